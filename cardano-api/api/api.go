@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -11,13 +12,20 @@ import (
 	"github.com/Ethernal-Tech/cardano-api/api/utils"
 	"github.com/Ethernal-Tech/cardano-api/common"
 	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-api/telemetry"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type cardanoAPIContextKey string
 
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
 const (
 	cardanoAPIBaseContextKey cardanoAPIContextKey = "cardanoApiBaseContextKey"
 	cardanoAPIConnContextKey cardanoAPIContextKey = "cardanoApiConnContextKey"
@@ -26,6 +34,7 @@ const (
 type APIImpl struct {
 	apiConfig core.APIConfig
 	handler   http.Handler
+	tlsConfig *tls.Config
 	server    *http.Server
 	logger    hclog.Logger
 
@@ -43,8 +52,10 @@ func NewAPI(
 	headersOk := handlers.AllowedHeaders(apiConfig.AllowedHeaders)
 	originsOk := handlers.AllowedOrigins(apiConfig.AllowedOrigins)
 	methodsOk := handlers.AllowedMethods(apiConfig.AllowedMethods)
+	exposedOk := handlers.ExposedHeaders([]string{requestIDHeader})
 
 	router := mux.NewRouter().StrictSlash(true)
+	maxRequestBodySize := apiConfig.GetMaxRequestBodySize()
 
 	for _, controller := range controllers {
 		controllerPathPrefix := controller.GetPathPrefix()
@@ -54,11 +65,16 @@ func NewAPI(
 			endpointPath := fmt.Sprintf("/%s/%s/%s", apiConfig.PathPrefix, controllerPathPrefix, endpoint.Path)
 
 			endpointHandler := endpoint.Handler
-			if !endpoint.NoAPIKeyAuth {
-				endpointHandler = withAPIKeyAuth(apiConfig, endpointHandler, logger)
+			if endpoint.AdminOnly {
+				endpointHandler = withAPIKeyAuth(
+					apiConfig.APIKeyHeader, apiConfig.AdminAPIKeys, false, endpointHandler, logger)
+			} else if !endpoint.NoAPIKeyAuth {
+				endpointHandler = withAPIKeyAuth(
+					apiConfig.APIKeyHeader, apiConfig.APIKeys, true, endpointHandler, logger)
 			}
 
-			endpointHandler = endpointWrapper(endpoint.Path, endpointHandler, logger)
+			endpointHandler = endpointWrapper(
+				endpoint.Path, withMaxRequestBodySize(maxRequestBodySize, endpointHandler), logger)
 
 			router.HandleFunc(endpointPath, endpointHandler).Methods(endpoint.Method)
 
@@ -66,11 +82,23 @@ func NewAPI(
 		}
 	}
 
-	handler := handlers.CORS(originsOk, headersOk, methodsOk)(router)
+	handler := handlers.CORS(originsOk, headersOk, methodsOk, exposedOk)(router)
+
+	var tlsConfig *tls.Config
+
+	if apiConfig.TLS.IsEnabled() {
+		var err error
+
+		tlsConfig, err = newTLSConfig(apiConfig.TLS, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tls config: %w", err)
+		}
+	}
 
 	return &APIImpl{
 		apiConfig: apiConfig,
 		handler:   handler,
+		tlsConfig: tlsConfig,
 		logger:    logger,
 	}, nil
 }
@@ -79,6 +107,7 @@ func (api *APIImpl) Start(ctx context.Context) {
 	api.server = &http.Server{
 		Addr:              fmt.Sprintf(":%d", api.apiConfig.Port),
 		Handler:           api.handler,
+		TLSConfig:         api.tlsConfig,
 		ReadHeaderTimeout: 3 * time.Second,
 		BaseContext: func(l net.Listener) context.Context {
 			return context.WithValue(ctx, cardanoAPIBaseContextKey, api.apiConfig.Port)
@@ -93,7 +122,15 @@ func (api *APIImpl) Start(ctx context.Context) {
 	err := common.RetryForever(ctx, 2*time.Second, func(context.Context) error {
 		api.logger.Debug("Trying to start api")
 
-		err := api.server.ListenAndServe()
+		var err error
+
+		if api.tlsConfig != nil {
+			// certificate is provided by tls config
+			err = api.server.ListenAndServeTLS("", "")
+		} else {
+			err = api.server.ListenAndServe()
+		}
+
 		if err == nil || err == http.ErrServerClosed {
 			return nil
 		}
@@ -139,17 +176,101 @@ func (api *APIImpl) Dispose() error {
 
 func endpointWrapper(path string, handler core.APIEndpointHandler, logger hclog.Logger) core.APIEndpointHandler {
 	return func(w http.ResponseWriter, r *http.Request) {
-		logger.Debug("endpoint called", "path", path, "url", r.URL)
+		startTime := time.Now()
+
+		requestID := r.Header.Get(requestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = common.NewRequestID()
+		}
+
+		ctx, span := telemetry.StartHTTPServerSpan(r, path)
+		span.SetAttributes(attribute.String("requestID", requestID))
+
+		requestInfo := &common.RequestInfo{}
+		ctx = common.ContextWithRequestInfo(common.ContextWithRequestID(ctx, requestID), requestInfo)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		recorder.Header().Set(requestIDHeader, requestID)
+
+		logger.Debug("endpoint called", "path", path, "url", r.URL, "requestID", requestID)
+		handler(recorder, r.WithContext(ctx))
+
+		span.SetAttributes(
+			attribute.String("apiKeyID", requestInfo.APIKeyID),
+			attribute.String("sourceChainId", requestInfo.SourceChainID),
+			attribute.String("destinationChainId", requestInfo.DestinationChainID))
+		telemetry.EndHTTPServerSpan(span, recorder.status)
+
+		logger.Info("access",
+			"requestID", requestID,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration", time.Since(startTime),
+			"apiKeyID", requestInfo.APIKeyID,
+			"sourceChainId", requestInfo.SourceChainID,
+			"destinationChainId", requestInfo.DestinationChainID,
+		)
+	}
+}
+
+// isValidRequestID prevents logging arbitrary client data as the request id
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, ch := range requestID {
+		isAlphaNum := (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+		if !isAlphaNum && ch != '-' && ch != '_' && ch != '.' && ch != ':' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// statusRecorder remembers the response status for the access log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Flush() {
+	if flusher, ok := sr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// withMaxRequestBodySize fails reading of the request body larger than maxSize
+func withMaxRequestBodySize(maxSize int64, handler core.APIEndpointHandler) core.APIEndpointHandler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
 		handler(w, r)
-		logger.Debug("endpoint call finished", "path", path, "url", r.URL)
 	}
 }
 
+// withAPIKeyAuth authorizes requests with one of the api keys.
+// If allowClientCert is true, verified tls client certificate is accepted instead of the api key
 func withAPIKeyAuth(
-	apiConfig core.APIConfig, handler core.APIEndpointHandler, logger hclog.Logger,
+	apiKeyHeader string, apiKeys []string, allowClientCert bool, handler core.APIEndpointHandler, logger hclog.Logger,
 ) core.APIEndpointHandler {
 	return func(w http.ResponseWriter, r *http.Request) {
-		apiKeyHeaderValue := r.Header.Get(apiConfig.APIKeyHeader)
+		if allowClientCert {
+			if clientCertID := getClientCertID(r); clientCertID != "" && r.Header.Get(apiKeyHeader) == "" {
+				handler(w, r.WithContext(common.ContextWithAPIKeyID(r.Context(), clientCertID)))
+
+				return
+			}
+		}
+
+		apiKeyHeaderValue := r.Header.Get(apiKeyHeader)
 		if apiKeyHeaderValue == "" {
 			utils.WriteUnauthorizedResponse(w, r, logger)
 
@@ -158,7 +279,7 @@ func withAPIKeyAuth(
 
 		authorized := false
 
-		for _, apiKey := range apiConfig.APIKeys {
+		for _, apiKey := range apiKeys {
 			if apiKey == apiKeyHeaderValue {
 				authorized = true
 
@@ -172,6 +293,6 @@ func withAPIKeyAuth(
 			return
 		}
 
-		handler(w, r)
+		handler(w, r.WithContext(common.ContextWithAPIKeyID(r.Context(), common.GetAPIKeyID(apiKeyHeaderValue))))
 	}
 }
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	"github.com/Ethernal-Tech/cardano-api/api/utils"
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointWrapper(t *testing.T) {
+	var (
+		logs      bytes.Buffer
+		requestID string
+	)
+
+	logger := hclog.New(&hclog.LoggerOptions{Output: &logs, JSONFormat: true, Level: hclog.Info})
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requestID = common.RequestIDFromContext(r.Context())
+
+		requestBody, ok := utils.DecodeModel[request.CreateBridgingTxRequest](w, r, logger)
+		if !ok {
+			return
+		}
+
+		utils.WriteResponse(w, r, http.StatusCreated, requestBody.Transactions, logger)
+	}
+
+	wrapped := endpointWrapper("CreateBridgingTx",
+		withAPIKeyAuth("x-api-key", []string{"key1"}, true, handler, logger), logger)
+
+	t.Run("request id is propagated and access log is written", func(t *testing.T) {
+		logs.Reset()
+
+		r := httptest.NewRequest(http.MethodPost, "/api/CardanoTx/CreateBridgingTx",
+			strings.NewReader(`{"sourceChainId":"prime","destinationChainId":"vector"}`))
+		r.Header.Set("x-api-key", "key1")
+		r.Header.Set(requestIDHeader, "web-api-123")
+
+		w := httptest.NewRecorder()
+		wrapped(w, r)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, "web-api-123", w.Header().Get(requestIDHeader))
+		require.Equal(t, "web-api-123", requestID)
+
+		var accessLog map[string]any
+
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &accessLog))
+		require.Equal(t, "access", accessLog["@message"])
+		require.Equal(t, "web-api-123", accessLog["requestID"])
+		require.Equal(t, http.MethodPost, accessLog["method"])
+		require.Equal(t, "/api/CardanoTx/CreateBridgingTx", accessLog["path"])
+		require.Equal(t, float64(http.StatusCreated), accessLog["status"])
+		require.Equal(t, common.GetAPIKeyID("key1"), accessLog["apiKeyID"])
+		require.Equal(t, "prime", accessLog["sourceChainId"])
+		require.Equal(t, "vector", accessLog["destinationChainId"])
+		require.Contains(t, accessLog, "duration")
+	})
+
+	t.Run("invalid request id is replaced and errors are logged with it", func(t *testing.T) {
+		logs.Reset()
+
+		r := httptest.NewRequest(http.MethodPost, "/api/CardanoTx/CreateBridgingTx", strings.NewReader(`{`))
+		r.Header.Set("x-api-key", "key1")
+		r.Header.Set(requestIDHeader, "bad id\nwith new line")
+
+		w := httptest.NewRecorder()
+		wrapped(w, r)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Len(t, w.Header().Get(requestIDHeader), 32)
+		require.Equal(t, w.Header().Get(requestIDHeader), requestID)
+
+		lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+		require.Len(t, lines, 2)
+
+		for _, line := range lines {
+			require.Contains(t, line, `"requestID":"`+requestID+`"`)
+		}
+
+		require.Contains(t, lines[1], `"status":400`)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		logs.Reset()
+
+		r := httptest.NewRequest(http.MethodPost, "/api/CardanoTx/CreateBridgingTx", strings.NewReader(`{}`))
+
+		w := httptest.NewRecorder()
+		wrapped(w, r)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.NotEmpty(t, w.Header().Get(requestIDHeader))
+		require.Contains(t, logs.String(), `"apiKeyID":""`)
+	})
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	"github.com/Ethernal-Tech/cardano-api/api/utils"
+	cardanotx "github.com/Ethernal-Tech/cardano-api/cardano"
+)
+
+const (
+	// same values as the defaults of sendtx.TxSender
+	amountModeMaxInputsPerTx = 50
+	amountModePotentialFee   = 400_000
+	amountModeMaxIterations  = 5
+)
+
+func isValidAmountMode(mode string) bool {
+	return mode == "" || mode == request.BridgingTxModeSendMax || mode == request.BridgingTxModeNetAmount
+}
+
+// amountModeFees are the fees known before the tx is built
+type amountModeFees struct {
+	bridgingFee   uint64
+	potentialFee  uint64
+	changeMinUtxo uint64
+}
+
+// applyAmountMode calculates the amount of the only receiver for sendMax and netAmount modes.
+// The request must already be validated and filled out
+func (c *CardanoTxControllerImpl) applyAmountMode(
+	ctx context.Context, requestBody *request.CreateBridgingTxRequest,
+) error {
+	if requestBody.Mode == "" {
+		return nil
+	}
+
+	cardanoSrcConfig, _ := c.appConfig.GetChainConfig(requestBody.SourceChainID)
+	cardanoDestConfig, _ := c.appConfig.GetChainConfig(requestBody.DestinationChainID)
+	settings := c.appConfig.GetBridgingSettings()
+
+	fees := amountModeFees{
+		bridgingFee:   requestBody.BridgingFee,
+		potentialFee:  cardanoSrcConfig.ChainSpecific.PotentialFee,
+		changeMinUtxo: settings.MinUtxoChainValue[requestBody.SourceChainID],
+	}
+	if fees.potentialFee == 0 {
+		fees.potentialFee = amountModePotentialFee
+	}
+
+	// do not change the transactions slice of the caller
+	requestBody.Transactions = slices.Clone(requestBody.Transactions)
+
+	spendable, err := c.getSpendableAmount(ctx, *requestBody)
+	if err != nil {
+		return err
+	}
+
+	amount, err := getAmountForMode(
+		requestBody.Mode, requestBody.Transactions[0].Amount, spendable, fees,
+		func(amount uint64) (*cardanotx.BridgingTxFeeBreakdown, error) {
+			return c.calculateTxFeeForAmount(ctx, *requestBody, amount)
+		})
+	if err != nil {
+		return fmt.Errorf("failed to calculate amount for %s mode: %w", requestBody.Mode, err)
+	}
+
+	if settings.MaxAmountAllowedToBridge != nil && settings.MaxAmountAllowedToBridge.Sign() == 1 &&
+		new(big.Int).SetUint64(amount).Cmp(settings.MaxAmountAllowedToBridge) == 1 {
+		if requestBody.Mode != request.BridgingTxModeSendMax {
+			return fmt.Errorf("amount %d greater than maximum allowed: %v", amount, settings.MaxAmountAllowedToBridge)
+		}
+
+		amount = settings.MaxAmountAllowedToBridge.Uint64()
+	}
+
+	if cardanoDestConfig != nil && amount < settings.MinValueToBridge {
+		return fmt.Errorf("amount %d after fees is below minimum value %d", amount, settings.MinValueToBridge)
+	}
+
+	requestBody.Transactions[0].Amount = amount
+
+	return nil
+}
+
+// getAmountForMode returns the amount of the only receiver. For sendMax it is the largest amount whose required funds
+// fit into spendable. For netAmount the requested amount is the total debit of the sender, so it is the largest amount
+// whose debit (amount, tx fee and bridging fee) does not exceed the requested one. Spendable funds must also cover
+// the min utxo of the change
+func getAmountForMode(
+	mode string, requestedAmount, spendable uint64, fees amountModeFees,
+	getFeeBreakdown func(amount uint64) (*cardanotx.BridgingTxFeeBreakdown, error),
+) (uint64, error) {
+	// tx inputs are selected only if they cover outputs, potential fee and min change
+	getRequiredFunds := func(amount uint64) (uint64, error) {
+		feeBreakdown, err := getFeeBreakdown(amount)
+		if err != nil {
+			return 0, err
+		}
+
+		return feeBreakdown.TotalDebit - feeBreakdown.SourceTxFee +
+			fees.potentialFee + feeBreakdown.ChangeMinUtxoAmount, nil
+	}
+
+	switch mode {
+	case request.BridgingTxModeSendMax:
+		return cardanotx.FindAmountForDebit(
+			spendable, fees.bridgingFee+fees.potentialFee+fees.changeMinUtxo, amountModeMaxIterations,
+			getRequiredFunds)
+	case request.BridgingTxModeNetAmount:
+		amount, err := cardanotx.FindAmountForDebit(
+			requestedAmount, fees.bridgingFee+fees.potentialFee, amountModeMaxIterations,
+			func(amount uint64) (uint64, error) {
+				feeBreakdown, err := getFeeBreakdown(amount)
+				if err != nil {
+					return 0, err
+				}
+
+				return feeBreakdown.TotalDebit, nil
+			})
+		if err != nil {
+			return 0, err
+		}
+
+		requiredFunds, err := getRequiredFunds(amount)
+		if err != nil {
+			return 0, err
+		}
+
+		if requiredFunds > spendable {
+			return 0, fmt.Errorf("debit %d with min change requires %d but only %d is spendable",
+				requestedAmount, requiredFunds, spendable)
+		}
+
+		return amount, nil
+	default:
+		return 0, fmt.Errorf("unknown mode: %s", mode)
+	}
+}
+
+// getSpendableAmount returns the amount which can be spent by a single tx from the not reserved utxos of the sender
+func (c *CardanoTxControllerImpl) getSpendableAmount(
+	ctx context.Context, requestBody request.CreateBridgingTxRequest,
+) (uint64, error) {
+	cardanoSrcConfig, _ := c.appConfig.GetChainConfig(requestBody.SourceChainID)
+
+	txProvider, err := cardanoSrcConfig.ChainSpecific.CreateTxProvider()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create tx provider: %w", err)
+	}
+
+	defer txProvider.Dispose()
+
+	utxos, err := txProvider.GetUtxos(ctx, requestBody.SenderAddr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve utxos: %w", err)
+	}
+
+	utxosTransformer, err := utils.GetUtxosTransformer(
+		ctx, requestBody, c.appConfig, c.usedUtxoCacher, c.reservationStore)
+	if err != nil {
+		return 0, err
+	}
+
+	if utxosTransformer != nil {
+		utxos = utxosTransformer.TransformUtxos(utxos)
+	}
+
+	return cardanotx.GetSpendableAmount(utxos, amountModeMaxInputsPerTx), nil
+}
+
+func (c *CardanoTxControllerImpl) calculateTxFeeForAmount(
+	ctx context.Context, requestBody request.CreateBridgingTxRequest, amount uint64,
+) (*cardanotx.BridgingTxFeeBreakdown, error) {
+	requestBody.Transactions = []request.CreateBridgingTxTransactionRequest{{
+		Addr:   requestBody.Transactions[0].Addr,
+		Amount: amount,
+	}}
+
+	return c.calculateTxFee(ctx, requestBody)
+}
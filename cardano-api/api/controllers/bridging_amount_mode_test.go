@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	cardanotx "github.com/Ethernal-Tech/cardano-api/cardano"
+	"github.com/Ethernal-Tech/cardano-infrastructure/sendtx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAmountForMode(t *testing.T) {
+	const (
+		receiverAddr  = "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw"
+		bridgingFee   = 1_000_000
+		sourceTxFee   = 155381 + 44*450
+		potentialFee  = 300_000
+		changeMinUtxo = 1_000_000
+	)
+
+	var lastBreakdown *cardanotx.BridgingTxFeeBreakdown
+
+	fees := amountModeFees{bridgingFee: bridgingFee, potentialFee: potentialFee, changeMinUtxo: changeMinUtxo}
+	getFeeBreakdown := func(amount uint64) (*cardanotx.BridgingTxFeeBreakdown, error) {
+		breakdown, err := cardanotx.NewBridgingTxFeeBreakdown(
+			&sendtx.TxFeeInfo{Fee: sourceTxFee, ChangeMinUtxoAmount: changeMinUtxo},
+			&sendtx.BridgingRequestMetadata{
+				Transactions: []sendtx.BridgingRequestMetadataTransaction{
+					{Address: sendtx.AddrToMetaDataAddr(receiverAddr), Amount: amount},
+				},
+				BridgingFee: bridgingFee,
+			},
+			bridgingFee, []byte(`{"txFeeFixed":155381,"txFeePerByte":44}`))
+		if err != nil {
+			return nil, err
+		}
+
+		lastBreakdown = breakdown
+
+		return breakdown, nil
+	}
+
+	t.Run("netAmount requested amount is the total debit", func(t *testing.T) {
+		amount, err := getAmountForMode(
+			request.BridgingTxModeNetAmount, 5_000_000, 10_000_000, fees, getFeeBreakdown)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5_000_000-bridgingFee-sourceTxFee), amount)
+
+		_, err = getFeeBreakdown(amount)
+		require.NoError(t, err)
+		require.Len(t, lastBreakdown.Receivers, 1)
+		require.Equal(t, amount, lastBreakdown.Receivers[0].Amount)
+		require.Equal(t, uint64(5_000_000), lastBreakdown.TotalDebit)
+	})
+
+	t.Run("netAmount debit and min change are not covered", func(t *testing.T) {
+		_, err := getAmountForMode(
+			request.BridgingTxModeNetAmount, 5_000_000, 5_500_000, fees, getFeeBreakdown)
+		require.ErrorContains(t, err, "debit 5000000 with min change requires 6124819 but only 5500000 is spendable")
+	})
+
+	t.Run("netAmount fees are greater than the amount", func(t *testing.T) {
+		_, err := getAmountForMode(
+			request.BridgingTxModeNetAmount, 1_000_000, 10_000_000, fees, getFeeBreakdown)
+		require.ErrorContains(t, err, "fees of 1300000 are greater than available 1000000")
+	})
+
+	t.Run("sendMax", func(t *testing.T) {
+		amount, err := getAmountForMode(request.BridgingTxModeSendMax, 0, 10_000_000, fees, getFeeBreakdown)
+		require.NoError(t, err)
+		require.Equal(t, uint64(10_000_000-bridgingFee-potentialFee-changeMinUtxo), amount)
+		require.Equal(t, amount, lastBreakdown.Receivers[0].Amount)
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := getAmountForMode("all", 5_000_000, 10_000_000, fees, getFeeBreakdown)
+		require.ErrorContains(t, err, "unknown mode: all")
+	})
+}
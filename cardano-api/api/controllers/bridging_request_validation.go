@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	"github.com/Ethernal-Tech/cardano-api/api/utils"
+	cardanotx "github.com/Ethernal-Tech/cardano-api/cardano"
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	goEthCommon "github.com/ethereum/go-ethereum/common"
+)
+
+type bridgingRequestIssues []response.ValidationIssueResponse
+
+func (issues *bridgingRequestIssues) add(field string, format string, args ...any) {
+	*issues = append(*issues, response.ValidationIssueResponse{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (issues *bridgingRequestIssues) addForReceiver(idx int, field string, format string, args ...any) {
+	*issues = append(*issues, response.ValidationIssueResponse{
+		Field:         fmt.Sprintf("transactions[%d].%s", idx, field),
+		ReceiverIndex: &idx,
+		Message:       fmt.Sprintf(format, args...),
+	})
+}
+
+// toError returns error with all the issues
+func (issues bridgingRequestIssues) toError() error {
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = fmt.Sprintf("%s: %s", issue.Field, issue.Message)
+	}
+
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// isFeeReceiver returns true if the receiver is the fee address of the destination chain
+func isFeeReceiver(addr string, cardanoDestConfig *core.CardanoChainConfig) bool {
+	if cardanoDestConfig != nil {
+		return addr == cardanoDestConfig.BridgingAddresses.FeeAddress
+	}
+
+	return addr == common.EthZeroAddr
+}
+
+// getBridgingRequestIssues runs all the checks of the bridging request without stopping at the first failed one.
+// The sender balance is checked only if the sender address is valid
+func (c *CardanoTxControllerImpl) getBridgingRequestIssues(
+	ctx context.Context, requestBody request.CreateBridgingTxRequest,
+) (bridgingRequestIssues, error) {
+	issues := c.getBridgingRequestStaticIssues(requestBody)
+
+	for _, issue := range issues {
+		if issue.Field == "sourceChainId" || issue.Field == "senderAddr" {
+			return issues, nil
+		}
+	}
+
+	balanceIssues, err := c.getSenderBalanceIssues(ctx, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(issues, balanceIssues...), nil
+}
+
+// getBridgingRequestStaticIssues runs the checks which do not need the sender utxos.
+// Both ValidateBridgingRequest and the endpoints which create the bridging tx use it, so they can not drift apart
+func (c *CardanoTxControllerImpl) getBridgingRequestStaticIssues(
+	requestBody request.CreateBridgingTxRequest,
+) (issues bridgingRequestIssues) {
+	settings := c.appConfig.GetBridgingSettings()
+
+	cardanoSrcConfig, _ := c.appConfig.GetChainConfig(requestBody.SourceChainID)
+	if cardanoSrcConfig == nil {
+		issues.add("sourceChainId", "origin chain not registered: %v", requestBody.SourceChainID)
+	} else if !cardanotx.IsValidOutputAddress(requestBody.SenderAddr, cardanoSrcConfig.NetworkID) {
+		issues.add("senderAddr", "invalid sender address for origin chain: %s", requestBody.SenderAddr)
+	}
+
+	cardanoDestConfig, ethDestConfig := c.appConfig.GetChainConfig(requestBody.DestinationChainID)
+	if cardanoDestConfig == nil && ethDestConfig == nil {
+		issues.add("destinationChainId", "destination chain not registered: %v", requestBody.DestinationChainID)
+	}
+
+	isDestRegistered := cardanoDestConfig != nil || ethDestConfig != nil
+
+	if cardanoSrcConfig != nil && isDestRegistered && len(settings.AllowedDirections) > 0 &&
+		!slices.Contains(settings.AllowedDirections[requestBody.SourceChainID], requestBody.DestinationChainID) {
+		issues.add("destinationChainId", "bridging from %s to %s is not allowed",
+			requestBody.SourceChainID, requestBody.DestinationChainID)
+	}
+
+	if requestBody.RefundAddr != "" {
+		if cardanoSrcConfig != nil && !cardanotx.IsValidOutputAddress(requestBody.RefundAddr, cardanoSrcConfig.NetworkID) {
+			issues.add("refundAddr", "invalid refund address for origin chain: %s", requestBody.RefundAddr)
+		}
+
+		if !settings.IsRefundAddrSupported() {
+			issues.add("refundAddr", "refund address is not supported by the oracle")
+		}
+	}
+
+	if requestBody.UseChaining && !utils.UseUtxoCache(requestBody, c.appConfig) {
+		issues.add("useChaining", "tx chaining requires a valid utxo cache key")
+	}
+
+	if requestBody.UseChaining && c.reservationStore != nil {
+		issues.add("useChaining", "tx chaining is not supported with shared utxo reservation store")
+	}
+
+	if requestBody.RequestQuote || requestBody.QuoteID != "" {
+		if c.feeQuoteStore == nil {
+			issues.add("requestQuote", "fee quotes are not enabled")
+		} else if !utils.UseUtxoCache(requestBody, c.appConfig) {
+			issues.add("utxoCacheKey", "fee quote requires a valid utxo cache key")
+		}
+	}
+
+	if requestBody.SenderAddrPolicyScript != nil {
+		err := cardanotx.ValidatePolicyScriptForAddress(requestBody.SenderAddr, *requestBody.SenderAddrPolicyScript)
+		if err != nil {
+			issues.add("senderAddrPolicyScript", "invalid sender address policy script: %v", err)
+		}
+	}
+
+	if len(requestBody.Transactions) == 0 {
+		issues.add("transactions", "at least one receiver is required")
+	} else if len(requestBody.Transactions) > settings.MaxReceiversPerBridgingRequest {
+		issues.add("transactions", "number of receivers greater than maximum allowed - no: %v, max: %v",
+			len(requestBody.Transactions), settings.MaxReceiversPerBridgingRequest)
+	}
+
+	if !isValidAmountMode(requestBody.Mode) {
+		issues.add("mode", "unknown mode: %s", requestBody.Mode)
+	} else if requestBody.Mode != "" && len(requestBody.Transactions) != 1 {
+		issues.add("mode", "%s mode requires exactly one receiver", requestBody.Mode)
+	}
+
+	receiverAmountSum := big.NewInt(0)
+	feeAmounts := []uint64{requestBody.BridgingFee}
+
+	for i, receiver := range requestBody.Transactions {
+		if cardanoDestConfig != nil {
+			// amount of sendMax mode is calculated from the sender balance
+			if requestBody.Mode != request.BridgingTxModeSendMax && receiver.Amount < settings.MinValueToBridge {
+				issues.addForReceiver(i, "amount", "amount %d is below minimum value %d",
+					receiver.Amount, settings.MinValueToBridge)
+			}
+
+			if !cardanotx.IsValidOutputAddress(receiver.Addr, cardanoDestConfig.NetworkID) {
+				issues.addForReceiver(i, "addr", "invalid receiver address for destination chain: %s", receiver.Addr)
+
+				continue
+			}
+		} else if ethDestConfig != nil {
+			if !goEthCommon.IsHexAddress(receiver.Addr) {
+				issues.addForReceiver(i, "addr", "invalid receiver address for destination chain: %s", receiver.Addr)
+
+				continue
+			}
+		} else {
+			continue
+		}
+
+		if isFeeReceiver(receiver.Addr, cardanoDestConfig) {
+			feeAmounts = append(feeAmounts, receiver.Amount)
+
+			if requestBody.Mode != "" {
+				issues.addForReceiver(i, "addr", "receiver of %s mode can not be the fee address", requestBody.Mode)
+			}
+		} else {
+			receiverAmountSum.Add(receiverAmountSum, new(big.Int).SetUint64(receiver.Amount))
+		}
+	}
+
+	if settings.MaxAmountAllowedToBridge != nil && settings.MaxAmountAllowedToBridge.Sign() == 1 &&
+		receiverAmountSum.Cmp(settings.MaxAmountAllowedToBridge) == 1 {
+		issues.add("transactions", "sum of receiver amounts %v greater than maximum allowed: %v",
+			receiverAmountSum, settings.MaxAmountAllowedToBridge)
+	}
+
+	if isDestRegistered {
+		bridgingFee, ok := sumUint64(feeAmounts...)
+
+		minFee, found := settings.MinChainFeeForBridging[requestBody.DestinationChainID]
+		if !ok {
+			issues.add("bridgingFee", "sum of bridging fee and fee address amounts overflows")
+		} else if !found {
+			issues.add("destinationChainId", "no minimal fee for chain: %s", requestBody.DestinationChainID)
+		} else if bridgingFee != 0 && bridgingFee < minFee {
+			issues.add("bridgingFee", "bridging fee %d is less than minimum: %d", bridgingFee, minFee)
+		}
+	}
+
+	return issues
+}
+
+// getSenderBalanceIssues checks if the sender has enough not reserved funds for receivers, bridging fee and tx fee
+func (c *CardanoTxControllerImpl) getSenderBalanceIssues(
+	ctx context.Context, requestBody request.CreateBridgingTxRequest,
+) (issues bridgingRequestIssues, err error) {
+	cardanoSrcConfig, _ := c.appConfig.GetChainConfig(requestBody.SourceChainID)
+
+	required, ok := c.getRequiredBalance(requestBody)
+	if !ok {
+		issues.add("transactions", "sum of amounts and fees overflows")
+
+		return issues, nil
+	}
+
+	txProvider, err := cardanoSrcConfig.ChainSpecific.CreateTxProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tx provider: %w", err)
+	}
+
+	defer txProvider.Dispose()
+
+	utxos, err := txProvider.GetUtxos(ctx, requestBody.SenderAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve utxos: %w", err)
+	}
+
+	utxosTransformer, err := utils.GetUtxosTransformer(
+		ctx, requestBody, c.appConfig, c.usedUtxoCacher, c.reservationStore)
+	if err != nil {
+		return nil, err
+	}
+
+	if utxosTransformer != nil {
+		utxos = utxosTransformer.TransformUtxos(utxos)
+	}
+
+	available := uint64(0)
+	for _, utxo := range utxos {
+		available += utxo.Amount
+	}
+
+	if available < required {
+		issues.add("senderAddr", "insufficient balance: available %d, required at least %d", available, required)
+	}
+
+	return issues, nil
+}
+
+// getRequiredBalance returns the sum of receiver amounts, bridging fee and potential tx fee of the request
+// filled out the same way as for tx creation. Returns false if the sum overflows
+func (c *CardanoTxControllerImpl) getRequiredBalance(requestBody request.CreateBridgingTxRequest) (uint64, bool) {
+	cardanoSrcConfig, _ := c.appConfig.GetChainConfig(requestBody.SourceChainID)
+
+	c.fillOutCreateBridgingTxRequest(&requestBody)
+
+	values := []uint64{requestBody.BridgingFee, cardanoSrcConfig.ChainSpecific.PotentialFee}
+	for _, receiver := range requestBody.Transactions {
+		values = append(values, receiver.Amount)
+	}
+
+	return sumUint64(values...)
+}
+
+// sumUint64 returns the sum of the values and false if it overflows
+func sumUint64(values ...uint64) (uint64, bool) {
+	sum, carry := uint64(0), uint64(0)
+
+	for _, value := range values {
+		var carryOut uint64
+
+		sum, carryOut = bits.Add64(sum, value, 0)
+		carry |= carryOut
+	}
+
+	return sum, carry == 0
+}
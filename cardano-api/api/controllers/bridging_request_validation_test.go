@@ -0,0 +1,402 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	utxotransformer "github.com/Ethernal-Tech/cardano-api/api/utxo_transformer"
+	cardanotx "github.com/Ethernal-Tech/cardano-api/cardano"
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBridgingRequestStaticIssues(t *testing.T) {
+	const (
+		testnetAddr = "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw"
+		mainnetAddr = "addr1w8nv7cp7revdt70yuc96z4ke9pasa70grc5clhyf7q70f4spev3dn"
+		feeAddr     = "addr_test1vruaegs6djpxaj9vkn8njh9uys63jdaluetqkf5r4w95zhc8sctxa"
+	)
+
+	appConfig := &core.AppConfig{
+		CardanoChains: map[string]*core.CardanoChainConfig{
+			common.ChainIDStrPrime: {
+				NetworkID: wallet.TestNetNetwork,
+				IsEnabled: true,
+			},
+			common.ChainIDStrVector: {
+				NetworkID:         wallet.TestNetNetwork,
+				BridgingAddresses: core.BridgingAddresses{FeeAddress: feeAddr},
+				IsEnabled:         true,
+			},
+		},
+		EthChains: map[string]*core.EthChainConfig{
+			common.ChainIDStrNexus: {IsEnabled: true},
+		},
+		BridgingSettings: core.BridgingSettings{
+			MinChainFeeForBridging: map[string]uint64{
+				common.ChainIDStrVector: 1_000_000,
+				common.ChainIDStrNexus:  1_000_000,
+			},
+			MinValueToBridge:               1_000_000,
+			MaxAmountAllowedToBridge:       big.NewInt(10_000_000),
+			MaxReceiversPerBridgingRequest: 3,
+			AllowedDirections: map[string][]string{
+				common.ChainIDStrPrime: {common.ChainIDStrVector, common.ChainIDStrNexus},
+			},
+		},
+	}
+	controller := NewCardanoTxController(
+		appConfig, utxotransformer.NewUsedUtxoCacher(time.Minute), nil, nil, hclog.NewNullLogger(), nil, nil, nil)
+
+	validRequest := func() request.CreateBridgingTxRequest {
+		return request.CreateBridgingTxRequest{
+			SenderAddr:         testnetAddr,
+			SourceChainID:      common.ChainIDStrPrime,
+			DestinationChainID: common.ChainIDStrVector,
+			Transactions: []request.CreateBridgingTxTransactionRequest{
+				{Addr: testnetAddr, Amount: 2_000_000},
+			},
+		}
+	}
+
+	t.Run("valid request", func(t *testing.T) {
+		require.Empty(t, controller.getBridgingRequestStaticIssues(validRequest()))
+	})
+
+	t.Run("all receiver issues are reported", func(t *testing.T) {
+		requestBody := validRequest()
+		requestBody.Transactions = []request.CreateBridgingTxTransactionRequest{
+			{Addr: testnetAddr, Amount: 100},
+			{Addr: mainnetAddr, Amount: 2_000_000},
+			{Addr: testnetAddr, Amount: 2_000_000},
+			{Addr: "invalid", Amount: 10},
+		}
+
+		issues := controller.getBridgingRequestStaticIssues(requestBody)
+
+		fields := make([]string, len(issues))
+		for i, issue := range issues {
+			fields[i] = issue.Field
+		}
+
+		require.Equal(t, []string{
+			"transactions",
+			"transactions[0].amount",
+			"transactions[1].addr",
+			"transactions[3].amount",
+			"transactions[3].addr",
+		}, fields)
+		require.Nil(t, issues[0].ReceiverIndex)
+		require.Equal(t, 0, *issues[1].ReceiverIndex)
+		require.Equal(t, 1, *issues[2].ReceiverIndex)
+		require.Equal(t, 3, *issues[4].ReceiverIndex)
+	})
+
+	t.Run("chain, sender, fee and max amount issues", func(t *testing.T) {
+		requestBody := validRequest()
+		requestBody.SenderAddr = mainnetAddr
+		requestBody.BridgingFee = 10
+		requestBody.Transactions = []request.CreateBridgingTxTransactionRequest{
+			{Addr: testnetAddr, Amount: 6_000_000},
+			{Addr: testnetAddr, Amount: 6_000_000},
+		}
+
+		issues := controller.getBridgingRequestStaticIssues(requestBody)
+		require.Len(t, issues, 3)
+		require.Equal(t, "senderAddr", issues[0].Field)
+		require.Equal(t, "transactions", issues[1].Field)
+		require.Contains(t, issues[1].Message, "greater than maximum allowed")
+		require.Equal(t, "bridgingFee", issues[2].Field)
+
+		requestBody = validRequest()
+		requestBody.SourceChainID = "unknown"
+		requestBody.DestinationChainID = "unknown"
+
+		issues = controller.getBridgingRequestStaticIssues(requestBody)
+		require.Len(t, issues, 2)
+		require.Equal(t, "sourceChainId", issues[0].Field)
+		require.Equal(t, "destinationChainId", issues[1].Field)
+	})
+
+	t.Run("direction not allowed", func(t *testing.T) {
+		requestBody := validRequest()
+		requestBody.SourceChainID = common.ChainIDStrVector
+		requestBody.DestinationChainID = common.ChainIDStrPrime
+
+		issues := controller.getBridgingRequestStaticIssues(requestBody)
+		require.Len(t, issues, 2)
+		require.Equal(t, "destinationChainId", issues[0].Field)
+		require.Contains(t, issues[0].Message, "is not allowed")
+		require.Contains(t, issues[1].Message, "no minimal fee")
+
+		// the endpoints which create the tx reject the request for the same issues
+		require.ErrorContains(t, controller.validateAndFillOutCreateBridgingTxRequest(&requestBody),
+			"destinationChainId: bridging from vector to prime is not allowed")
+	})
+
+	t.Run("fee address amount is counted as bridging fee", func(t *testing.T) {
+		requestBody := validRequest()
+		requestBody.Transactions = append(requestBody.Transactions,
+			request.CreateBridgingTxTransactionRequest{Addr: feeAddr, Amount: 1_100_000})
+
+		require.Empty(t, controller.getBridgingRequestStaticIssues(requestBody))
+
+		filledOut := requestBody
+		require.NoError(t, controller.validateAndFillOutCreateBridgingTxRequest(&filledOut))
+		require.Equal(t, uint64(1_100_000), filledOut.BridgingFee)
+		require.Equal(t, requestBody.Transactions[:1], filledOut.Transactions)
+
+		requestBody.Mode = request.BridgingTxModeNetAmount
+		requestBody.Transactions = requestBody.Transactions[1:]
+		issues := controller.getBridgingRequestStaticIssues(requestBody)
+		require.Len(t, issues, 1)
+		require.Equal(t, "transactions[0].addr", issues[0].Field)
+		require.ErrorContains(t, controller.validateAndFillOutCreateBridgingTxRequest(&requestBody),
+			"can not be the fee address")
+
+		requestBody = validRequest()
+		requestBody.Transactions = append(requestBody.Transactions,
+			request.CreateBridgingTxTransactionRequest{Addr: feeAddr, Amount: 1_100_000})
+		requestBody.BridgingFee = 0
+		requestBody.Transactions[1].Amount = 1_000
+		issues = controller.getBridgingRequestStaticIssues(requestBody)
+		require.Len(t, issues, 2)
+		require.Equal(t, "transactions[1].amount", issues[0].Field)
+		require.Equal(t, "bridgingFee", issues[1].Field)
+	})
+
+	t.Run("refund address", func(t *testing.T) {
+		requestBody := validRequest()
+		requestBody.RefundAddr = mainnetAddr
+
+		issues := controller.getBridgingRequestStaticIssues(requestBody)
+		require.Len(t, issues, 2)
+		require.Equal(t, "refundAddr", issues[0].Field)
+		require.Contains(t, issues[0].Message, "invalid refund address")
+		require.Contains(t, issues[1].Message, "not supported by the oracle")
+
+		appConfig.BridgingSettings.MetadataVersion = common.MetadataVersion2
+		defer func() { appConfig.BridgingSettings.MetadataVersion = 0 }()
+
+		requestBody.RefundAddr = testnetAddr
+
+		require.Empty(t, controller.getBridgingRequestStaticIssues(requestBody))
+		require.NoError(t, controller.validateAndFillOutCreateBridgingTxRequest(&requestBody))
+	})
+
+	t.Run("amount modes", func(t *testing.T) {
+		requestBody := validRequest()
+		requestBody.Mode = request.BridgingTxModeSendMax
+		requestBody.Transactions[0].Amount = 0
+
+		require.Empty(t, controller.getBridgingRequestStaticIssues(requestBody))
+		require.NoError(t, controller.validateAndFillOutCreateBridgingTxRequest(&requestBody))
+		require.Equal(t, uint64(1_000_000), requestBody.Transactions[0].Amount)
+
+		requestBody = validRequest()
+		requestBody.Mode = request.BridgingTxModeNetAmount
+		requestBody.Transactions = append(requestBody.Transactions, requestBody.Transactions[0])
+
+		issues := controller.getBridgingRequestStaticIssues(requestBody)
+		require.Len(t, issues, 1)
+		require.Equal(t, "mode", issues[0].Field)
+		require.ErrorContains(t, controller.validateAndFillOutCreateBridgingTxRequest(&requestBody),
+			"netAmount mode requires exactly one receiver")
+
+		requestBody = validRequest()
+		requestBody.Mode = "all"
+
+		issues = controller.getBridgingRequestStaticIssues(requestBody)
+		require.Len(t, issues, 1)
+		require.Equal(t, "unknown mode: all", issues[0].Message)
+	})
+}
+
+func TestRefundAddrSupportedByOracleSettings(t *testing.T) {
+	const testnetAddr = "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw"
+
+	oracleServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"minChainFeeForBridging": {"vector": 1000000},
+			"minValueToBridge": 1000000,
+			"maxAmountAllowedToBridge": "10000000",
+			"maxReceiversPerBridgingRequest": 3,
+			"allowedDirections": {"prime": ["vector"]},
+			"metadataVersion": 2
+		}`))
+	}))
+	defer oracleServer.Close()
+
+	appConfig := &core.AppConfig{
+		CardanoChains: map[string]*core.CardanoChainConfig{
+			common.ChainIDStrPrime: {
+				NetworkID:     wallet.TestNetNetwork,
+				IsEnabled:     true,
+				ChainSpecific: &cardanotx.CardanoChainConfig{},
+			},
+			common.ChainIDStrVector: {
+				NetworkID:     wallet.TestNetNetwork,
+				IsEnabled:     true,
+				ChainSpecific: &cardanotx.CardanoChainConfig{},
+			},
+		},
+		OracleAPI: core.OracleAPISettings{URL: oracleServer.URL, Timeout: time.Second, MaxAttempts: 1},
+	}
+	require.NoError(t, appConfig.FillOut(context.Background(), hclog.NewNullLogger()))
+
+	controller := NewCardanoTxController(
+		appConfig, utxotransformer.NewUsedUtxoCacher(time.Minute), nil, nil, hclog.NewNullLogger(), nil, nil, nil)
+
+	recorder := httptest.NewRecorder()
+	controller.getSettings(recorder, httptest.NewRequest(http.MethodGet, "/api/CardanoTx/GetSettings", nil))
+
+	var settings response.SettingsResponse
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &settings))
+	require.True(t, settings.RefundAddrSupported)
+	require.Equal(t, common.MetadataVersion2, settings.BridgingSettings.MetadataVersion)
+
+	requestBody := request.CreateBridgingTxRequest{
+		SenderAddr:         testnetAddr,
+		SourceChainID:      common.ChainIDStrPrime,
+		DestinationChainID: common.ChainIDStrVector,
+		RefundAddr:         testnetAddr,
+		Transactions: []request.CreateBridgingTxTransactionRequest{
+			{Addr: testnetAddr, Amount: 2_000_000},
+		},
+	}
+
+	require.Empty(t, controller.getBridgingRequestStaticIssues(requestBody))
+}
+
+func TestGetRequiredBalance(t *testing.T) {
+	const (
+		testnetAddr = "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw"
+		feeAddr     = "addr_test1vruaegs6djpxaj9vkn8njh9uys63jdaluetqkf5r4w95zhc8sctxa"
+	)
+
+	appConfig := &core.AppConfig{
+		CardanoChains: map[string]*core.CardanoChainConfig{
+			common.ChainIDStrPrime: {
+				NetworkID:     wallet.TestNetNetwork,
+				IsEnabled:     true,
+				ChainSpecific: &cardanotx.CardanoChainConfig{PotentialFee: 300_000},
+			},
+			common.ChainIDStrVector: {
+				NetworkID:         wallet.TestNetNetwork,
+				BridgingAddresses: core.BridgingAddresses{FeeAddress: feeAddr},
+				IsEnabled:         true,
+			},
+		},
+		BridgingSettings: core.BridgingSettings{
+			MinChainFeeForBridging:         map[string]uint64{common.ChainIDStrVector: 1_000_000},
+			MinValueToBridge:               1_000_000,
+			MaxReceiversPerBridgingRequest: 3,
+		},
+	}
+	controller := NewCardanoTxController(
+		appConfig, utxotransformer.NewUsedUtxoCacher(time.Minute), nil, nil, hclog.NewNullLogger(), nil, nil, nil)
+
+	testCases := []struct {
+		name        string
+		bridgingFee uint64
+		receivers   []request.CreateBridgingTxTransactionRequest
+		required    uint64
+		overflows   bool
+	}{
+		{
+			name:      "default min fee",
+			receivers: []request.CreateBridgingTxTransactionRequest{{Addr: testnetAddr, Amount: 2_000_000}},
+			required:  2_000_000 + 1_000_000 + 300_000,
+		},
+		{
+			name:        "requested fee",
+			bridgingFee: 1_500_000,
+			receivers:   []request.CreateBridgingTxTransactionRequest{{Addr: testnetAddr, Amount: 2_000_000}},
+			required:    2_000_000 + 1_500_000 + 300_000,
+		},
+		{
+			name: "fee address receiver without bridging fee",
+			receivers: []request.CreateBridgingTxTransactionRequest{
+				{Addr: testnetAddr, Amount: 2_000_000},
+				{Addr: feeAddr, Amount: 1_200_000},
+			},
+			// fee address amount is the bridging fee, min fee is not added to it
+			required: 2_000_000 + 1_200_000 + 300_000,
+		},
+		{
+			name:        "fee address receiver with bridging fee",
+			bridgingFee: 500_000,
+			receivers: []request.CreateBridgingTxTransactionRequest{
+				{Addr: testnetAddr, Amount: 2_000_000},
+				{Addr: feeAddr, Amount: 700_000},
+			},
+			required: 2_000_000 + 500_000 + 700_000 + 300_000,
+		},
+		{
+			name: "overflow",
+			receivers: []request.CreateBridgingTxTransactionRequest{
+				{Addr: testnetAddr, Amount: math.MaxUint64 - 1_000_000},
+				{Addr: testnetAddr, Amount: 2_000_000},
+			},
+			overflows: true,
+		},
+		{
+			name:        "overflow with fee",
+			bridgingFee: math.MaxUint64 - 100_000,
+			receivers:   []request.CreateBridgingTxTransactionRequest{{Addr: testnetAddr, Amount: 2_000_000}},
+			overflows:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requestBody := request.CreateBridgingTxRequest{
+				SenderAddr:         testnetAddr,
+				SourceChainID:      common.ChainIDStrPrime,
+				DestinationChainID: common.ChainIDStrVector,
+				BridgingFee:        tc.bridgingFee,
+				Transactions:       tc.receivers,
+			}
+
+			required, ok := controller.getRequiredBalance(requestBody)
+			require.Equal(t, !tc.overflows, ok)
+
+			if !tc.overflows {
+				require.Equal(t, tc.required, required)
+			}
+
+			// the request of the caller is not filled out
+			require.Equal(t, tc.bridgingFee, requestBody.BridgingFee)
+			require.Equal(t, tc.receivers, requestBody.Transactions)
+		})
+	}
+
+	t.Run("fee overflow is a static issue", func(t *testing.T) {
+		issues := controller.getBridgingRequestStaticIssues(request.CreateBridgingTxRequest{
+			SenderAddr:         testnetAddr,
+			SourceChainID:      common.ChainIDStrPrime,
+			DestinationChainID: common.ChainIDStrVector,
+			BridgingFee:        math.MaxUint64,
+			Transactions: []request.CreateBridgingTxTransactionRequest{
+				{Addr: testnetAddr, Amount: 2_000_000},
+				{Addr: feeAddr, Amount: 1_000_000},
+			},
+		})
+		require.Len(t, issues, 1)
+		require.Equal(t, "bridgingFee", issues[0].Field)
+		require.Contains(t, issues[0].Message, "overflows")
+	})
+}
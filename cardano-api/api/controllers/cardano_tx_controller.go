@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/big"
 	"net/http"
+	"time"
 
+	eventstream "github.com/Ethernal-Tech/cardano-api/api/event_stream"
+	feequote "github.com/Ethernal-Tech/cardano-api/api/fee_quote"
 	"github.com/Ethernal-Tech/cardano-api/api/model/request"
 	"github.com/Ethernal-Tech/cardano-api/api/model/response"
 	"github.com/Ethernal-Tech/cardano-api/api/utils"
@@ -14,31 +16,62 @@ import (
 	cardanotx "github.com/Ethernal-Tech/cardano-api/cardano"
 	"github.com/Ethernal-Tech/cardano-api/common"
 	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-api/telemetry"
+	"github.com/Ethernal-Tech/cardano-api/webhook"
 	"github.com/Ethernal-Tech/cardano-infrastructure/sendtx"
 	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
-	goEthCommon "github.com/ethereum/go-ethereum/common"
 	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+const (
+	// maxBridgingTxBatchSize is the maximal number of bridging requests in a single batch
+	maxBridgingTxBatchSize = 50
+	// maxUtxoTxInputs is the maximal (and default) number of inputs of the consolidation and fan-out txs
+	maxUtxoTxInputs = 100
+	// maxFanOutOutputs is the maximal number of outputs of the fan-out tx
+	maxFanOutOutputs = 100
+	// maxReservationAttempts is the number of times the tx is rebuilt
+	// when its inputs have been reserved in the shared store by another replica in the meantime
+	maxReservationAttempts = 3
+	// releaseReservationTimeout is the timeout of releasing the inputs of the tx which is not returned to the caller
+	releaseReservationTimeout = 5 * time.Second
+)
+
+var errUtxosAlreadyReserved = errors.New("utxos have been reserved by another request, please try again")
+
 type CardanoTxControllerImpl struct {
 	appConfig              *core.AppConfig
 	usedUtxoCacher         *utxotransformer.UsedUtxoCacher
+	reservationStore       utxotransformer.IReservationStore
+	feeQuoteStore          *feequote.FeeQuoteStore
 	logger                 hclog.Logger
 	validatorChangeTracker common.ValidatorChangeTracker
+	webhookService         *webhook.Service
+	eventBroker            *eventstream.Broker
 }
 
 var _ core.APIController = (*CardanoTxControllerImpl)(nil)
 
 func NewCardanoTxController(
 	appConfig *core.AppConfig,
+	usedUtxoCacher *utxotransformer.UsedUtxoCacher,
+	reservationStore utxotransformer.IReservationStore,
+	feeQuoteStore *feequote.FeeQuoteStore,
 	logger hclog.Logger,
 	validatorChange common.ValidatorChangeTracker,
+	webhookService *webhook.Service,
+	eventBroker *eventstream.Broker,
 ) *CardanoTxControllerImpl {
 	return &CardanoTxControllerImpl{
 		appConfig:              appConfig,
-		usedUtxoCacher:         utxotransformer.NewUsedUtxoCacher(appConfig.UtxoCacheTimeout),
+		usedUtxoCacher:         usedUtxoCacher,
+		reservationStore:       reservationStore,
+		feeQuoteStore:          feeQuoteStore,
 		logger:                 logger,
 		validatorChangeTracker: validatorChange,
+		webhookService:         webhookService,
+		eventBroker:            eventBroker,
 	}
 }
 
@@ -47,11 +80,23 @@ func (*CardanoTxControllerImpl) GetPathPrefix() string {
 }
 
 func (c *CardanoTxControllerImpl) GetEndpoints() []*core.APIEndpoint {
-	return []*core.APIEndpoint{
+	endpoints := []*core.APIEndpoint{
 		{Path: "CreateBridgingTx", Method: http.MethodPost, Handler: c.createBridgingTx},
 		{Path: "GetBridgingTxFee", Method: http.MethodPost, Handler: c.getBridgingTxFee},
 		{Path: "GetSettings", Method: http.MethodGet, Handler: c.getSettings},
+		{Path: "DecodeTx", Method: http.MethodPost, Handler: c.decodeTx},
+		{Path: "CreateMultisigBridgingTx", Method: http.MethodPost, Handler: c.createMultisigBridgingTx},
+		{Path: "CreateBridgingTxBatch", Method: http.MethodPost, Handler: c.createBridgingTxBatch},
+		{Path: "CreateConsolidationTx", Method: http.MethodPost, Handler: c.createConsolidationTx},
+		{Path: "CreateFanOutTx", Method: http.MethodPost, Handler: c.createFanOutTx},
+		{Path: "ValidateBridgingRequest", Method: http.MethodPost, Handler: c.validateBridgingRequest},
+	}
+
+	if c.eventBroker != nil {
+		endpoints = append(endpoints, &core.APIEndpoint{Path: "Events", Method: http.MethodGet, Handler: c.events})
 	}
+
+	return endpoints
 }
 
 func (c *CardanoTxControllerImpl) getBridgingTxFee(w http.ResponseWriter, r *http.Request) {
@@ -70,7 +115,7 @@ func (c *CardanoTxControllerImpl) getBridgingTxFee(w http.ResponseWriter, r *htt
 		return
 	}
 
-	c.logger.Debug("getBridgingTxFee request", "body", requestBody, "url", r.URL)
+	utils.GetRequestLogger(r.Context(), c.logger).Debug("getBridgingTxFee request", "body", requestBody, "url", r.URL)
 
 	err := c.validateAndFillOutCreateBridgingTxRequest(&requestBody)
 	if err != nil {
@@ -81,14 +126,43 @@ func (c *CardanoTxControllerImpl) getBridgingTxFee(w http.ResponseWriter, r *htt
 		return
 	}
 
-	txFeeInfo, _, err := c.calculateTxFee(r.Context(), requestBody)
+	// the quote is bound to the request as it was sent, before the amount mode is applied
+	quoteRequestHash, err := getQuoteRequestHash(requestBody)
 	if err != nil {
 		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
 
 		return
 	}
 
-	utils.WriteResponse(w, r, http.StatusOK, response.NewBridgingTxFeeResponse(txFeeInfo.Fee), c.logger)
+	if err := c.applyAmountMode(r.Context(), &requestBody); err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	feeBreakdown, err := c.calculateTxFee(r.Context(), requestBody)
+	if err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	if !requestBody.RequestQuote {
+		utils.WriteResponse(w, r, http.StatusOK, response.NewBridgingTxFeeResponse(feeBreakdown), c.logger)
+
+		return
+	}
+
+	quoteID, quote, err := c.createFeeQuote(r.Context(), quoteRequestHash, requestBody, feeBreakdown)
+	if err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	utils.WriteResponse(
+		w, r, http.StatusOK,
+		response.NewQuotedBridgingTxFeeResponse(feeBreakdown, quoteID, quote.ExpiresAt), c.logger)
 }
 
 func (c *CardanoTxControllerImpl) createBridgingTx(w http.ResponseWriter, r *http.Request) {
@@ -105,7 +179,7 @@ func (c *CardanoTxControllerImpl) createBridgingTx(w http.ResponseWriter, r *htt
 		return
 	}
 
-	c.logger.Debug("createBridgingTx request", "body", requestBody, "url", r.URL)
+	utils.GetRequestLogger(r.Context(), c.logger).Debug("createBridgingTx request", "body", requestBody, "url", r.URL)
 
 	err := c.validateAndFillOutCreateBridgingTxRequest(&requestBody)
 	if err != nil {
@@ -116,6 +190,29 @@ func (c *CardanoTxControllerImpl) createBridgingTx(w http.ResponseWriter, r *htt
 		return
 	}
 
+	if requestBody.QuoteID != "" {
+		quote, err := c.redeemFeeQuote(r.Context(), requestBody)
+		if err != nil {
+			utils.WriteErrorResponse(w, r, http.StatusBadRequest, err, c.logger)
+
+			return
+		}
+
+		c.trackTx(r.Context(), requestBody.SourceChainID, quote.TxHash)
+
+		utils.WriteResponse(
+			w, r, http.StatusOK,
+			response.NewFullBridgingTxResponse(quote.TxRaw, quote.TxHash, quote.BridgingFee), c.logger)
+
+		return
+	}
+
+	if err := c.applyAmountMode(r.Context(), &requestBody); err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
 	txInfo, err := c.createTx(r.Context(), requestBody)
 	if err != nil {
 		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
@@ -123,141 +220,739 @@ func (c *CardanoTxControllerImpl) createBridgingTx(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// the caller has gone away, so the tx will not be submitted and its inputs can be used by other requests
+	if err := r.Context().Err(); err != nil {
+		if utils.UseUtxoCache(requestBody, c.appConfig) {
+			c.releaseInputs(r.Context(), requestBody.SenderAddr, txInfo.ChosenInputs.Inputs)
+		}
+
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	c.trackTx(r.Context(), requestBody.SourceChainID, txInfo.TxHash)
+
 	utils.WriteResponse(
 		w, r, http.StatusOK,
 		response.NewFullBridgingTxResponse(txInfo.TxRaw, txInfo.TxHash, requestBody.BridgingFee), c.logger)
 }
 
-func (c *CardanoTxControllerImpl) getSettings(w http.ResponseWriter, r *http.Request) {
+// trackTx tracks the state of the built bridging tx for the webhook of the caller (if the webhooks are enabled)
+func (c *CardanoTxControllerImpl) trackTx(ctx context.Context, chainID string, txHash string) {
+	cardanoConfig, _ := c.appConfig.GetChainConfig(chainID)
+	if c.webhookService == nil || cardanoConfig == nil || cardanoConfig.ChainSpecific == nil {
+		return
+	}
+
+	// slot length is one second
+	ttl := time.Duration(cardanoConfig.ChainSpecific.TTLSlotNumberInc) * time.Second //nolint:gosec
+
+	c.webhookService.TrackTx(common.APIKeyIDFromContext(ctx), chainID, txHash, ttl)
+}
+
+// createFeeQuote builds the quoted tx, which reserves its inputs in the utxo cache, and stores it in the quote store.
+// The fee breakdown is updated with the fee of the built tx
+func (c *CardanoTxControllerImpl) createFeeQuote(
+	ctx context.Context, requestHash string, requestBody request.CreateBridgingTxRequest,
+	feeBreakdown *cardanotx.BridgingTxFeeBreakdown,
+) (string, *feequote.Quote, error) {
+	txInfo, err := c.createTx(ctx, requestBody)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fee, err := cardanotx.GetTxFee(txInfo.TxRaw)
+	if err != nil {
+		if utils.UseUtxoCache(requestBody, c.appConfig) {
+			c.releaseInputs(ctx, requestBody.SenderAddr, txInfo.ChosenInputs.Inputs)
+		}
+
+		return "", nil, err
+	}
+
+	feeBreakdown.TotalDebit = feeBreakdown.TotalDebit - feeBreakdown.SourceTxFee + fee
+	feeBreakdown.SourceTxFee = fee
+
+	quoteID, quote := c.feeQuoteStore.Add(feequote.Quote{
+		TxRaw:       txInfo.TxRaw,
+		TxHash:      txInfo.TxHash,
+		Inputs:      txInfo.ChosenInputs.Inputs,
+		SenderAddr:  requestBody.SenderAddr,
+		BridgingFee: requestBody.BridgingFee,
+	}, requestHash, common.APIKeyIDFromContext(ctx))
+
+	return quoteID, quote, nil
+}
+
+// redeemFeeQuote returns the quoted tx and extends the reservation of its inputs in the local utxo cache.
+// Quotes are kept in memory, so only the replica which created the quote can redeem it. Reservation in the shared
+// store is not extended, it lasts utxoCacheTimeout from the creation of the quote, which is not shorter than the quote
+func (c *CardanoTxControllerImpl) redeemFeeQuote(
+	ctx context.Context, requestBody request.CreateBridgingTxRequest,
+) (*feequote.Quote, error) {
+	requestHash, err := getQuoteRequestHash(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := c.feeQuoteStore.Redeem(requestBody.QuoteID, requestHash, common.APIKeyIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	c.usedUtxoCacher.AddWithInfo(quote.SenderAddr, quote.Inputs, utils.GetReservationInfo(ctx))
+
+	return quote, nil
+}
+
+// getQuoteRequestHash returns hash of the request without quote fields so fee and create requests have the same hash
+func getQuoteRequestHash(requestBody request.CreateBridgingTxRequest) (string, error) {
+	requestBody.RequestQuote = false
+	requestBody.QuoteID = ""
+
+	return feequote.GetRequestHash(requestBody)
+}
+
+// validateBridgingRequest runs all the checks of CreateBridgingTx and returns every found issue
+// without building the tx or reserving utxos
+func (c *CardanoTxControllerImpl) validateBridgingRequest(w http.ResponseWriter, r *http.Request) {
+	if c.validatorChangeTracker.IsValidatorChangeInProgress() {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			fmt.Errorf("validator change is in progress, validating a bridge request is not possible at the moment"),
+			c.logger)
+
+		return
+	}
+
+	requestBody, ok := utils.DecodeModel[request.CreateBridgingTxRequest](w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	utils.GetRequestLogger(r.Context(), c.logger).Debug(
+		"validateBridgingRequest request", "body", requestBody, "url", r.URL)
+
+	issues, err := c.getBridgingRequestIssues(r.Context(), requestBody)
+	if err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	utils.WriteResponse(w, r, http.StatusOK, response.NewValidateBridgingRequestResponse(issues), c.logger)
+}
+
+func (c *CardanoTxControllerImpl) createMultisigBridgingTx(w http.ResponseWriter, r *http.Request) {
+	if c.validatorChangeTracker.IsValidatorChangeInProgress() {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			fmt.Errorf("validator change is in progress, creating a bridge tx is not possible at the moment"), c.logger)
+
+		return
+	}
+
+	requestBody, ok := utils.DecodeModel[request.CreateBridgingTxRequest](w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	utils.GetRequestLogger(r.Context(), c.logger).Debug(
+		"createMultisigBridgingTx request", "body", requestBody, "url", r.URL)
+
+	if requestBody.SenderAddrPolicyScript == nil {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			errors.New("validation error. err: senderAddrPolicyScript is required for multisig sender"), c.logger)
+
+		return
+	}
+
+	err := c.validateAndFillOutCreateBridgingTxRequest(&requestBody)
+	if err != nil {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			fmt.Errorf("validation error. err: %w", err), c.logger)
+
+		return
+	}
+
+	witnesses, err := cardanotx.GetPolicyScriptWitnesses(*requestBody.SenderAddrPolicyScript)
+	if err != nil {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			fmt.Errorf("validation error. err: %w", err), c.logger)
+
+		return
+	}
+
+	if err := c.applyAmountMode(r.Context(), &requestBody); err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	txInfo, err := c.createTx(r.Context(), requestBody)
+	if err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	// the caller has gone away, so the tx will not be submitted and its inputs can be used by other requests
+	if err := r.Context().Err(); err != nil {
+		if utils.UseUtxoCache(requestBody, c.appConfig) {
+			c.releaseInputs(r.Context(), requestBody.SenderAddr, txInfo.ChosenInputs.Inputs)
+		}
+
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	c.trackTx(r.Context(), requestBody.SourceChainID, txInfo.TxHash)
+
 	utils.WriteResponse(
 		w, r, http.StatusOK,
-		response.NewSettingsResponse(c.appConfig), c.logger)
+		response.NewMultisigBridgingTxResponse(
+			txInfo.TxRaw, txInfo.TxHash, requestBody.BridgingFee, witnesses.KeyHashes, witnesses.Threshold),
+		c.logger)
 }
 
-func (c *CardanoTxControllerImpl) validateAndFillOutCreateBridgingTxRequest(
-	requestBody *request.CreateBridgingTxRequest,
-) error {
-	cardanoSrcConfig, _ := c.appConfig.GetChainConfig(requestBody.SourceChainID)
-	if cardanoSrcConfig == nil {
-		return fmt.Errorf("origin chain not registered: %v", requestBody.SourceChainID)
+func (c *CardanoTxControllerImpl) createBridgingTxBatch(w http.ResponseWriter, r *http.Request) {
+	if c.validatorChangeTracker.IsValidatorChangeInProgress() {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			fmt.Errorf("validator change is in progress, creating a bridge tx is not possible at the moment"), c.logger)
+
+		return
 	}
 
-	cardanoDestConfig, ethDestConfig := c.appConfig.GetChainConfig(requestBody.DestinationChainID)
-	if cardanoDestConfig == nil && ethDestConfig == nil {
-		return fmt.Errorf("destination chain not registered: %v", requestBody.DestinationChainID)
+	requestBody, ok := utils.DecodeModel[request.CreateBridgingTxBatchRequest](w, r, c.logger)
+	if !ok {
+		return
 	}
 
-	if len(requestBody.Transactions) > c.appConfig.BridgingSettings.MaxReceiversPerBridgingRequest {
-		return fmt.Errorf("number of receivers in metadata greater than maximum allowed - no: %v, max: %v, requestBody: %v",
-			len(requestBody.Transactions), c.appConfig.BridgingSettings.MaxReceiversPerBridgingRequest, requestBody)
+	utils.GetRequestLogger(r.Context(), c.logger).Debug(
+		"createBridgingTxBatch request", "body", requestBody, "url", r.URL)
+
+	if len(requestBody.Requests) == 0 || len(requestBody.Requests) > maxBridgingTxBatchSize {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			fmt.Errorf("validation error. err: number of requests in batch should be between 1 and %d, got %d",
+				maxBridgingTxBatchSize, len(requestBody.Requests)), c.logger)
+
+		return
 	}
 
-	receiverAmountSum := big.NewInt(0)
-	feeSum := uint64(0)
-	foundAUtxoValueBelowMinimumValue := false
-	foundAnInvalidReceiverAddr := false
-	transactions := make([]request.CreateBridgingTxTransactionRequest, 0, len(requestBody.Transactions))
+	utils.WriteResponse(
+		w, r, http.StatusOK,
+		response.NewBridgingTxBatchResponse(c.createTxBatch(r.Context(), requestBody)), c.logger)
+}
 
-	for _, receiver := range requestBody.Transactions {
-		if cardanoDestConfig != nil {
-			if receiver.Amount < c.appConfig.BridgingSettings.MinValueToBridge {
-				foundAUtxoValueBelowMinimumValue = true
+// createTxBatch builds bridging txs in order. Inputs used by one item are reserved for the following items,
+// either in the used utxo cache (if the request is allowed to use it) or by adding them to the skip utxos.
+// Failure of one item does not affect the others
+func (c *CardanoTxControllerImpl) createTxBatch(
+	ctx context.Context, batchRequest request.CreateBridgingTxBatchRequest,
+) []response.BridgingTxBatchItemResponse {
+	items := make([]response.BridgingTxBatchItemResponse, len(batchRequest.Requests))
+	skipUtxos := append([]request.UtxoRequest(nil), batchRequest.SkipUtxos...)
+	reservedInputs := map[string][]wallet.TxInput{}
+
+	for i := range batchRequest.Requests {
+		if err := ctx.Err(); err != nil {
+			items[i] = response.NewBridgingTxBatchItemErrorResponse(i, err)
+
+			continue
+		}
 
-				break
-			}
+		requestBody := batchRequest.ToCreateBridgingTxRequest(i, skipUtxos)
 
-			if !cardanotx.IsValidOutputAddress(receiver.Addr, cardanoDestConfig.NetworkID) {
-				foundAnInvalidReceiverAddr = true
+		if err := c.validateAndFillOutCreateBridgingTxRequest(&requestBody); err != nil {
+			items[i] = response.NewBridgingTxBatchItemErrorResponse(i, fmt.Errorf("validation error. err: %w", err))
 
-				break
-			}
+			continue
+		}
 
-			// if fee address is specified in transactions just add amount to the fee sum
-			// otherwise keep this transaction
-			if receiver.Addr == cardanoDestConfig.BridgingAddresses.FeeAddress {
-				feeSum += receiver.Amount
-			} else {
-				transactions = append(transactions, receiver)
-				receiverAmountSum.Add(receiverAmountSum, new(big.Int).SetUint64(receiver.Amount))
-			}
-		} else if ethDestConfig != nil {
-			if !goEthCommon.IsHexAddress(receiver.Addr) {
-				foundAnInvalidReceiverAddr = true
+		txInfo, err := c.createTx(ctx, requestBody)
+		if err != nil {
+			utils.GetRequestLogger(ctx, c.logger).Debug("failed to create batch item", "index", i, "err", err)
 
-				break
+			items[i] = response.NewBridgingTxBatchItemErrorResponse(i, err)
+
+			continue
+		}
+
+		// inputs are already reserved if the cache is used, otherwise skip them for the next items
+		if utils.UseUtxoCache(requestBody, c.appConfig) {
+			reservedInputs[requestBody.SenderAddr] = append(
+				reservedInputs[requestBody.SenderAddr], txInfo.ChosenInputs.Inputs...)
+		} else {
+			for _, input := range txInfo.ChosenInputs.Inputs {
+				skipUtxos = append(skipUtxos, request.UtxoRequest{
+					Hash:  input.Hash,
+					Index: input.Index,
+				})
 			}
+		}
+
+		c.trackTx(ctx, requestBody.SourceChainID, txInfo.TxHash)
+
+		items[i] = response.NewBridgingTxBatchItemResponse(i, txInfo.TxRaw, txInfo.TxHash, requestBody.BridgingFee)
+	}
+
+	// the caller has gone away, so the built txs will not be submitted and their inputs can be used by other requests
+	if ctx.Err() != nil {
+		for addr, inputs := range reservedInputs {
+			c.releaseInputs(ctx, addr, inputs)
+		}
+	}
+
+	return items
+}
+
+func (c *CardanoTxControllerImpl) createConsolidationTx(w http.ResponseWriter, r *http.Request) {
+	requestBody, ok := utils.DecodeModel[request.CreateConsolidationTxRequest](w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	utils.GetRequestLogger(r.Context(), c.logger).Debug(
+		"createConsolidationTx request", "body", requestBody, "url", r.URL)
+
+	if requestBody.MaxInputs == 0 {
+		requestBody.MaxInputs = maxUtxoTxInputs
+	}
+
+	cardanoConfig, err := c.validateUtxoTxRequest(
+		requestBody.SenderAddr, requestBody.SenderAddrPolicyScript, requestBody.ChainID)
+	if err == nil && (requestBody.MaxInputs < 2 || requestBody.MaxInputs > maxUtxoTxInputs) {
+		err = fmt.Errorf("max inputs should be between 2 and %d", maxUtxoTxInputs)
+	}
+
+	if err != nil {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			fmt.Errorf("validation error. err: %w", err), c.logger)
+
+		return
+	}
+
+	txInfo, err := c.createUtxoTx(
+		r.Context(), cardanoConfig, requestBody.SenderAddr, requestBody.UTXOCacheKey, requestBody.SkipUtxos,
+		func(ctx context.Context, config cardanotx.UtxoTxConfig, utxos []wallet.Utxo) (*cardanotx.UtxoTxInfo, error) {
+			return cardanotx.CreateConsolidationTx(
+				ctx, config, requestBody.SenderAddr, requestBody.SenderAddrPolicyScript,
+				utxos, requestBody.MaxUtxoAmount, requestBody.MaxInputs)
+		})
+	if err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	if err := c.checkUtxoTxDelivered(r.Context(), requestBody.SenderAddr, requestBody.UTXOCacheKey, txInfo); err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
 
-			if receiver.Addr == common.EthZeroAddr {
-				feeSum += receiver.Amount
-			} else {
-				transactions = append(transactions, receiver)
-				receiverAmountSum.Add(receiverAmountSum, new(big.Int).SetUint64(receiver.Amount))
+	utils.WriteResponse(
+		w, r, http.StatusOK,
+		response.NewUtxoTxResponse(txInfo.TxRaw, txInfo.TxHash, txInfo.Fee, len(txInfo.Inputs), txInfo.OutputsCount),
+		c.logger)
+}
+
+func (c *CardanoTxControllerImpl) createFanOutTx(w http.ResponseWriter, r *http.Request) {
+	requestBody, ok := utils.DecodeModel[request.CreateFanOutTxRequest](w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	utils.GetRequestLogger(r.Context(), c.logger).Debug("createFanOutTx request", "body", requestBody, "url", r.URL)
+
+	cardanoConfig, err := c.validateUtxoTxRequest(
+		requestBody.SenderAddr, requestBody.SenderAddrPolicyScript, requestBody.ChainID)
+	if err == nil {
+		switch minUtxoValue := c.appConfig.GetBridgingSettings().MinUtxoChainValue[requestBody.ChainID]; {
+		case requestBody.OutputsCount < 1 || requestBody.OutputsCount > maxFanOutOutputs:
+			err = fmt.Errorf("outputs count should be between 1 and %d", maxFanOutOutputs)
+		case requestBody.OutputAmount < minUtxoValue:
+			err = fmt.Errorf("output amount %d is lower than minimal utxo value %d",
+				requestBody.OutputAmount, minUtxoValue)
+		default:
+			_, err = cardanotx.GetFanOutDesiredAmount(requestBody.OutputAmount, requestBody.OutputsCount, minUtxoValue)
+		}
+	}
+
+	if err != nil {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			fmt.Errorf("validation error. err: %w", err), c.logger)
+
+		return
+	}
+
+	txInfo, err := c.createUtxoTx(
+		r.Context(), cardanoConfig, requestBody.SenderAddr, requestBody.UTXOCacheKey, requestBody.SkipUtxos,
+		func(ctx context.Context, config cardanotx.UtxoTxConfig, utxos []wallet.Utxo) (*cardanotx.UtxoTxInfo, error) {
+			return cardanotx.CreateFanOutTx(
+				ctx, config, requestBody.SenderAddr, requestBody.SenderAddrPolicyScript,
+				utxos, requestBody.OutputAmount, requestBody.OutputsCount, maxUtxoTxInputs)
+		})
+	if err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	if err := c.checkUtxoTxDelivered(r.Context(), requestBody.SenderAddr, requestBody.UTXOCacheKey, txInfo); err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	utils.WriteResponse(
+		w, r, http.StatusOK,
+		response.NewUtxoTxResponse(txInfo.TxRaw, txInfo.TxHash, txInfo.Fee, len(txInfo.Inputs), txInfo.OutputsCount),
+		c.logger)
+}
+
+func (c *CardanoTxControllerImpl) validateUtxoTxRequest(
+	senderAddr string, policyScript *wallet.PolicyScript, chainID string,
+) (*core.CardanoChainConfig, error) {
+	cardanoConfig, _ := c.appConfig.GetChainConfig(chainID)
+	if cardanoConfig == nil {
+		return nil, fmt.Errorf("chain not registered: %v", chainID)
+	}
+
+	if !cardanotx.IsValidOutputAddress(senderAddr, cardanoConfig.NetworkID) {
+		return nil, fmt.Errorf("invalid sender addr: %s", senderAddr)
+	}
+
+	if policyScript != nil {
+		if err := cardanotx.ValidatePolicyScriptForAddress(senderAddr, *policyScript); err != nil {
+			return nil, fmt.Errorf("invalid sender address policy script: %w", err)
+		}
+	}
+
+	return cardanoConfig, nil
+}
+
+// createUtxoTx retrieves utxos of the sender without those reserved in the cache (or skipped by the request),
+// builds the tx and reserves its inputs if the request is allowed to use the cache
+func (c *CardanoTxControllerImpl) createUtxoTx(
+	ctx context.Context, cardanoConfig *core.CardanoChainConfig,
+	senderAddr string, utxoCacheKey string, skipUtxos []request.UtxoRequest,
+	buildTx func(context.Context, cardanotx.UtxoTxConfig, []wallet.Utxo) (*cardanotx.UtxoTxInfo, error),
+) (*cardanotx.UtxoTxInfo, error) {
+	for attempt := 1; ; attempt++ {
+		txInfo, err := c.createUtxoTxAttempt(ctx, cardanoConfig, senderAddr, utxoCacheKey, skipUtxos, buildTx)
+		if !errors.Is(err, errUtxosAlreadyReserved) || attempt >= maxReservationAttempts {
+			return txInfo, err
+		}
+
+		utils.GetRequestLogger(ctx, c.logger).Debug(
+			"utxos already reserved, rebuilding tx", "addr", senderAddr, "attempt", attempt)
+	}
+}
+
+func (c *CardanoTxControllerImpl) createUtxoTxAttempt(
+	ctx context.Context, cardanoConfig *core.CardanoChainConfig,
+	senderAddr string, utxoCacheKey string, skipUtxos []request.UtxoRequest,
+	buildTx func(context.Context, cardanotx.UtxoTxConfig, []wallet.Utxo) (*cardanotx.UtxoTxInfo, error),
+) (*cardanotx.UtxoTxInfo, error) {
+	txProvider, err := cardanoConfig.ChainSpecific.CreateTxProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tx provider: %w", err)
+	}
+
+	defer txProvider.Dispose()
+
+	utxos, err := txProvider.GetUtxos(ctx, senderAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve utxos: %w", err)
+	}
+
+	var cacheUtxosTransformer utxotransformer.IUtxosTransformer = &utxotransformer.CacheUtxosTransformer{
+		UtxoCacher:      c.usedUtxoCacher,
+		Addr:            senderAddr,
+		ReservationInfo: utils.GetReservationInfo(ctx),
+	}
+
+	if c.reservationStore != nil {
+		sharedTransformer := &utxotransformer.SharedUtxosTransformer{
+			Store:           c.reservationStore,
+			UtxoCacher:      c.usedUtxoCacher,
+			Addr:            senderAddr,
+			ReservationInfo: utils.GetReservationInfo(ctx),
+		}
+
+		if err := sharedTransformer.Load(ctx); err != nil {
+			return nil, fmt.Errorf("failed to retrieve reserved utxos: %w", err)
+		}
+
+		cacheUtxosTransformer = sharedTransformer
+	}
+
+	utxos = cacheUtxosTransformer.TransformUtxos(utxos)
+	utxos = (&utxotransformer.SkipUtxosTransformer{
+		SkipUtxos: common.Map(skipUtxos, func(x request.UtxoRequest) wallet.TxInput {
+			return wallet.TxInput{
+				Hash:  x.Hash,
+				Index: x.Index,
 			}
+		}),
+	}).TransformUtxos(utxos)
+
+	txInfo, err := buildTx(ctx, cardanotx.UtxoTxConfig{
+		CardanoCliBinary: wallet.ResolveCardanoCliBinary(cardanoConfig.NetworkID),
+		TxProvider:       txProvider,
+		TestNetMagic:     uint(cardanoConfig.NetworkMagic),
+		TTLSlotNumberInc: cardanoConfig.ChainSpecific.TTLSlotNumberInc,
+		MinUtxoValue:     c.appConfig.GetBridgingSettings().MinUtxoChainValue[cardanoConfig.ChainID],
+	}, utxos)
+	if err != nil {
+		utils.GetRequestLogger(ctx, c.logger).Error("failed to build tx", "err", err)
+
+		return nil, fmt.Errorf("failed to build tx: %w", err)
+	}
+
+	if !utils.IsUtxoCacheKeyAllowed(utxoCacheKey, c.appConfig) {
+		return txInfo, nil
+	}
+
+	if err := reserveInputs(ctx, cacheUtxosTransformer, txInfo.Inputs); err != nil {
+		return nil, err
+	}
+
+	return txInfo, nil
+}
+
+// checkUtxoTxDelivered returns an error if the caller has gone away, in which case the tx will not be submitted
+// and its inputs are released if they were reserved
+func (c *CardanoTxControllerImpl) checkUtxoTxDelivered(
+	ctx context.Context, senderAddr string, utxoCacheKey string, txInfo *cardanotx.UtxoTxInfo,
+) error {
+	err := ctx.Err()
+	if err != nil && utils.IsUtxoCacheKeyAllowed(utxoCacheKey, c.appConfig) {
+		c.releaseInputs(ctx, senderAddr, txInfo.Inputs)
+	}
+
+	return err
+}
+
+// releaseInputs releases the reservations of the inputs of the tx which is not returned to the caller,
+// both in the local utxo cache and in the shared reservation store, so they are not blocked until they expire
+func (c *CardanoTxControllerImpl) releaseInputs(ctx context.Context, addr string, inputs []wallet.TxInput) {
+	if len(inputs) == 0 {
+		return
+	}
+
+	// the request context may be already canceled
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseReservationTimeout)
+	defer cancel()
+
+	released, err := utxotransformer.ReleaseReservations(ctx, c.usedUtxoCacher, c.reservationStore, addr, inputs)
+	if err != nil {
+		utils.GetRequestLogger(ctx, c.logger).Error("failed to release reserved utxos", "addr", addr, "err", err)
+
+		return
+	}
+
+	utils.GetRequestLogger(ctx, c.logger).Debug("released reserved utxos", "addr", addr, "released", released)
+}
+
+func (c *CardanoTxControllerImpl) getSettings(w http.ResponseWriter, r *http.Request) {
+	utils.WriteResponse(
+		w, r, http.StatusOK,
+		response.NewSettingsResponse(c.appConfig), c.logger)
+}
+
+func (c *CardanoTxControllerImpl) decodeTx(w http.ResponseWriter, r *http.Request) {
+	requestBody, ok := utils.DecodeModel[request.DecodeTxRequest](w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	utils.GetRequestLogger(r.Context(), c.logger).Debug("decodeTx request", "body", requestBody, "url", r.URL)
+
+	txRaw, err := c.getTxRaw(r.Context(), requestBody)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, cardanotx.ErrTxNotFound) {
+			status = http.StatusNotFound
 		}
+
+		utils.WriteErrorResponse(w, r, status, err, c.logger)
+
+		return
 	}
 
-	if foundAUtxoValueBelowMinimumValue {
-		return fmt.Errorf("found a utxo value below minimum value in request body receivers: %v", requestBody)
+	txHash, err := cardanotx.GetTxHash(txRaw)
+	if err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusBadRequest, err, c.logger)
+
+		return
 	}
 
-	if foundAnInvalidReceiverAddr {
-		return fmt.Errorf("found an invalid receiver addr in request body: %v", requestBody)
+	metadata, err := cardanotx.DecodeTxMetadata(txRaw)
+	if err != nil {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest,
+			fmt.Errorf("failed to decode metadata of tx %s. err: %w", txHash, err), c.logger)
+
+		return
 	}
 
-	requestBody.BridgingFee += feeSum
-	requestBody.Transactions = transactions
+	utils.WriteResponse(w, r, http.StatusOK, response.NewDecodedTxResponse(txHash, metadata), c.logger)
+}
 
-	// this is just convinient way to setup default min fee
-	if requestBody.BridgingFee == 0 {
-		requestBody.BridgingFee = c.appConfig.BridgingSettings.MinChainFeeForBridging[requestBody.DestinationChainID]
+func (c *CardanoTxControllerImpl) getTxRaw(
+	ctx context.Context, requestBody request.DecodeTxRequest,
+) ([]byte, error) {
+	if (requestBody.TxRaw == "") == (requestBody.TxHash == "") {
+		return nil, errors.New("specify either txRaw or txHash")
 	}
 
-	if c.appConfig.BridgingSettings.MaxAmountAllowedToBridge != nil &&
-		c.appConfig.BridgingSettings.MaxAmountAllowedToBridge.Sign() == 1 &&
-		receiverAmountSum.Cmp(c.appConfig.BridgingSettings.MaxAmountAllowedToBridge) == 1 {
-		return fmt.Errorf("sum of receiver amounts + fee greater than maximum allowed: %v, for request: %v",
-			c.appConfig.BridgingSettings.MaxAmountAllowedToBridge, requestBody)
+	if requestBody.TxRaw != "" {
+		txRaw, err := common.DecodeHex(requestBody.TxRaw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid txRaw: %w", err)
+		}
+
+		return txRaw, nil
 	}
 
-	receiverAmountSum.Add(receiverAmountSum, new(big.Int).SetUint64(requestBody.BridgingFee))
+	cardanoConfig, _ := c.appConfig.GetChainConfig(requestBody.ChainID)
+	if cardanoConfig == nil {
+		return nil, fmt.Errorf("chain not registered: %v", requestBody.ChainID)
+	}
 
-	minFee, found := c.appConfig.BridgingSettings.MinChainFeeForBridging[requestBody.DestinationChainID]
-	if !found {
-		return fmt.Errorf("no minimal fee for chain: %s", requestBody.DestinationChainID)
+	return cardanoConfig.ChainSpecific.GetTxRaw(ctx, requestBody.TxHash)
+}
+
+// CreateBridgingTx validates and fills out the request and builds the bridging tx without going through http.
+// It is used by cli commands which need the same validation and tx building logic as the api
+func (c *CardanoTxControllerImpl) CreateBridgingTx(
+	ctx context.Context, requestBody *request.CreateBridgingTxRequest,
+) (*sendtx.TxInfo, error) {
+	if c.validatorChangeTracker.IsValidatorChangeInProgress() {
+		return nil, errors.New("validator change is in progress, creating a bridge tx is not possible at the moment")
 	}
 
-	if requestBody.BridgingFee < minFee {
-		return fmt.Errorf("bridging fee in request body is less than minimum: %v", requestBody)
+	if err := c.validateAndFillOutCreateBridgingTxRequest(requestBody); err != nil {
+		return nil, fmt.Errorf("validation error. err: %w", err)
 	}
 
+	if err := c.applyAmountMode(ctx, requestBody); err != nil {
+		return nil, err
+	}
+
+	return c.createTx(ctx, *requestBody)
+}
+
+// validateAndFillOutCreateBridgingTxRequest rejects the request for the same issues ValidateBridgingRequest reports
+// and fills it out: amounts sent to the fee address are added to the bridging fee and the minimal fee is set if missing
+func (c *CardanoTxControllerImpl) validateAndFillOutCreateBridgingTxRequest(
+	requestBody *request.CreateBridgingTxRequest,
+) error {
+	if issues := c.getBridgingRequestStaticIssues(*requestBody); len(issues) > 0 {
+		return issues.toError()
+	}
+
+	c.fillOutCreateBridgingTxRequest(requestBody)
+
 	return nil
 }
 
+// fillOutCreateBridgingTxRequest moves amounts sent to the fee address to the bridging fee and sets the minimal fee
+// if missing. The request must already be validated. Slices of the request are replaced, not modified
+func (c *CardanoTxControllerImpl) fillOutCreateBridgingTxRequest(requestBody *request.CreateBridgingTxRequest) {
+	settings := c.appConfig.GetBridgingSettings()
+	cardanoDestConfig, _ := c.appConfig.GetChainConfig(requestBody.DestinationChainID)
+
+	// the amount is calculated later, until then it is set to the minimal one
+	if requestBody.Mode == request.BridgingTxModeSendMax {
+		requestBody.Transactions = []request.CreateBridgingTxTransactionRequest{{
+			Addr:   requestBody.Transactions[0].Addr,
+			Amount: settings.MinValueToBridge,
+		}}
+	}
+
+	feeSum := uint64(0)
+	transactions := make([]request.CreateBridgingTxTransactionRequest, 0, len(requestBody.Transactions))
+
+	for _, receiver := range requestBody.Transactions {
+		if isFeeReceiver(receiver.Addr, cardanoDestConfig) {
+			feeSum += receiver.Amount
+		} else {
+			transactions = append(transactions, receiver)
+		}
+	}
+
+	requestBody.BridgingFee += feeSum
+	requestBody.Transactions = transactions
+
+	// this is just convinient way to setup default min fee
+	if requestBody.BridgingFee == 0 {
+		requestBody.BridgingFee = settings.MinChainFeeForBridging[requestBody.DestinationChainID]
+	}
+}
+
 func (c *CardanoTxControllerImpl) createTx(ctx context.Context, requestBody request.CreateBridgingTxRequest) (
 	*sendtx.TxInfo, error,
 ) {
-	// Setup transaction components
-	cacheUtxosTransformer := utils.GetUtxosTransformer(requestBody, c.appConfig, c.usedUtxoCacher)
+	for attempt := 1; ; attempt++ {
+		txInfo, err := c.createTxAttempt(ctx, requestBody)
+		if !errors.Is(err, errUtxosAlreadyReserved) || attempt >= maxReservationAttempts {
+			return txInfo, err
+		}
+
+		utils.GetRequestLogger(ctx, c.logger).Debug(
+			"utxos already reserved, rebuilding tx", "addr", requestBody.SenderAddr, "attempt", attempt)
+	}
+}
 
-	txSender, receivers, err := c.getTxSenderAndReceivers(requestBody, cacheUtxosTransformer)
+func (c *CardanoTxControllerImpl) createTxAttempt(ctx context.Context, requestBody request.CreateBridgingTxRequest) (
+	*sendtx.TxInfo, error,
+) {
+	// Setup transaction components
+	cacheUtxosTransformer, err := utils.GetUtxosTransformer(
+		ctx, requestBody, c.appConfig, c.usedUtxoCacher, c.reservationStore)
 	if err != nil {
 		return nil, err
 	}
 
+	txSenderChainsConfig, err := c.appConfig.ToSendTxChainConfigs(ctx, requestBody.UseFallback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate configuration")
+	}
+
+	txSender := sendtx.NewTxSender(txSenderChainsConfig, sendtx.WithUtxosTransformer(cacheUtxosTransformer))
+	txDto := getBridgingTxDto(requestBody)
+
 	// Create the bridging transaction
-	txInfo, _, err := txSender.CreateBridgingTx(
-		ctx,
-		sendtx.BridgingTxDto{
-			SrcChainID:             requestBody.SourceChainID,
-			DstChainID:             requestBody.DestinationChainID,
-			SenderAddr:             requestBody.SenderAddr,
-			SenderAddrPolicyScript: requestBody.SenderAddrPolicyScript,
-			Receivers:              receivers,
-			BridgingFee:            requestBody.BridgingFee,
-		},
-	)
+	var txInfo *sendtx.TxInfo
+
+	sendTxCtx, span := telemetry.StartSpan(ctx, "sendtx.CreateBridgingTx", getChainPairAttributes(requestBody)...)
+
+	metadataVersion, isVersioned := c.getBridgingTxMetadataVersion(requestBody)
+	if !isVersioned {
+		txInfo, _, err = txSender.CreateBridgingTx(sendTxCtx, txDto)
+	} else {
+		span.SetAttributes(attribute.Int("metadataVersion", int(metadataVersion)))
+
+		txInfo, _, err = cardanotx.CreateVersionedBridgingTx(sendTxCtx, txSender,
+			txSenderChainsConfig[requestBody.SourceChainID], txDto, requestBody.RefundAddr, metadataVersion)
+	}
+
+	telemetry.EndSpan(span, err)
+
 	if err != nil {
-		c.logger.Error("failed to build tx", "err", err)
+		utils.GetRequestLogger(ctx, c.logger).Error("failed to build tx", "err", err)
 
 		if errors.Is(err, wallet.ErrUTXOsCouldNotSelect) {
 			err = errors.New("not enough funds for the transaction")
@@ -266,89 +961,148 @@ func (c *CardanoTxControllerImpl) createTx(ctx context.Context, requestBody requ
 		return nil, fmt.Errorf("failed to build tx: %w", err)
 	}
 
+	// Change of this tx is offered to the next chained txs, so it is retrieved before the inputs are reserved
+	var changeUtxos []wallet.Utxo
+
+	chainedTransformer, isChained := cacheUtxosTransformer.(*utxotransformer.ChainedUtxosTransformer)
+	if isChained {
+		changeUtxos, err = cardanotx.GetTxOutputsForAddress(txInfo.TxRaw, txInfo.TxHash, requestBody.SenderAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve change outputs for chaining: %w", err)
+		}
+	}
+
 	// Update UTXO cache if available
-	if cacheUtxosTransformer != nil {
-		cacheUtxosTransformer.UpdateUtxos(txInfo.ChosenInputs.Inputs)
+	if err := reserveInputs(ctx, cacheUtxosTransformer, txInfo.ChosenInputs.Inputs); err != nil {
+		return nil, err
+	}
+
+	if isChained {
+		chainedTransformer.AddChangeUtxos(changeUtxos)
 	}
 
 	return txInfo, nil
 }
 
+// reserveInputs reserves inputs of the built tx in the shared reservation store or in the local utxo cache
+func reserveInputs(
+	ctx context.Context, utxosTransformer utxotransformer.IUtxosTransformer, inputs []wallet.TxInput,
+) (err error) {
+	if utxosTransformer == nil {
+		return nil
+	}
+
+	ctx, span := telemetry.StartSpan(ctx, "utxoCache.Reserve", attribute.Int("inputs", len(inputs)))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	sharedTransformer, ok := utxosTransformer.(*utxotransformer.SharedUtxosTransformer)
+	if !ok {
+		utxosTransformer.UpdateUtxos(inputs)
+
+		return nil
+	}
+
+	reserved, err := sharedTransformer.Reserve(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("failed to reserve utxos: %w", err)
+	} else if !reserved {
+		return errUtxosAlreadyReserved
+	}
+
+	return nil
+}
+
 func (c *CardanoTxControllerImpl) calculateTxFee(
 	ctx context.Context, requestBody request.CreateBridgingTxRequest) (
-	*sendtx.TxFeeInfo, *sendtx.BridgingRequestMetadata, error,
+	*cardanotx.BridgingTxFeeBreakdown, error,
 ) {
-	txSender, receivers, err := c.getTxSenderAndReceivers(
-		requestBody, utils.GetUtxosTransformer(requestBody, c.appConfig, c.usedUtxoCacher))
-	if err != nil {
-		return nil, nil, err
-	}
-
-	txFeeInfo, metadata, err := txSender.CalculateBridgingTxFee(
-		ctx,
-		sendtx.BridgingTxDto{
-			SrcChainID:             requestBody.SourceChainID,
-			DstChainID:             requestBody.DestinationChainID,
-			SenderAddr:             requestBody.SenderAddr,
-			SenderAddrPolicyScript: requestBody.SenderAddrPolicyScript,
-			Receivers:              receivers,
-			BridgingFee:            requestBody.BridgingFee,
-		},
-	)
+	utxosTransformer, err := utils.GetUtxosTransformer(
+		ctx, requestBody, c.appConfig, c.usedUtxoCacher, c.reservationStore)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to calculate tx fee: %w", err)
+		return nil, err
 	}
 
-	return txFeeInfo, metadata, nil
-}
+	txSenderChainsConfig, err := c.appConfig.ToSendTxChainConfigs(ctx, requestBody.UseFallback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate configuration")
+	}
 
-func (c *CardanoTxControllerImpl) getTxSenderAndReceivers(
-	requestBody request.CreateBridgingTxRequest,
-	utxosTransformer sendtx.IUtxosTransformer,
-) (
-	*sendtx.TxSender, []sendtx.BridgingTxReceiver, error,
-) {
-	txSenderChainsConfig, err := c.appConfig.ToSendTxChainConfigs(requestBody.UseFallback)
+	// protocol parameters are retrieved here so they can be used for the tx size estimation as well
+	srcConfig := txSenderChainsConfig[requestBody.SourceChainID]
+
+	srcConfig.ProtocolParameters, err = srcConfig.TxProvider.GetProtocolParameters(ctx)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate configuration")
+		return nil, fmt.Errorf("failed to retrieve protocol parameters: %w", err)
 	}
 
+	txSenderChainsConfig[requestBody.SourceChainID] = srcConfig
+
 	txSender := sendtx.NewTxSender(txSenderChainsConfig, sendtx.WithUtxosTransformer(utxosTransformer))
 
-	receivers := make([]sendtx.BridgingTxReceiver, len(requestBody.Transactions))
-	for i, tx := range requestBody.Transactions {
-		receivers[i] = sendtx.BridgingTxReceiver{
-			Addr:   tx.Addr,
-			Amount: tx.Amount,
-		}
+	// fee is calculated for the same metadata and outputs as the ones of the tx created for the request
+	var (
+		txFeeInfo *sendtx.TxFeeInfo
+		metadata  *sendtx.BridgingRequestMetadata
+	)
+
+	sendTxCtx, span := telemetry.StartSpan(ctx, "sendtx.CalculateBridgingTxFee", getChainPairAttributes(requestBody)...)
+
+	metadataVersion, isVersioned := c.getBridgingTxMetadataVersion(requestBody)
+	if !isVersioned {
+		txFeeInfo, metadata, err = txSender.CalculateBridgingTxFee(sendTxCtx, getBridgingTxDto(requestBody))
+	} else {
+		span.SetAttributes(attribute.Int("metadataVersion", int(metadataVersion)))
+
+		txFeeInfo, metadata, err = cardanotx.CalculateVersionedBridgingTxFee(sendTxCtx, txSender,
+			srcConfig, getBridgingTxDto(requestBody), requestBody.RefundAddr, metadataVersion)
+	}
+
+	telemetry.EndSpan(span, err)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate tx fee: %w", err)
 	}
 
-	return txSender, receivers, nil
+	return cardanotx.NewBridgingTxFeeBreakdown(
+		txFeeInfo, metadata, requestBody.BridgingFee, srcConfig.ProtocolParameters)
 }
 
-func getSkipUtxos(
-	requestBody request.CreateBridgingTxRequest, appConfig *core.AppConfig, usedUtxoCacher *utxotransformer.UsedUtxoCacher,
-) (skipUtxos []wallet.TxInput, useCaching bool) {
-	shouldUseUsedUtxoCacher := false
+// getBridgingTxMetadataVersion returns the metadata version of the bridging tx and whether the tx is built
+// by the versioned builder, because metadata newer than version 1 and refund address are not supported by sendtx
+func (c *CardanoTxControllerImpl) getBridgingTxMetadataVersion(
+	requestBody request.CreateBridgingTxRequest,
+) (common.MetadataVersion, bool) {
+	metadataVersion := c.appConfig.GetBridgingSettings().GetMetadataVersion()
 
-	if desiredKey := requestBody.UTXOCacheKey; desiredKey != "" {
-		for _, key := range appConfig.APIConfig.UTXOCacheKeys {
-			if key == desiredKey {
-				shouldUseUsedUtxoCacher = true
+	return metadataVersion, metadataVersion != common.MetadataVersion1 || requestBody.RefundAddr != ""
+}
 
-				break
-			}
-		}
+func getBridgingTxDto(requestBody request.CreateBridgingTxRequest) sendtx.BridgingTxDto {
+	return sendtx.BridgingTxDto{
+		SrcChainID:             requestBody.SourceChainID,
+		DstChainID:             requestBody.DestinationChainID,
+		SenderAddr:             requestBody.SenderAddr,
+		SenderAddrPolicyScript: requestBody.SenderAddrPolicyScript,
+		Receivers:              getTxReceivers(requestBody),
+		BridgingFee:            requestBody.BridgingFee,
 	}
+}
 
-	if shouldUseUsedUtxoCacher {
-		return usedUtxoCacher.Get(requestBody.SenderAddr), true
+func getChainPairAttributes(requestBody request.CreateBridgingTxRequest) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("sourceChainId", requestBody.SourceChainID),
+		attribute.String("destinationChainId", requestBody.DestinationChainID),
 	}
+}
 
-	return common.Map(requestBody.SkipUtxos, func(x request.UtxoRequest) wallet.TxInput {
-		return wallet.TxInput{
-			Hash:  x.Hash,
-			Index: x.Index,
+func getTxReceivers(requestBody request.CreateBridgingTxRequest) []sendtx.BridgingTxReceiver {
+	receivers := make([]sendtx.BridgingTxReceiver, len(requestBody.Transactions))
+	for i, tx := range requestBody.Transactions {
+		receivers[i] = sendtx.BridgingTxReceiver{
+			Addr:   tx.Addr,
+			Amount: tx.Amount,
 		}
-	}), false
+	}
+
+	return receivers
 }
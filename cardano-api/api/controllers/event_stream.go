@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	eventstream "github.com/Ethernal-Tech/cardano-api/api/event_stream"
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	"github.com/Ethernal-Tech/cardano-api/api/utils"
+	"github.com/Ethernal-Tech/cardano-api/common"
+)
+
+const (
+	eventStreamHeartbeatInterval = 15 * time.Second
+	eventStreamSnapshot          = "snapshot"
+)
+
+// events streams server-sent events. The snapshot of the settings, multisig addresses and validator change status
+// is sent first, followed by the events when any of them changes
+func (c *CardanoTxControllerImpl) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		utils.WriteErrorResponse(
+			w, r, http.StatusInternalServerError, errors.New("streaming is not supported"), c.logger)
+
+		return
+	}
+
+	subscriber, err := c.eventBroker.Subscribe(common.APIKeyIDFromContext(r.Context()))
+	if err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusTooManyRequests, err, c.logger)
+
+		return
+	}
+
+	defer c.eventBroker.Unsubscribe(subscriber)
+
+	logger := utils.GetRequestLogger(r.Context(), c.logger)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	snapshot := response.NewEventStreamSnapshotResponse(
+		c.appConfig, c.validatorChangeTracker.IsValidatorChangeInProgress())
+	if err := writeServerSentEvent(w, eventStreamSnapshot, snapshot); err != nil {
+		logger.Debug("failed to write event", "err", err)
+
+		return
+	}
+
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, err = io.WriteString(w, ": heartbeat\n\n")
+		case event, ok := <-subscriber.Events():
+			if !ok {
+				logger.Debug("event stream closed because the client does not keep up with the events")
+
+				return
+			}
+
+			err = writeServerSentEvent(w, event.Type, c.getEventData(event))
+		}
+
+		if err != nil {
+			logger.Debug("failed to write event", "err", err)
+
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+func (c *CardanoTxControllerImpl) getEventData(event eventstream.Event) any {
+	switch event.Type {
+	case eventstream.EventSettings:
+		return response.NewSettingsResponse(c.appConfig)
+	case eventstream.EventMultisigAddresses:
+		return response.NewMultisigAddressesResponse(c.appConfig)
+	default:
+		return &response.ValidatorChangeStatusReponse{InProgress: event.ValidatorChangeInProgress}
+	}
+}
+
+func writeServerSentEvent(w io.Writer, eventType string, data any) error {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, bytes)
+
+	return err
+}
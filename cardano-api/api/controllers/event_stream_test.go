@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	eventstream "github.com/Ethernal-Tech/cardano-api/api/event_stream"
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+type serverSentEvent struct {
+	eventType string
+	data      string
+}
+
+func readServerSentEvent(t *testing.T, reader *bufio.Reader) serverSentEvent {
+	t.Helper()
+
+	var event serverSentEvent
+
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "":
+			return event
+		case strings.HasPrefix(line, "event: "):
+			event.eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	appConfig := &core.AppConfig{
+		CardanoChains: map[string]*core.CardanoChainConfig{
+			common.ChainIDStrPrime: {
+				IsEnabled:         true,
+				BridgingAddresses: core.BridgingAddresses{BridgingAddress: "addr1", FeeAddress: "addr2"},
+			},
+		},
+	}
+	validatorChangeTracker := core.NewValidatorChangeTracker()
+	validatorChangeTracker.SetValidatorChangeStatus(true)
+
+	broker := eventstream.NewBroker(1)
+	controller := NewCardanoTxController(
+		appConfig, nil, nil, nil, hclog.NewNullLogger(), validatorChangeTracker, nil, broker)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		controller.events(w, r.WithContext(common.ContextWithAPIKeyID(r.Context(), "key1")))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+
+	event := readServerSentEvent(t, reader)
+	require.Equal(t, eventStreamSnapshot, event.eventType)
+
+	var snapshot response.EventStreamSnapshotResponse
+
+	require.NoError(t, json.Unmarshal([]byte(event.data), &snapshot))
+	require.True(t, snapshot.ValidatorChangeInProgress)
+	require.Equal(t, []string{common.ChainIDStrPrime}, snapshot.Settings.EnabledChains)
+	require.Equal(t, "addr1", snapshot.MultisigAddresses[common.ChainIDStrPrime].BridgingAddress)
+
+	// second stream of the same api key exceeds the limit
+	rec := httptest.NewRecorder()
+	controller.events(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(
+		common.ContextWithAPIKeyID(context.Background(), "key1")))
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	broker.NotifyValidatorChange(false)
+
+	event = readServerSentEvent(t, reader)
+	require.Equal(t, eventstream.EventValidatorChange, event.eventType)
+	require.JSONEq(t, `{"inProgress":false}`, event.data)
+
+	broker.NotifyMultisigAddressesChanged()
+
+	event = readServerSentEvent(t, reader)
+	require.Equal(t, eventstream.EventMultisigAddresses, event.eventType)
+	require.Contains(t, event.data, `"addr1"`)
+}
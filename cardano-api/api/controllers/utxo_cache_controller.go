@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	"github.com/Ethernal-Tech/cardano-api/api/utils"
+	utxotransformer "github.com/Ethernal-Tech/cardano-api/api/utxo_transformer"
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/hashicorp/go-hclog"
+)
+
+// UtxoCacheControllerImpl exposes admin endpoints for inspecting and managing utxo reservations
+type UtxoCacheControllerImpl struct {
+	usedUtxoCacher   *utxotransformer.UsedUtxoCacher
+	reservationStore utxotransformer.IReservationStore
+	logger           hclog.Logger
+}
+
+var _ core.APIController = (*UtxoCacheControllerImpl)(nil)
+
+func NewUtxoCacheController(
+	usedUtxoCacher *utxotransformer.UsedUtxoCacher,
+	reservationStore utxotransformer.IReservationStore,
+	logger hclog.Logger,
+) *UtxoCacheControllerImpl {
+	return &UtxoCacheControllerImpl{
+		usedUtxoCacher:   usedUtxoCacher,
+		reservationStore: reservationStore,
+		logger:           logger,
+	}
+}
+
+func (*UtxoCacheControllerImpl) GetPathPrefix() string {
+	return "UtxoCache"
+}
+
+func (c *UtxoCacheControllerImpl) GetEndpoints() []*core.APIEndpoint {
+	return []*core.APIEndpoint{
+		{Path: "GetReservations", Method: http.MethodGet, Handler: c.getReservations, AdminOnly: true},
+		{Path: "Release", Method: http.MethodPost, Handler: c.release, AdminOnly: true},
+		{Path: "GetStats", Method: http.MethodGet, Handler: c.getStats, AdminOnly: true},
+	}
+}
+
+// getReservations returns reservations from the shared reservation store if it is configured,
+// otherwise from the local utxo cache
+func (c *UtxoCacheControllerImpl) getReservations(w http.ResponseWriter, r *http.Request) {
+	addr := r.URL.Query().Get("addr")
+
+	var reservations []utxotransformer.Reservation
+
+	if c.reservationStore != nil {
+		var err error
+
+		reservations, err = c.reservationStore.GetReservations(r.Context(), addr)
+		if err != nil {
+			utils.WriteErrorResponse(
+				w, r, http.StatusInternalServerError, fmt.Errorf("failed to retrieve reservations: %w", err), c.logger)
+
+			return
+		}
+	} else {
+		reservations = c.usedUtxoCacher.GetReservations(addr)
+	}
+
+	utils.WriteResponse(
+		w, r, http.StatusOK,
+		response.NewUtxoCacheReservationsResponse(reservations, time.Now().UTC()),
+		c.logger)
+}
+
+func (c *UtxoCacheControllerImpl) release(w http.ResponseWriter, r *http.Request) {
+	requestBody, ok := utils.DecodeModel[request.ReleaseUtxoCacheRequest](w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	if requestBody.Addr == "" {
+		utils.WriteErrorResponse(w, r, http.StatusBadRequest, errors.New("validation error. err: addr not specified"), c.logger)
+
+		return
+	}
+
+	released, err := utxotransformer.ReleaseReservations(
+		r.Context(), c.usedUtxoCacher, c.reservationStore, requestBody.Addr,
+		common.Map(requestBody.Inputs, func(x request.UtxoRequest) wallet.TxInput {
+			return wallet.TxInput{
+				Hash:  x.Hash,
+				Index: x.Index,
+			}
+		}))
+	if err != nil {
+		utils.WriteErrorResponse(
+			w, r, http.StatusInternalServerError, fmt.Errorf("failed to release reserved utxos: %w", err), c.logger)
+
+		return
+	}
+
+	utils.GetRequestLogger(r.Context(), c.logger).Info("utxo reservations released",
+		"addr", requestBody.Addr, "released", released, "apiKeyID", common.APIKeyIDFromContext(r.Context()))
+
+	utils.WriteResponse(w, r, http.StatusOK, &response.ReleaseUtxoCacheResponse{Released: released}, c.logger)
+}
+
+// getStats counts reserved inputs in the shared reservation store if it is configured
+func (c *UtxoCacheControllerImpl) getStats(w http.ResponseWriter, r *http.Request) {
+	var stats utxotransformer.CacheStats
+
+	if c.reservationStore != nil {
+		var err error
+
+		stats, err = utxotransformer.GetSharedCacheStats(r.Context(), c.usedUtxoCacher, c.reservationStore)
+		if err != nil {
+			utils.WriteErrorResponse(
+				w, r, http.StatusInternalServerError, fmt.Errorf("failed to retrieve reservations: %w", err), c.logger)
+
+			return
+		}
+	} else {
+		stats = c.usedUtxoCacher.GetStats()
+	}
+
+	utils.WriteResponse(
+		w, r, http.StatusOK,
+		response.NewUtxoCacheStatsResponse(stats, time.Now().UTC()), c.logger)
+}
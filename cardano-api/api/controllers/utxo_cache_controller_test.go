@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	utxotransformer "github.com/Ethernal-Tech/cardano-api/api/utxo_transformer"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hashicorp/go-hclog"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUtxoCacheControllerWithReservationStore(t *testing.T) {
+	const addr = "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw"
+
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := utxotransformer.NewRedisReservationStore(
+		redis.NewClient(&redis.Options{Addr: server.Addr()}), "", time.Minute)
+
+	defer store.Close()
+
+	inputs := []wallet.TxInput{
+		wallet.NewTxInput(strings.Repeat("1", 64), 0),
+		wallet.NewTxInput(strings.Repeat("2", 64), 1),
+	}
+
+	// inputs are reserved by another replica, so the local cache of the controller is empty
+	ok, err := store.Reserve(ctx, addr, inputs, utxotransformer.ReservationInfo{APIKeyID: "key", RequestID: "req"})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	controller := NewUtxoCacheController(utxotransformer.NewUsedUtxoCacher(time.Minute), store, hclog.NewNullLogger())
+
+	t.Run("get reservations", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		controller.getReservations(recorder, httptest.NewRequest(http.MethodGet, "/api/UtxoCache/GetReservations", nil))
+
+		var result response.UtxoCacheReservationsResponse
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+		require.Len(t, result.Addresses, 1)
+		require.Equal(t, addr, result.Addresses[0].Addr)
+		require.Len(t, result.Addresses[0].Inputs, 2)
+		require.Equal(t, "key", result.Addresses[0].Inputs[0].APIKeyID)
+		require.Equal(t, "req", result.Addresses[0].Inputs[0].RequestID)
+	})
+
+	t.Run("get stats", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		controller.getStats(recorder, httptest.NewRequest(http.MethodGet, "/api/UtxoCache/GetStats", nil))
+
+		var result response.UtxoCacheStatsResponse
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+		require.Equal(t, 1, result.AddressesCount)
+		require.Equal(t, 2, result.ReservedInputs)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		body, err := json.Marshal(request.ReleaseUtxoCacheRequest{
+			Addr:   addr,
+			Inputs: []request.UtxoRequest{{Hash: inputs[0].Hash, Index: inputs[0].Index}},
+		})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		controller.release(recorder, httptest.NewRequest(http.MethodPost, "/api/UtxoCache/Release", bytes.NewReader(body)))
+
+		var result response.ReleaseUtxoCacheResponse
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+		require.Equal(t, 1, result.Released)
+
+		reserved, err := store.Get(ctx, addr)
+		require.NoError(t, err)
+		require.Equal(t, inputs[1:], reserved)
+	})
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	"github.com/Ethernal-Tech/cardano-api/api/utils"
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-api/webhook"
+	"github.com/hashicorp/go-hclog"
+)
+
+// WebhookControllerImpl manages the webhook of the calling api key
+type WebhookControllerImpl struct {
+	webhookService *webhook.Service
+	logger         hclog.Logger
+}
+
+var _ core.APIController = (*WebhookControllerImpl)(nil)
+
+func NewWebhookController(
+	webhookService *webhook.Service,
+	logger hclog.Logger,
+) *WebhookControllerImpl {
+	return &WebhookControllerImpl{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+func (*WebhookControllerImpl) GetPathPrefix() string {
+	return "Webhook"
+}
+
+func (c *WebhookControllerImpl) GetEndpoints() []*core.APIEndpoint {
+	return []*core.APIEndpoint{
+		{Path: "Register", Method: http.MethodPost, Handler: c.register},
+		{Path: "Unregister", Method: http.MethodPost, Handler: c.unregister},
+		{Path: "Get", Method: http.MethodGet, Handler: c.get},
+		{Path: "GetDeadLetters", Method: http.MethodGet, Handler: c.getDeadLetters},
+		{Path: "RedeliverDeadLetters", Method: http.MethodPost, Handler: c.redeliverDeadLetters},
+	}
+}
+
+func (c *WebhookControllerImpl) register(w http.ResponseWriter, r *http.Request) {
+	requestBody, ok := utils.DecodeModel[request.RegisterWebhookRequest](w, r, c.logger)
+	if !ok {
+		return
+	}
+
+	if err := c.webhookService.ValidateURL(r.Context(), requestBody.URL); err != nil {
+		utils.WriteErrorResponse(
+			w, r, http.StatusBadRequest, fmt.Errorf("validation error. err: %w", err), c.logger)
+
+		return
+	}
+
+	apiKeyID := common.APIKeyIDFromContext(r.Context())
+
+	subscription, err := c.webhookService.Register(apiKeyID, requestBody.URL)
+	if err != nil {
+		utils.WriteErrorResponse(w, r, http.StatusInternalServerError, err, c.logger)
+
+		return
+	}
+
+	utils.GetRequestLogger(r.Context(), c.logger).Info("webhook registered",
+		"apiKeyID", apiKeyID, "url", requestBody.URL)
+
+	utils.WriteResponse(w, r, http.StatusOK, response.NewRegisterWebhookResponse(subscription), c.logger)
+}
+
+func (c *WebhookControllerImpl) unregister(w http.ResponseWriter, r *http.Request) {
+	apiKeyID := common.APIKeyIDFromContext(r.Context())
+
+	if err := c.webhookService.Unregister(apiKeyID); err != nil {
+		c.writeError(w, r, err)
+
+		return
+	}
+
+	utils.GetRequestLogger(r.Context(), c.logger).Info("webhook unregistered", "apiKeyID", apiKeyID)
+
+	utils.WriteResponse(w, r, http.StatusOK, struct{}{}, c.logger)
+}
+
+func (c *WebhookControllerImpl) get(w http.ResponseWriter, r *http.Request) {
+	subscription, pending, deadLetters, trackedTxs, err := c.webhookService.GetSubscription(
+		common.APIKeyIDFromContext(r.Context()))
+	if err != nil {
+		c.writeError(w, r, err)
+
+		return
+	}
+
+	utils.WriteResponse(
+		w, r, http.StatusOK,
+		response.NewWebhookResponse(subscription, pending, deadLetters, trackedTxs), c.logger)
+}
+
+func (c *WebhookControllerImpl) getDeadLetters(w http.ResponseWriter, r *http.Request) {
+	deadLetters := c.webhookService.GetDeadLetters(common.APIKeyIDFromContext(r.Context()))
+
+	utils.WriteResponse(w, r, http.StatusOK, response.NewWebhookDeadLettersResponse(deadLetters), c.logger)
+}
+
+func (c *WebhookControllerImpl) redeliverDeadLetters(w http.ResponseWriter, r *http.Request) {
+	redelivered, err := c.webhookService.RedeliverDeadLetters(common.APIKeyIDFromContext(r.Context()))
+	if err != nil {
+		c.writeError(w, r, err)
+
+		return
+	}
+
+	utils.WriteResponse(
+		w, r, http.StatusOK, &response.RedeliverWebhookDeadLettersResponse{Redelivered: redelivered}, c.logger)
+}
+
+func (c *WebhookControllerImpl) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, webhook.ErrNotRegistered) {
+		status = http.StatusNotFound
+	}
+
+	utils.WriteErrorResponse(w, r, status, err, c.logger)
+}
@@ -0,0 +1,128 @@
+package eventstream
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+)
+
+const (
+	EventSettings          = "settings"
+	EventMultisigAddresses = "multisigAddresses"
+	EventValidatorChange   = "validatorChange"
+
+	defaultMaxStreamsPerKey = 5
+	// subscriberBufferSize is the number of events waiting to be sent before the slow subscriber is dropped
+	subscriberBufferSize = 16
+)
+
+var ErrTooManyStreams = errors.New("too many event streams opened for the api key")
+
+type Event struct {
+	Type string
+	// ValidatorChangeInProgress is set for validator change event
+	ValidatorChangeInProgress bool
+}
+
+type Subscriber struct {
+	apiKeyID string
+	ch       chan Event
+}
+
+// Events returns the channel of events which is closed if the subscriber does not keep up with the events
+func (s *Subscriber) Events() <-chan Event {
+	return s.ch
+}
+
+// Broker fans out settings, multisig addresses and validator change notifications to the event streams
+type Broker struct {
+	maxStreamsPerKey int
+
+	lock          sync.Mutex
+	subscribers   map[*Subscriber]struct{}
+	streamsPerKey map[string]int
+}
+
+var (
+	_ common.ConfigChangeNotifier    = (*Broker)(nil)
+	_ common.ValidatorChangeNotifier = (*Broker)(nil)
+)
+
+func NewBroker(maxStreamsPerKey int) *Broker {
+	if maxStreamsPerKey <= 0 {
+		maxStreamsPerKey = defaultMaxStreamsPerKey
+	}
+
+	return &Broker{
+		maxStreamsPerKey: maxStreamsPerKey,
+		subscribers:      map[*Subscriber]struct{}{},
+		streamsPerKey:    map[string]int{},
+	}
+}
+
+// Subscribe returns ErrTooManyStreams if the api key already has the maximal number of streams opened
+func (b *Broker) Subscribe(apiKeyID string) (*Subscriber, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.streamsPerKey[apiKeyID] >= b.maxStreamsPerKey {
+		return nil, ErrTooManyStreams
+	}
+
+	subscriber := &Subscriber{
+		apiKeyID: apiKeyID,
+		ch:       make(chan Event, subscriberBufferSize),
+	}
+
+	b.subscribers[subscriber] = struct{}{}
+	b.streamsPerKey[apiKeyID]++
+
+	return subscriber, nil
+}
+
+func (b *Broker) Unsubscribe(subscriber *Subscriber) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.remove(subscriber)
+}
+
+func (b *Broker) NotifySettingsChanged() {
+	b.publish(Event{Type: EventSettings})
+}
+
+func (b *Broker) NotifyMultisigAddressesChanged() {
+	b.publish(Event{Type: EventMultisigAddresses})
+}
+
+func (b *Broker) NotifyValidatorChange(inProgress bool) {
+	b.publish(Event{Type: EventValidatorChange, ValidatorChangeInProgress: inProgress})
+}
+
+func (b *Broker) publish(event Event) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber.ch <- event:
+		default:
+			b.remove(subscriber)
+		}
+	}
+}
+
+// remove closes the channel of the subscriber. Lock must be held
+func (b *Broker) remove(subscriber *Subscriber) {
+	if _, exists := b.subscribers[subscriber]; !exists {
+		return
+	}
+
+	delete(b.subscribers, subscriber)
+	close(subscriber.ch)
+
+	if b.streamsPerKey[subscriber.apiKeyID]--; b.streamsPerKey[subscriber.apiKeyID] <= 0 {
+		delete(b.streamsPerKey, subscriber.apiKeyID)
+	}
+}
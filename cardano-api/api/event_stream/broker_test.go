@@ -0,0 +1,73 @@
+package eventstream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker(t *testing.T) {
+	t.Run("streams per key limit", func(t *testing.T) {
+		broker := NewBroker(2)
+
+		s1, err := broker.Subscribe("key1")
+		require.NoError(t, err)
+
+		_, err = broker.Subscribe("key1")
+		require.NoError(t, err)
+
+		_, err = broker.Subscribe("key1")
+		require.ErrorIs(t, err, ErrTooManyStreams)
+
+		_, err = broker.Subscribe("key2")
+		require.NoError(t, err)
+
+		broker.Unsubscribe(s1)
+		broker.Unsubscribe(s1)
+
+		_, err = broker.Subscribe("key1")
+		require.NoError(t, err)
+	})
+
+	t.Run("events are fanned out", func(t *testing.T) {
+		broker := NewBroker(0)
+
+		s1, err := broker.Subscribe("key1")
+		require.NoError(t, err)
+
+		s2, err := broker.Subscribe("key2")
+		require.NoError(t, err)
+
+		broker.NotifySettingsChanged()
+		broker.NotifyMultisigAddressesChanged()
+		broker.NotifyValidatorChange(true)
+
+		for _, s := range []*Subscriber{s1, s2} {
+			require.Equal(t, Event{Type: EventSettings}, <-s.Events())
+			require.Equal(t, Event{Type: EventMultisigAddresses}, <-s.Events())
+			require.Equal(t, Event{Type: EventValidatorChange, ValidatorChangeInProgress: true}, <-s.Events())
+		}
+	})
+
+	t.Run("slow subscriber is dropped", func(t *testing.T) {
+		broker := NewBroker(1)
+
+		slow, err := broker.Subscribe("key1")
+		require.NoError(t, err)
+
+		for i := 0; i <= subscriberBufferSize; i++ {
+			broker.NotifySettingsChanged()
+		}
+
+		count := 0
+		for range slow.Events() {
+			count++
+		}
+
+		require.Equal(t, subscriberBufferSize, count)
+
+		// dropped stream does not count against the limit
+		_, err = broker.Subscribe("key1")
+		require.NoError(t, err)
+	})
+}
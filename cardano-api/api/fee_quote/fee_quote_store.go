@@ -0,0 +1,152 @@
+package feequote
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+)
+
+var (
+	ErrQuoteExpired  = errors.New("quote expired")
+	ErrQuoteMismatch = errors.New("invalid quote or request does not match the quote")
+)
+
+// Quote is the tx built for the fee quote. Its inputs are held in the used utxo cacher until the quote expires
+type Quote struct {
+	TxRaw       []byte
+	TxHash      string
+	Inputs      []wallet.TxInput
+	SenderAddr  string
+	BridgingFee uint64
+	ExpiresAt   time.Time
+
+	requestHash string
+	apiKeyID    string
+}
+
+// FeeQuoteStore keeps quotes in memory. Quote ids are signed so they can not be forged or used for another request
+type FeeQuoteStore struct {
+	secret  []byte
+	timeout time.Duration
+	lock    sync.Mutex
+	quotes  map[string]*Quote
+}
+
+// NewFeeQuoteStore creates quote store. Random secret is generated if the secret is empty
+func NewFeeQuoteStore(secret []byte, timeout time.Duration) *FeeQuoteStore {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		_, _ = rand.Read(secret)
+	}
+
+	return &FeeQuoteStore{
+		secret:  secret,
+		timeout: timeout,
+		quotes:  map[string]*Quote{},
+	}
+}
+
+// Add stores the quote for the request and returns signed quote id
+func (s *FeeQuoteStore) Add(quote Quote, requestHash string, apiKeyID string) (string, *Quote) {
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+
+	quote.ExpiresAt = time.Now().UTC().Add(s.timeout)
+	quote.requestHash = requestHash
+	quote.apiKeyID = apiKeyID
+
+	nonceStr := hex.EncodeToString(nonce)
+	expiresAtStr := strconv.FormatInt(quote.ExpiresAt.UnixMilli(), 10)
+	quoteID := fmt.Sprintf("%s.%s.%s",
+		nonceStr, expiresAtStr, s.sign(nonceStr, expiresAtStr, requestHash, apiKeyID))
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.removeExpired(time.Now().UTC())
+
+	s.quotes[nonceStr] = &quote
+
+	return quoteID, &quote
+}
+
+// Redeem returns the quote and removes it from the store so it can be used only once
+func (s *FeeQuoteStore) Redeem(quoteID string, requestHash string, apiKeyID string) (*Quote, error) {
+	parts := strings.Split(quoteID, ".")
+	if len(parts) != 3 {
+		return nil, ErrQuoteMismatch
+	}
+
+	expectedSignature := s.sign(parts[0], parts[1], requestHash, apiKeyID)
+	if !hmac.Equal([]byte(parts[2]), []byte(expectedSignature)) {
+		return nil, ErrQuoteMismatch
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrQuoteMismatch
+	}
+
+	now := time.Now().UTC()
+	if now.UnixMilli() >= expiresAt {
+		return nil, ErrQuoteExpired
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	quote, exists := s.quotes[parts[0]]
+	if !exists || !now.Before(quote.ExpiresAt) {
+		// already redeemed or the api has been restarted
+		return nil, ErrQuoteExpired
+	}
+
+	delete(s.quotes, parts[0])
+
+	return quote, nil
+}
+
+// Count returns number of not redeemed quotes
+func (s *FeeQuoteStore) Count() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.quotes)
+}
+
+func (s *FeeQuoteStore) removeExpired(now time.Time) {
+	for id, quote := range s.quotes {
+		if !now.Before(quote.ExpiresAt) {
+			delete(s.quotes, id)
+		}
+	}
+}
+
+func (s *FeeQuoteStore) sign(nonce, expiresAt, requestHash, apiKeyID string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join([]string{nonce, expiresAt, requestHash, apiKeyID}, "|")))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GetRequestHash returns hash of the json representation of the request
+func GetRequestHash(request any) (string, error) {
+	bytes, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(bytes)
+
+	return hex.EncodeToString(hash[:]), nil
+}
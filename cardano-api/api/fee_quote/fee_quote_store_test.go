@@ -0,0 +1,90 @@
+package feequote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeQuoteStore(t *testing.T) {
+	quote := Quote{
+		TxRaw:       []byte{1, 2, 3},
+		TxHash:      "aa",
+		Inputs:      []wallet.TxInput{wallet.NewTxInput("0x1", 0)},
+		SenderAddr:  "addr",
+		BridgingFee: 1_000_000,
+	}
+
+	t.Run("redeem once", func(t *testing.T) {
+		store := NewFeeQuoteStore([]byte("secret"), time.Minute)
+
+		quoteID, added := store.Add(quote, "hash", "key")
+		require.True(t, added.ExpiresAt.After(time.Now().UTC()))
+		require.Equal(t, 1, store.Count())
+
+		redeemed, err := store.Redeem(quoteID, "hash", "key")
+		require.NoError(t, err)
+		require.Equal(t, quote.TxRaw, redeemed.TxRaw)
+		require.Equal(t, quote.Inputs, redeemed.Inputs)
+		require.Equal(t, 0, store.Count())
+
+		_, err = store.Redeem(quoteID, "hash", "key")
+		require.ErrorIs(t, err, ErrQuoteExpired)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		store := NewFeeQuoteStore([]byte("secret"), time.Minute)
+
+		quoteID, _ := store.Add(quote, "hash", "key")
+
+		_, err := store.Redeem(quoteID, "other hash", "key")
+		require.ErrorIs(t, err, ErrQuoteMismatch)
+
+		_, err = store.Redeem(quoteID, "hash", "other key")
+		require.ErrorIs(t, err, ErrQuoteMismatch)
+
+		_, err = NewFeeQuoteStore([]byte("other secret"), time.Minute).Redeem(quoteID, "hash", "key")
+		require.ErrorIs(t, err, ErrQuoteMismatch)
+
+		_, err = store.Redeem("invalid", "hash", "key")
+		require.ErrorIs(t, err, ErrQuoteMismatch)
+
+		_, err = store.Redeem(quoteID, "hash", "key")
+		require.NoError(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		store := NewFeeQuoteStore(nil, time.Millisecond*20)
+
+		quoteID, _ := store.Add(quote, "hash", "key")
+
+		time.Sleep(time.Millisecond * 30)
+
+		_, err := store.Redeem(quoteID, "hash", "key")
+		require.ErrorIs(t, err, ErrQuoteExpired)
+
+		store.Add(quote, "hash", "key")
+		require.Equal(t, 1, store.Count())
+	})
+}
+
+func TestGetRequestHash(t *testing.T) {
+	type req struct {
+		A string
+		B uint64
+	}
+
+	first, err := GetRequestHash(req{A: "a", B: 1})
+	require.NoError(t, err)
+
+	second, err := GetRequestHash(req{A: "a", B: 1})
+	require.NoError(t, err)
+
+	third, err := GetRequestHash(req{A: "a", B: 2})
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+	require.NotEqual(t, first, third)
+}
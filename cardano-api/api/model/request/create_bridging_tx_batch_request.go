@@ -0,0 +1,48 @@
+package request
+
+import (
+	cardanowallet "github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+)
+
+type CreateBridgingTxBatchItemRequest struct {
+	DestinationChainID string                               `json:"destinationChainId"`
+	Transactions       []CreateBridgingTxTransactionRequest `json:"transactions"`
+	BridgingFee        uint64                               `json:"bridgingFee"`
+	UseFallback        bool                                 `json:"useFallback"`
+}
+
+type CreateBridgingTxBatchRequest struct {
+	SenderAddr             string                             `json:"senderAddr"`
+	SenderAddrPolicyScript *cardanowallet.PolicyScript        `json:"senderAddrPolicyScript"`
+	SourceChainID          string                             `json:"sourceChainId"`
+	UTXOCacheKey           string                             `json:"utxoCacheKey"`
+	SkipUtxos              []UtxoRequest                      `json:"skipUtxos"`
+	UseChaining            bool                               `json:"useChaining"`
+	Requests               []CreateBridgingTxBatchItemRequest `json:"requests"`
+}
+
+// ToCreateBridgingTxRequest creates single bridging request from the batch item
+// skipUtxos contains inputs which should not be used (request skip utxos and inputs reserved by previous items)
+func (r CreateBridgingTxBatchRequest) ToCreateBridgingTxRequest(
+	idx int, skipUtxos []UtxoRequest,
+) CreateBridgingTxRequest {
+	item := r.Requests[idx]
+
+	return CreateBridgingTxRequest{
+		SenderAddr:             r.SenderAddr,
+		SenderAddrPolicyScript: r.SenderAddrPolicyScript,
+		SourceChainID:          r.SourceChainID,
+		DestinationChainID:     item.DestinationChainID,
+		Transactions:           item.Transactions,
+		BridgingFee:            item.BridgingFee,
+		UseFallback:            item.UseFallback,
+		UTXOCacheKey:           r.UTXOCacheKey,
+		SkipUtxos:              skipUtxos,
+		UseChaining:            r.UseChaining,
+	}
+}
+
+// GetChainPair returns only the source chain because every batch item has its own destination chain
+func (r CreateBridgingTxBatchRequest) GetChainPair() (string, string) {
+	return r.SourceChainID, ""
+}
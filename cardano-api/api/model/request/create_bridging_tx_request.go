@@ -4,6 +4,13 @@ import (
 	cardanowallet "github.com/Ethernal-Tech/cardano-infrastructure/wallet"
 )
 
+const (
+	// BridgingTxModeSendMax bridges all the spendable funds of the sender reduced by the fees to the only receiver
+	BridgingTxModeSendMax = "sendMax"
+	// BridgingTxModeNetAmount treats the amount of the only receiver as the total debit of the sender
+	BridgingTxModeNetAmount = "netAmount"
+)
+
 type CreateBridgingTxTransactionRequest struct {
 	Addr   string `json:"addr"`
 	Amount uint64 `json:"amount"`
@@ -24,4 +31,18 @@ type CreateBridgingTxRequest struct {
 	UseFallback            bool                                 `json:"useFallback"`
 	UTXOCacheKey           string                               `json:"utxoCacheKey"`
 	SkipUtxos              []UtxoRequest                        `json:"skipUtxos"`
+	// UseChaining allows spending change outputs of not yet confirmed txs built by the api (requires UTXOCacheKey)
+	UseChaining bool `json:"useChaining"`
+	// RequestQuote makes GetBridgingTxFee build the tx, hold its inputs and return the quote id (requires UTXOCacheKey)
+	RequestQuote bool `json:"requestQuote"`
+	// QuoteID makes CreateBridgingTx return exactly the tx quoted by GetBridgingTxFee
+	QuoteID string `json:"quoteId"`
+	// Mode changes how the receiver amount is calculated: sendMax, netAmount or empty for the amount as it is
+	Mode string `json:"mode"`
+	// RefundAddr receives the funds instead of the sender if the request is refunded (source chain address)
+	RefundAddr string `json:"refundAddr"`
+}
+
+func (r CreateBridgingTxRequest) GetChainPair() (string, string) {
+	return r.SourceChainID, r.DestinationChainID
 }
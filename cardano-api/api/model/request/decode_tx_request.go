@@ -0,0 +1,11 @@
+package request
+
+type DecodeTxRequest struct {
+	TxRaw   string `json:"txRaw"`
+	TxHash  string `json:"txHash"`
+	ChainID string `json:"chainId"`
+}
+
+func (r DecodeTxRequest) GetChainPair() (string, string) {
+	return r.ChainID, ""
+}
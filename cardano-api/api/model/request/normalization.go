@@ -0,0 +1,127 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	goEthCommon "github.com/ethereum/go-ethereum/common"
+)
+
+var errInvalidEVMAddressChecksum = errors.New("invalid EIP-55 checksum")
+
+// FieldError points to the request field which is not valid
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid field %s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// NormalizeChainID trims and lowercases the chain id
+func NormalizeChainID(chainID string) string {
+	return strings.ToLower(strings.TrimSpace(chainID))
+}
+
+// NormalizeAddr trims the address. EVM address is lowercased, but only after the checksum
+// of the mixed case address is validated
+func NormalizeAddr(addr string) (string, error) {
+	addr = strings.TrimSpace(addr)
+
+	if len(addr) != 2+2*goEthCommon.AddressLength || !strings.HasPrefix(strings.ToLower(addr), "0x") ||
+		!goEthCommon.IsHexAddress(addr) {
+		return addr, nil
+	}
+
+	hexAddr := addr[2:]
+	isMixedCase := hexAddr != strings.ToLower(hexAddr) && hexAddr != strings.ToUpper(hexAddr)
+
+	if isMixedCase && goEthCommon.HexToAddress(hexAddr).Hex() != "0x"+hexAddr {
+		return "", errInvalidEVMAddressChecksum
+	}
+
+	return "0x" + strings.ToLower(hexAddr), nil
+}
+
+func normalizeAddrField(field string, addr *string) error {
+	normalized, err := NormalizeAddr(*addr)
+	if err != nil {
+		return &FieldError{Field: field, Err: err}
+	}
+
+	*addr = normalized
+
+	return nil
+}
+
+func normalizeTransactions(field string, transactions []CreateBridgingTxTransactionRequest) error {
+	for i := range transactions {
+		if err := normalizeAddrField(fmt.Sprintf("%s.%d.addr", field, i), &transactions[i].Addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *CreateBridgingTxRequest) Normalize() error {
+	r.SourceChainID = NormalizeChainID(r.SourceChainID)
+	r.DestinationChainID = NormalizeChainID(r.DestinationChainID)
+
+	if err := normalizeAddrField("senderAddr", &r.SenderAddr); err != nil {
+		return err
+	}
+
+	if err := normalizeAddrField("refundAddr", &r.RefundAddr); err != nil {
+		return err
+	}
+
+	return normalizeTransactions("transactions", r.Transactions)
+}
+
+func (r *CreateBridgingTxBatchRequest) Normalize() error {
+	r.SourceChainID = NormalizeChainID(r.SourceChainID)
+
+	if err := normalizeAddrField("senderAddr", &r.SenderAddr); err != nil {
+		return err
+	}
+
+	for i := range r.Requests {
+		r.Requests[i].DestinationChainID = NormalizeChainID(r.Requests[i].DestinationChainID)
+
+		if err := normalizeTransactions(
+			fmt.Sprintf("requests.%d.transactions", i), r.Requests[i].Transactions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *CreateConsolidationTxRequest) Normalize() error {
+	r.ChainID = NormalizeChainID(r.ChainID)
+
+	return normalizeAddrField("senderAddr", &r.SenderAddr)
+}
+
+func (r *CreateFanOutTxRequest) Normalize() error {
+	r.ChainID = NormalizeChainID(r.ChainID)
+
+	return normalizeAddrField("senderAddr", &r.SenderAddr)
+}
+
+func (r *DecodeTxRequest) Normalize() error {
+	r.ChainID = NormalizeChainID(r.ChainID)
+
+	return nil
+}
+
+func (r *ReleaseUtxoCacheRequest) Normalize() error {
+	return normalizeAddrField("addr", &r.Addr)
+}
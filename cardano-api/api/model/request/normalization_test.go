@@ -0,0 +1,93 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeAddr(t *testing.T) {
+	const (
+		checksumAddr = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+		lowerAddr    = "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+		cardanoAddr  = "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw"
+	)
+
+	for _, tc := range []struct {
+		addr     string
+		expected string
+		err      error
+	}{
+		{" " + cardanoAddr + "\n", cardanoAddr, nil},
+		{checksumAddr, lowerAddr, nil},
+		{lowerAddr, lowerAddr, nil},
+		{"0X5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", lowerAddr, nil},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", "", errInvalidEVMAddressChecksum},
+		{"0x1234", "0x1234", nil},
+	} {
+		addr, err := NormalizeAddr(tc.addr)
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err, tc.addr)
+		} else {
+			require.NoError(t, err, tc.addr)
+			require.Equal(t, tc.expected, addr)
+		}
+	}
+}
+
+func TestCreateBridgingTxBatchRequestNormalize(t *testing.T) {
+	r := CreateBridgingTxBatchRequest{
+		SenderAddr:    " addr1 ",
+		SourceChainID: " Prime",
+		Requests: []CreateBridgingTxBatchItemRequest{
+			{DestinationChainID: "VECTOR", Transactions: []CreateBridgingTxTransactionRequest{{Addr: "addr2 "}}},
+			{DestinationChainID: "Nexus", Transactions: []CreateBridgingTxTransactionRequest{
+				{Addr: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+				{Addr: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"},
+			}},
+		},
+	}
+
+	var fieldErr *FieldError
+
+	require.ErrorAs(t, r.Normalize(), &fieldErr)
+	require.Equal(t, "requests.1.transactions.1.addr", fieldErr.Field)
+
+	r.Requests[1].Transactions = r.Requests[1].Transactions[:1]
+
+	require.NoError(t, r.Normalize())
+	require.Equal(t, CreateBridgingTxBatchRequest{
+		SenderAddr:    "addr1",
+		SourceChainID: "prime",
+		Requests: []CreateBridgingTxBatchItemRequest{
+			{DestinationChainID: "vector", Transactions: []CreateBridgingTxTransactionRequest{{Addr: "addr2"}}},
+			{DestinationChainID: "nexus", Transactions: []CreateBridgingTxTransactionRequest{
+				{Addr: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+			}},
+		},
+	}, r)
+}
+
+func TestCreateBridgingTxRequestNormalize(t *testing.T) {
+	r := CreateBridgingTxRequest{
+		SenderAddr:         "addr1 ",
+		SourceChainID:      "Prime",
+		DestinationChainID: " NEXUS",
+		RefundAddr:         " addr2",
+		Transactions: []CreateBridgingTxTransactionRequest{
+			{Addr: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		},
+	}
+
+	require.NoError(t, r.Normalize())
+	require.Equal(t, "addr2", r.RefundAddr)
+	require.Equal(t, "nexus", r.DestinationChainID)
+	require.Equal(t, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", r.Transactions[0].Addr)
+
+	r.RefundAddr = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"
+
+	var fieldErr *FieldError
+
+	require.ErrorAs(t, r.Normalize(), &fieldErr)
+	require.Equal(t, "refundAddr", fieldErr.Field)
+}
@@ -0,0 +1,7 @@
+package request
+
+type ReleaseUtxoCacheRequest struct {
+	Addr string `json:"addr"`
+	// Inputs to release. If empty, all the reservations for the address are released
+	Inputs []UtxoRequest `json:"inputs"`
+}
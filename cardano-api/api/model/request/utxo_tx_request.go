@@ -0,0 +1,34 @@
+package request
+
+import (
+	cardanowallet "github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+)
+
+type CreateConsolidationTxRequest struct {
+	SenderAddr             string                      `json:"senderAddr"`
+	SenderAddrPolicyScript *cardanowallet.PolicyScript `json:"senderAddrPolicyScript"`
+	ChainID                string                      `json:"chainId"`
+	// MaxUtxoAmount - only utxos with amount lower or equal are consolidated (0 means all utxos)
+	MaxUtxoAmount uint64        `json:"maxUtxoAmount"`
+	MaxInputs     int           `json:"maxInputs"`
+	UTXOCacheKey  string        `json:"utxoCacheKey"`
+	SkipUtxos     []UtxoRequest `json:"skipUtxos"`
+}
+
+type CreateFanOutTxRequest struct {
+	SenderAddr             string                      `json:"senderAddr"`
+	SenderAddrPolicyScript *cardanowallet.PolicyScript `json:"senderAddrPolicyScript"`
+	ChainID                string                      `json:"chainId"`
+	OutputAmount           uint64                      `json:"outputAmount"`
+	OutputsCount           int                         `json:"outputsCount"`
+	UTXOCacheKey           string                      `json:"utxoCacheKey"`
+	SkipUtxos              []UtxoRequest               `json:"skipUtxos"`
+}
+
+func (r CreateConsolidationTxRequest) GetChainPair() (string, string) {
+	return r.ChainID, ""
+}
+
+func (r CreateFanOutTxRequest) GetChainPair() (string, string) {
+	return r.ChainID, ""
+}
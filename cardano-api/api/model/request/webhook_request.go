@@ -0,0 +1,6 @@
+package request
+
+type RegisterWebhookRequest struct {
+	// URL to which the signed events are posted
+	URL string `json:"url"`
+}
@@ -0,0 +1,56 @@
+package response
+
+import (
+	"encoding/hex"
+	"strconv"
+)
+
+type BridgingTxBatchItemResponse struct {
+	Index       int    `json:"index"`
+	Success     bool   `json:"success"`
+	TxRaw       string `json:"txRaw,omitempty"`
+	TxHash      string `json:"txHash,omitempty"`
+	BridgingFee string `json:"bridgingFee,omitempty"`
+	Err         string `json:"error,omitempty"`
+}
+
+func NewBridgingTxBatchItemResponse(
+	idx int, txRawBytes []byte, txHash string, bridgingFee uint64,
+) BridgingTxBatchItemResponse {
+	return BridgingTxBatchItemResponse{
+		Index:       idx,
+		Success:     true,
+		TxRaw:       hex.EncodeToString(txRawBytes),
+		TxHash:      txHash,
+		BridgingFee: strconv.FormatUint(bridgingFee, 10),
+	}
+}
+
+func NewBridgingTxBatchItemErrorResponse(idx int, err error) BridgingTxBatchItemResponse {
+	return BridgingTxBatchItemResponse{
+		Index: idx,
+		Err:   err.Error(),
+	}
+}
+
+type BridgingTxBatchResponse struct {
+	Items        []BridgingTxBatchItemResponse `json:"items"`
+	SuccessCount int                           `json:"successCount"`
+	FailureCount int                           `json:"failureCount"`
+}
+
+func NewBridgingTxBatchResponse(items []BridgingTxBatchItemResponse) *BridgingTxBatchResponse {
+	successCount := 0
+
+	for _, item := range items {
+		if item.Success {
+			successCount++
+		}
+	}
+
+	return &BridgingTxBatchResponse{
+		Items:        items,
+		SuccessCount: successCount,
+		FailureCount: len(items) - successCount,
+	}
+}
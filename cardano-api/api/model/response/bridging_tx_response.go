@@ -3,6 +3,9 @@ package response
 import (
 	"encoding/hex"
 	"strconv"
+	"time"
+
+	cardanotx "github.com/Ethernal-Tech/cardano-api/cardano"
 )
 
 type BridgingTxResponse struct {
@@ -30,12 +33,68 @@ func NewBridgingTxResponse(
 	}
 }
 
+type MultisigBridgingTxResponse struct {
+	BridgingTxResponse
+	RequiredKeyHashes []string `json:"requiredKeyHashes"`
+	Threshold         int      `json:"threshold"`
+}
+
+func NewMultisigBridgingTxResponse(
+	txRawBytes []byte, txHash string, bridgingFee uint64, requiredKeyHashes []string, threshold int,
+) *MultisigBridgingTxResponse {
+	return &MultisigBridgingTxResponse{
+		BridgingTxResponse: *NewFullBridgingTxResponse(txRawBytes, txHash, bridgingFee),
+		RequiredKeyHashes:  requiredKeyHashes,
+		Threshold:          threshold,
+	}
+}
+
+type BridgingTxFeeReceiverResponse struct {
+	Addr           string `json:"addr"`
+	ReceivedAmount string `json:"receivedAmount"`
+}
+
 type BridgingTxFeeResponse struct {
-	Fee string `json:"fee"`
+	// Fee is the source tx fee, kept for backward compatibility
+	Fee                 string                          `json:"fee"`
+	SourceTxFee         string                          `json:"sourceTxFee"`
+	BridgingFee         string                          `json:"bridgingFee"`
+	MinUtxoTopUp        string                          `json:"minUtxoTopUp"`
+	ChangeMinUtxoAmount string                          `json:"changeMinUtxoAmount"`
+	TotalDebit          string                          `json:"totalDebit"`
+	TxSize              uint64                          `json:"txSize"`
+	Receivers           []BridgingTxFeeReceiverResponse `json:"receivers"`
+	QuoteID             string                          `json:"quoteId,omitempty"`
+	QuoteExpiresAt      *time.Time                      `json:"quoteExpiresAt,omitempty"`
 }
 
-func NewBridgingTxFeeResponse(fee uint64) *BridgingTxFeeResponse {
+func NewBridgingTxFeeResponse(breakdown *cardanotx.BridgingTxFeeBreakdown) *BridgingTxFeeResponse {
+	receivers := make([]BridgingTxFeeReceiverResponse, len(breakdown.Receivers))
+	for i, receiver := range breakdown.Receivers {
+		receivers[i] = BridgingTxFeeReceiverResponse{
+			Addr:           receiver.Addr,
+			ReceivedAmount: strconv.FormatUint(receiver.Amount, 10),
+		}
+	}
+
 	return &BridgingTxFeeResponse{
-		Fee: strconv.FormatUint(fee, 10),
+		Fee:                 strconv.FormatUint(breakdown.SourceTxFee, 10),
+		SourceTxFee:         strconv.FormatUint(breakdown.SourceTxFee, 10),
+		BridgingFee:         strconv.FormatUint(breakdown.BridgingFee, 10),
+		MinUtxoTopUp:        strconv.FormatUint(breakdown.MinUtxoTopUp, 10),
+		ChangeMinUtxoAmount: strconv.FormatUint(breakdown.ChangeMinUtxoAmount, 10),
+		TotalDebit:          strconv.FormatUint(breakdown.TotalDebit, 10),
+		TxSize:              breakdown.TxSize,
+		Receivers:           receivers,
 	}
 }
+
+func NewQuotedBridgingTxFeeResponse(
+	breakdown *cardanotx.BridgingTxFeeBreakdown, quoteID string, quoteExpiresAt time.Time,
+) *BridgingTxFeeResponse {
+	result := NewBridgingTxFeeResponse(breakdown)
+	result.QuoteID = quoteID
+	result.QuoteExpiresAt = &quoteExpiresAt
+
+	return result
+}
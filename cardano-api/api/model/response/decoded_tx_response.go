@@ -0,0 +1,58 @@
+package response
+
+import (
+	"github.com/Ethernal-Tech/cardano-api/common"
+)
+
+type DecodedBridgingTransaction struct {
+	Addr   string `json:"addr"`
+	Amount uint64 `json:"amount"`
+}
+
+type DecodedTxResponse struct {
+	TxHash             string                       `json:"txHash"`
+	MetadataType       string                       `json:"metadataType"`
+	MetadataVersion    uint8                        `json:"metadataVersion"`
+	DestinationChainID string                       `json:"destinationChainId,omitempty"`
+	SenderAddr         string                       `json:"senderAddr,omitempty"`
+	RefundAddr         string                       `json:"refundAddr,omitempty"`
+	Transactions       []DecodedBridgingTransaction `json:"transactions,omitempty"`
+	FeeAmount          uint64                       `json:"feeAmount,omitempty"`
+	BatchNonceID       uint64                       `json:"batchNonceId,omitempty"`
+	Metadata           any                          `json:"metadata"`
+}
+
+func NewDecodedTxResponse(txHash string, metadata *common.DecodedMetadata) *DecodedTxResponse {
+	result := &DecodedTxResponse{
+		TxHash:          txHash,
+		MetadataType:    string(metadata.BridgingTxType),
+		MetadataVersion: uint8(metadata.Version),
+	}
+
+	switch {
+	case metadata.BridgingRequest != nil:
+		// bridging requests are created on the cardano chains, so only receivers can have evm addresses
+		destinationChainType := common.GetChainType(metadata.BridgingRequest.DestinationChainID)
+
+		result.DestinationChainID = metadata.BridgingRequest.DestinationChainID
+		result.SenderAddr = common.JoinAddrChunks(metadata.BridgingRequest.SenderAddr, common.ChainTypeCardano)
+		result.RefundAddr = common.JoinAddrChunks(metadata.BridgingRequest.RefundAddr, common.ChainTypeCardano)
+		result.FeeAmount = metadata.BridgingRequest.FeeAmount
+		result.Transactions = make([]DecodedBridgingTransaction, len(metadata.BridgingRequest.Transactions))
+		result.Metadata = metadata.BridgingRequest
+
+		for i, tx := range metadata.BridgingRequest.Transactions {
+			result.Transactions[i] = DecodedBridgingTransaction{
+				Addr:   common.JoinAddrChunks(tx.Address, destinationChainType),
+				Amount: tx.Amount,
+			}
+		}
+	case metadata.BatchExecuted != nil:
+		result.BatchNonceID = metadata.BatchExecuted.BatchNonceID
+		result.Metadata = metadata.BatchExecuted
+	case metadata.RefundExecuted != nil:
+		result.Metadata = metadata.RefundExecuted
+	}
+
+	return result
+}
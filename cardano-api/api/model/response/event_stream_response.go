@@ -0,0 +1,30 @@
+package response
+
+import "github.com/Ethernal-Tech/cardano-api/core"
+
+// EventStreamSnapshotResponse is the first event of the CardanoTx/Events stream
+type EventStreamSnapshotResponse struct {
+	Settings                  *SettingsResponse                 `json:"settings"`
+	MultisigAddresses         map[string]core.BridgingAddresses `json:"multisigAddresses"`
+	ValidatorChangeInProgress bool                              `json:"validatorChangeInProgress"`
+}
+
+func NewEventStreamSnapshotResponse(
+	config *core.AppConfig, validatorChangeInProgress bool,
+) *EventStreamSnapshotResponse {
+	return &EventStreamSnapshotResponse{
+		Settings:                  NewSettingsResponse(config),
+		MultisigAddresses:         config.GetBridgingAddresses(),
+		ValidatorChangeInProgress: validatorChangeInProgress,
+	}
+}
+
+type MultisigAddressesResponse struct {
+	MultisigAddresses map[string]core.BridgingAddresses `json:"multisigAddresses"`
+}
+
+func NewMultisigAddressesResponse(config *core.AppConfig) *MultisigAddressesResponse {
+	return &MultisigAddressesResponse{
+		MultisigAddresses: config.GetBridgingAddresses(),
+	}
+}
@@ -5,14 +5,19 @@ import "github.com/Ethernal-Tech/cardano-api/core"
 type SettingsResponse struct {
 	BridgingSettings core.BridgingSettings `json:"bridgingSettings"`
 	EnabledChains    []string              `json:"enabledChains"`
+	// RefundAddrSupported is true if the bridging request can have the refund address
+	RefundAddrSupported bool `json:"refundAddrSupported"`
 }
 
 func NewSettingsResponse(
 	config *core.AppConfig,
 ) *SettingsResponse {
+	settings := config.GetBridgingSettings()
+
 	return &SettingsResponse{
-		BridgingSettings: config.BridgingSettings,
-		EnabledChains:    config.CreateEnabledChains(),
+		BridgingSettings:    settings,
+		EnabledChains:       config.CreateEnabledChains(),
+		RefundAddrSupported: settings.IsRefundAddrSupported(),
 	}
 }
 
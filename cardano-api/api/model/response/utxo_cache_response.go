@@ -0,0 +1,87 @@
+package response
+
+import (
+	"time"
+
+	utxotransformer "github.com/Ethernal-Tech/cardano-api/api/utxo_transformer"
+)
+
+type UtxoReservationResponse struct {
+	Hash         string `json:"hash"`
+	Index        uint32 `json:"index"`
+	ReservedAt   string `json:"reservedAt"`
+	AgeInSeconds int64  `json:"ageInSeconds"`
+	APIKeyID     string `json:"apiKeyId"`
+	RequestID    string `json:"requestId"`
+}
+
+type AddressReservationsResponse struct {
+	Addr   string                    `json:"addr"`
+	Inputs []UtxoReservationResponse `json:"inputs"`
+}
+
+type UtxoCacheReservationsResponse struct {
+	Addresses []AddressReservationsResponse `json:"addresses"`
+}
+
+func NewUtxoCacheReservationsResponse(
+	reservations []utxotransformer.Reservation, now time.Time,
+) *UtxoCacheReservationsResponse {
+	addresses := []AddressReservationsResponse{}
+
+	// reservations are sorted by address
+	for _, x := range reservations {
+		if len(addresses) == 0 || addresses[len(addresses)-1].Addr != x.Addr {
+			addresses = append(addresses, AddressReservationsResponse{
+				Addr: x.Addr,
+			})
+		}
+
+		last := &addresses[len(addresses)-1]
+		last.Inputs = append(last.Inputs, UtxoReservationResponse{
+			Hash:         x.Input.Hash,
+			Index:        x.Input.Index,
+			ReservedAt:   x.ReservedAt.Format(time.RFC3339),
+			AgeInSeconds: int64(now.Sub(x.ReservedAt).Seconds()),
+			APIKeyID:     x.APIKeyID,
+			RequestID:    x.RequestID,
+		})
+	}
+
+	return &UtxoCacheReservationsResponse{
+		Addresses: addresses,
+	}
+}
+
+type ReleaseUtxoCacheResponse struct {
+	Released int `json:"released"`
+}
+
+type UtxoCacheStatsResponse struct {
+	AddressesCount        int    `json:"addressesCount"`
+	ReservedInputs        int    `json:"reservedInputs"`
+	PendingUtxos          int    `json:"pendingUtxos"`
+	OldestReservationAge  int64  `json:"oldestReservationAgeInSeconds"`
+	ReservationTimeoutSec int64  `json:"reservationTimeoutInSeconds"`
+	ExpiredInputs         uint64 `json:"expiredInputs"`
+	EvictedAddresses      uint64 `json:"evictedAddresses"`
+	EvictedInputs         uint64 `json:"evictedInputs"`
+}
+
+func NewUtxoCacheStatsResponse(stats utxotransformer.CacheStats, now time.Time) *UtxoCacheStatsResponse {
+	oldestAge := int64(0)
+	if !stats.OldestReservedAt.IsZero() {
+		oldestAge = int64(now.Sub(stats.OldestReservedAt).Seconds())
+	}
+
+	return &UtxoCacheStatsResponse{
+		AddressesCount:        stats.AddressesCount,
+		ReservedInputs:        stats.ReservedInputs,
+		PendingUtxos:          stats.PendingUtxos,
+		OldestReservationAge:  oldestAge,
+		ReservationTimeoutSec: int64(stats.ReservationTimeout.Seconds()),
+		ExpiredInputs:         stats.ExpiredInputs,
+		EvictedAddresses:      stats.EvictedAddresses,
+		EvictedInputs:         stats.EvictedInputs,
+	}
+}
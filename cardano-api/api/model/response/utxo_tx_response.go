@@ -0,0 +1,26 @@
+package response
+
+import (
+	"encoding/hex"
+	"strconv"
+)
+
+type UtxoTxResponse struct {
+	TxRaw        string `json:"txRaw"`
+	TxHash       string `json:"txHash"`
+	Fee          string `json:"fee"`
+	InputsCount  int    `json:"inputsCount"`
+	OutputsCount int    `json:"outputsCount"`
+}
+
+func NewUtxoTxResponse(
+	txRawBytes []byte, txHash string, fee uint64, inputsCount int, outputsCount int,
+) *UtxoTxResponse {
+	return &UtxoTxResponse{
+		TxRaw:        hex.EncodeToString(txRawBytes),
+		TxHash:       txHash,
+		Fee:          strconv.FormatUint(fee, 10),
+		InputsCount:  inputsCount,
+		OutputsCount: outputsCount,
+	}
+}
@@ -0,0 +1,25 @@
+package response
+
+type ValidationIssueResponse struct {
+	// Field is json path of the invalid field, e.g. transactions[1].addr
+	Field string `json:"field"`
+	// ReceiverIndex is index of the receiver in transactions if the issue is related to a receiver
+	ReceiverIndex *int   `json:"receiverIndex,omitempty"`
+	Message       string `json:"message"`
+}
+
+type ValidateBridgingRequestResponse struct {
+	Valid  bool                      `json:"valid"`
+	Issues []ValidationIssueResponse `json:"issues"`
+}
+
+func NewValidateBridgingRequestResponse(issues []ValidationIssueResponse) *ValidateBridgingRequestResponse {
+	if issues == nil {
+		issues = []ValidationIssueResponse{}
+	}
+
+	return &ValidateBridgingRequestResponse{
+		Valid:  len(issues) == 0,
+		Issues: issues,
+	}
+}
@@ -0,0 +1,72 @@
+package response
+
+import (
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/webhook"
+)
+
+type RegisterWebhookResponse struct {
+	URL string `json:"url"`
+	// Secret for verifying X-Webhook-Signature header. It is returned only on registration
+	Secret    string `json:"secret"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func NewRegisterWebhookResponse(subscription webhook.Subscription) *RegisterWebhookResponse {
+	return &RegisterWebhookResponse{
+		URL:       subscription.URL,
+		Secret:    subscription.Secret,
+		CreatedAt: subscription.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+type WebhookResponse struct {
+	URL           string `json:"url"`
+	CreatedAt     string `json:"createdAt"`
+	PendingEvents int    `json:"pendingEvents"`
+	DeadLetters   int    `json:"deadLetters"`
+	TrackedTxs    int    `json:"trackedTxs"`
+}
+
+func NewWebhookResponse(
+	subscription webhook.Subscription, pendingEvents int, deadLetters int, trackedTxs int,
+) *WebhookResponse {
+	return &WebhookResponse{
+		URL:           subscription.URL,
+		CreatedAt:     subscription.CreatedAt.Format(time.RFC3339),
+		PendingEvents: pendingEvents,
+		DeadLetters:   deadLetters,
+		TrackedTxs:    trackedTxs,
+	}
+}
+
+type WebhookDeadLetterResponse struct {
+	Event     webhook.Event `json:"event"`
+	Attempts  int           `json:"attempts"`
+	LastError string        `json:"lastError"`
+}
+
+type WebhookDeadLettersResponse struct {
+	DeadLetters []WebhookDeadLetterResponse `json:"deadLetters"`
+}
+
+func NewWebhookDeadLettersResponse(deliveries []webhook.Delivery) *WebhookDeadLettersResponse {
+	deadLetters := make([]WebhookDeadLetterResponse, len(deliveries))
+
+	for i, x := range deliveries {
+		deadLetters[i] = WebhookDeadLetterResponse{
+			Event:     x.Event,
+			Attempts:  x.Attempts,
+			LastError: x.LastError,
+		}
+	}
+
+	return &WebhookDeadLettersResponse{
+		DeadLetters: deadLetters,
+	}
+}
+
+type RedeliverWebhookDeadLettersResponse struct {
+	Redelivered int `json:"redelivered"`
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/hashicorp/go-hclog"
+)
+
+const certReloadCheckInterval = 10 * time.Second
+
+// newTLSConfig creates server tls config for the api. Certificate is reloaded when the cert or key file changes
+func newTLSConfig(config core.TLSConfig, logger hclog.Logger) (*tls.Config, error) {
+	if config.CertFile == "" || config.KeyFile == "" {
+		return nil, errors.New("both tls cert and key files should be specified")
+	}
+
+	minVersion, err := config.GetMinVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	reloader, err := newCertReloader(config.CertFile, config.KeyFile, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     minVersion,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if config.ClientCAFile != "" {
+		caBundle, err := os.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls client CA file: %w", err)
+		}
+
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no certificates found in tls client CA file: %s", config.ClientCAFile)
+		}
+
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if config.RequireClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	} else if config.RequireClientCert {
+		return nil, errors.New("tls client CA file should be specified if client certificate is required")
+	}
+
+	return tlsConfig, nil
+}
+
+// getClientCertID returns identifier of the verified client certificate or empty string if there is none
+func getClientCertID(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	cert := r.TLS.VerifiedChains[0][0]
+	if cert.Subject.CommonName != "" {
+		return "cert:" + cert.Subject.CommonName
+	}
+
+	return "cert:" + cert.SerialNumber.String()
+}
+
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   hclog.Logger
+	now      func() time.Time
+
+	lock          sync.Mutex
+	cert          *tls.Certificate
+	certModTime   time.Time
+	keyModTime    time.Time
+	lastCheckTime time.Time
+}
+
+func newCertReloader(certFile, keyFile string, logger hclog.Logger) (*certReloader, error) {
+	cr := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		logger:   logger,
+		now:      time.Now,
+	}
+
+	if err := cr.reload(); err != nil {
+		return nil, err
+	}
+
+	return cr, nil
+}
+
+func (cr *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cr.lock.Lock()
+	defer cr.lock.Unlock()
+
+	if now := cr.now(); now.Sub(cr.lastCheckTime) >= certReloadCheckInterval {
+		cr.lastCheckTime = now
+
+		// keep the old certificate if the new one can not be loaded (for example files are not fully written yet)
+		if err := cr.reload(); err != nil {
+			cr.logger.Error("failed to reload tls certificate", "err", err)
+		}
+	}
+
+	return cr.cert, nil
+}
+
+// reload loads the certificate if the cert or key file has been modified since the last load
+func (cr *certReloader) reload() error {
+	certInfo, err := os.Stat(cr.certFile)
+	if err != nil {
+		return fmt.Errorf("failed to stat tls cert file: %w", err)
+	}
+
+	keyInfo, err := os.Stat(cr.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to stat tls key file: %w", err)
+	}
+
+	if cr.cert != nil && certInfo.ModTime().Equal(cr.certModTime) && keyInfo.ModTime().Equal(cr.keyModTime) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load tls certificate: %w", err)
+	}
+
+	if cr.cert != nil {
+		cr.logger.Info("tls certificate reloaded", "certFile", cr.certFile)
+	}
+
+	cr.cert = &cert
+	cr.certModTime = certInfo.ModTime()
+	cr.keyModTime = keyInfo.ModTime()
+
+	return nil
+}
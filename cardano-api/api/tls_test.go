@@ -0,0 +1,262 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, commonName string, serial int64, isCA bool, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	}
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) writeFiles(t *testing.T, certFile, keyFile string) {
+	t.Helper()
+
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key, Leaf: c.cert}
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	caFile := filepath.Join(dir, "ca.pem")
+
+	ca := newTestCert(t, "ca", 1, true, nil)
+	serverCert := newTestCert(t, "server", 2, false, ca)
+	clientCert := newTestCert(t, "web-api", 3, false, ca)
+	otherCA := newTestCert(t, "other ca", 4, true, nil)
+	otherClientCert := newTestCert(t, "other", 5, false, otherCA)
+
+	serverCert.writeFiles(t, certFile, keyFile)
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.der}), 0600))
+
+	logger := hclog.NewNullLogger()
+
+	startServer := func(t *testing.T, tlsConfig core.TLSConfig) string {
+		t.Helper()
+
+		serverTLSConfig, err := newTLSConfig(tlsConfig, logger)
+		require.NoError(t, err)
+
+		listener, err := tls.Listen("tcp", "127.0.0.1:0", serverTLSConfig)
+		require.NoError(t, err)
+
+		handler := withAPIKeyAuth("x-api-key", []string{"key1"}, true, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(common.APIKeyIDFromContext(r.Context())))
+		}, logger)
+		server := &http.Server{
+			Handler:           http.HandlerFunc(handler),
+			ReadHeaderTimeout: time.Second,
+			ErrorLog:          log.New(io.Discard, "", 0),
+		}
+
+		go func() { _ = server.Serve(listener) }()
+
+		t.Cleanup(func() { _ = server.Close() })
+
+		return "https://" + listener.Addr().String()
+	}
+
+	call := func(url string, apiKey string, certs ...tls.Certificate) (int, string, error) {
+		rootCAs := x509.NewCertPool()
+		rootCAs.AddCert(ca.cert)
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			RootCAs:      rootCAs,
+			ServerName:   "localhost",
+			Certificates: certs,
+		}}}
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return 0, "", err
+		}
+
+		if apiKey != "" {
+			req.Header.Set("x-api-key", apiKey)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, "", err
+		}
+
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+
+		return resp.StatusCode, string(body), err
+	}
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := newTLSConfig(core.TLSConfig{CertFile: certFile}, logger)
+		require.ErrorContains(t, err, "both tls cert and key files")
+
+		_, err = newTLSConfig(core.TLSConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.1"}, logger)
+		require.ErrorContains(t, err, "unsupported tls version")
+
+		_, err = newTLSConfig(core.TLSConfig{CertFile: certFile, KeyFile: keyFile, RequireClientCert: true}, logger)
+		require.ErrorContains(t, err, "client CA file should be specified")
+
+		_, err = newTLSConfig(core.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile}, logger)
+		require.ErrorContains(t, err, "no certificates found")
+	})
+
+	t.Run("https with api key", func(t *testing.T) {
+		url := startServer(t, core.TLSConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"})
+
+		status, body, err := call(url, "key1")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, common.GetAPIKeyID("key1"), body)
+
+		// client certificate is ignored without client CA
+		status, _, err = call(url, "", clientCert.tlsCertificate())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("optional client certificate", func(t *testing.T) {
+		url := startServer(t, core.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+
+		status, body, err := call(url, "", clientCert.tlsCertificate())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "cert:web-api", body)
+
+		status, _, err = call(url, "")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		// api key is checked if sent together with the certificate
+		status, _, err = call(url, "wrong", clientCert.tlsCertificate())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, status)
+
+		// certificate of unknown CA is not sent by the client
+		status, _, err = call(url, "", otherClientCert.tlsCertificate())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("required client certificate", func(t *testing.T) {
+		url := startServer(t, core.TLSConfig{
+			CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, RequireClientCert: true,
+		})
+
+		status, _, err := call(url, "", clientCert.tlsCertificate())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, status)
+
+		_, _, err = call(url, "key1")
+		require.Error(t, err)
+	})
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	newTestCert(t, "first", 1, false, nil).writeFiles(t, certFile, keyFile)
+
+	reloader, err := newCertReloader(certFile, keyFile, hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	now := time.Now()
+	reloader.now = func() time.Time { return now }
+
+	getCommonName := func() string {
+		cert, err := reloader.GetCertificate(nil)
+		require.NoError(t, err)
+
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+
+		return leaf.Subject.CommonName
+	}
+
+	require.Equal(t, "first", getCommonName())
+
+	newTestCert(t, "second", 2, false, nil).writeFiles(t, certFile, keyFile)
+
+	// make sure mod time is changed even on file systems with coarse timestamps
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+
+	// not checked before the interval passes
+	require.Equal(t, "first", getCommonName())
+
+	now = now.Add(certReloadCheckInterval)
+	require.Equal(t, "second", getCommonName())
+
+	// invalid files keep the old certificate
+	require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0600))
+	require.NoError(t, os.Chtimes(keyFile, modTime.Add(time.Minute), modTime.Add(time.Minute)))
+
+	now = now.Add(certReloadCheckInterval)
+	require.Equal(t, "second", getCommonName())
+}
@@ -1,10 +1,14 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/Ethernal-Tech/cardano-api/api/model/request"
 	"github.com/Ethernal-Tech/cardano-api/api/model/response"
@@ -15,17 +19,37 @@ import (
 	"github.com/hashicorp/go-hclog"
 )
 
+// unknownFieldErrPrefix is the prefix of the error returned by json decoder for unknown fields
+const unknownFieldErrPrefix = "json: unknown field "
+
+var errRequestBodyTrailingData = errors.New("request body should contain a single json object")
+
+// chainPairRequest is implemented by request models which contain chain ids, so they can be logged in the access log
+type chainPairRequest interface {
+	GetChainPair() (string, string)
+}
+
+// GetRequestLogger returns logger which adds request id of the request being handled to every line
+func GetRequestLogger(ctx context.Context, logger hclog.Logger) hclog.Logger {
+	if requestID := common.RequestIDFromContext(ctx); requestID != "" {
+		return logger.With("requestID", requestID)
+	}
+
+	return logger
+}
+
 func WriteResponse(w http.ResponseWriter, r *http.Request, status int, response any, logger hclog.Logger) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		logger.Error("write response error", "url", r.URL, "status", status, "err", err)
+		GetRequestLogger(r.Context(), logger).Error(
+			"write response error", "url", r.URL, "status", status, "err", err)
 	}
 }
 
 func WriteErrorResponse(w http.ResponseWriter, r *http.Request, status int, err error, logger hclog.Logger) {
-	logger.Error("error happened", "url", r.URL, "status", status, "err", err)
+	GetRequestLogger(r.Context(), logger).Error("error happened", "url", r.URL, "status", status, "err", err)
 
 	WriteResponse(w, r, status, response.ErrorResponse{Err: err.Error()}, logger)
 }
@@ -34,28 +58,143 @@ func WriteUnauthorizedResponse(w http.ResponseWriter, r *http.Request, logger hc
 	WriteErrorResponse(w, r, http.StatusUnauthorized, errors.New("Unauthorized"), logger)
 }
 
+// normalizableRequest is implemented by request models which trim and case-normalize addresses and chain ids
+type normalizableRequest interface {
+	Normalize() error
+}
+
+// DecodeModel decodes json request body into the model. Unknown fields and trailing data are rejected
+// and the model is normalized if it supports normalization
 func DecodeModel[T any](w http.ResponseWriter, r *http.Request, logger hclog.Logger) (T, bool) {
 	var requestBody T
 
-	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
-		WriteErrorResponse(w, r, http.StatusBadRequest, fmt.Errorf("bad request: %w", err), logger)
+	if err := checkContentType(r); err != nil {
+		WriteErrorResponse(w, r, http.StatusUnsupportedMediaType, err, logger)
+
+		return requestBody, false
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(&requestBody)
+	if err == nil {
+		if _, tokenErr := decoder.Token(); !errors.Is(tokenErr, io.EOF) {
+			err = errors.Join(errRequestBodyTrailingData, tokenErr)
+		}
+	}
+
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			WriteErrorResponse(w, r, http.StatusRequestEntityTooLarge,
+				fmt.Errorf("request body is larger than %d bytes", maxBytesErr.Limit), logger)
+		} else {
+			WriteErrorResponse(w, r, http.StatusBadRequest, fmt.Errorf("bad request: %w", describeDecodeError(err)), logger)
+		}
 
 		return requestBody, false
 	}
 
+	if normalizable, ok := any(&requestBody).(normalizableRequest); ok {
+		if err := normalizable.Normalize(); err != nil {
+			WriteErrorResponse(w, r, http.StatusBadRequest, fmt.Errorf("bad request: %w", err), logger)
+
+			return requestBody, false
+		}
+	}
+
+	if chainPair, ok := any(requestBody).(chainPairRequest); ok {
+		if info := common.RequestInfoFromContext(r.Context()); info != nil {
+			info.SourceChainID, info.DestinationChainID = chainPair.GetChainPair()
+		}
+	}
+
 	return requestBody, true
 }
 
+// checkContentType accepts json content type. Missing content type is accepted for the clients which do not set it
+func checkContentType(r *http.Request) error {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "application/json" {
+		return fmt.Errorf("unsupported content type %s, expected application/json", contentType)
+	}
+
+	return nil
+}
+
+// describeDecodeError replaces json decoder errors with the ones which point to the offending field
+func describeDecodeError(err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.Is(err, errRequestBodyTrailingData):
+		return errRequestBodyTrailingData
+	case errors.Is(err, io.EOF):
+		return errors.New("request body is empty")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.New("request body is not complete json")
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("malformed json at offset %d: %w", syntaxErr.Offset, err)
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return fmt.Errorf("request body should be %s but got %s", typeErr.Type, typeErr.Value)
+		}
+
+		return &request.FieldError{
+			Field: typeErr.Field,
+			Err:   fmt.Errorf("expected %s but got %s", typeErr.Type, typeErr.Value),
+		}
+	case strings.HasPrefix(err.Error(), unknownFieldErrPrefix):
+		return fmt.Errorf("unknown field %s", strings.TrimPrefix(err.Error(), unknownFieldErrPrefix))
+	default:
+		return err
+	}
+}
+
 func GetUtxosTransformer(
+	ctx context.Context,
 	requestBody request.CreateBridgingTxRequest,
 	appConfig *core.AppConfig,
 	usedUtxoCacher *utxotransformer.UsedUtxoCacher,
-) utxotransformer.IUtxosTransformer {
-	if useUtxoCache(requestBody, appConfig) {
-		return &utxotransformer.CacheUtxosTransformer{
-			UtxoCacher: usedUtxoCacher,
-			Addr:       requestBody.SenderAddr,
+	reservationStore utxotransformer.IReservationStore,
+) (utxotransformer.IUtxosTransformer, error) {
+	if UseUtxoCache(requestBody, appConfig) {
+		if reservationStore != nil {
+			transformer := &utxotransformer.SharedUtxosTransformer{
+				Store:           reservationStore,
+				UtxoCacher:      usedUtxoCacher,
+				Addr:            requestBody.SenderAddr,
+				ReservationInfo: GetReservationInfo(ctx),
+			}
+
+			if err := transformer.Load(ctx); err != nil {
+				return nil, fmt.Errorf("failed to retrieve reserved utxos: %w", err)
+			}
+
+			return transformer, nil
+		}
+
+		if requestBody.UseChaining {
+			return &utxotransformer.ChainedUtxosTransformer{
+				UtxoCacher:      usedUtxoCacher,
+				Addr:            requestBody.SenderAddr,
+				ReservationInfo: GetReservationInfo(ctx),
+			}, nil
 		}
+
+		return &utxotransformer.CacheUtxosTransformer{
+			UtxoCacher:      usedUtxoCacher,
+			Addr:            requestBody.SenderAddr,
+			ReservationInfo: GetReservationInfo(ctx),
+		}, nil
 	}
 
 	if len(requestBody.SkipUtxos) > 0 {
@@ -66,16 +205,22 @@ func GetUtxosTransformer(
 					Index: a.Index,
 				}
 			}),
-		}
+		}, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
-func useUtxoCache(
+// UseUtxoCache returns true if the request is allowed to use the used utxo cache
+func UseUtxoCache(
 	requestBody request.CreateBridgingTxRequest, appConfig *core.AppConfig,
 ) (useCaching bool) {
-	if desiredKey := requestBody.UTXOCacheKey; desiredKey != "" {
+	return IsUtxoCacheKeyAllowed(requestBody.UTXOCacheKey, appConfig)
+}
+
+// IsUtxoCacheKeyAllowed returns true if the key is one of the configured utxo cache keys
+func IsUtxoCacheKeyAllowed(desiredKey string, appConfig *core.AppConfig) bool {
+	if desiredKey != "" {
 		for _, key := range appConfig.APIConfig.UTXOCacheKeys {
 			if key == desiredKey {
 				return true
@@ -85,3 +230,11 @@ func useUtxoCache(
 
 	return false
 }
+
+// GetReservationInfo returns api key and request id from the request context which are recorded with reservations
+func GetReservationInfo(ctx context.Context) utxotransformer.ReservationInfo {
+	return utxotransformer.ReservationInfo{
+		APIKeyID:  common.APIKeyIDFromContext(ctx),
+		RequestID: common.RequestIDFromContext(ctx),
+	}
+}
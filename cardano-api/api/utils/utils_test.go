@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeModel(t *testing.T) {
+	decode := func(body string, contentType string, maxBodySize int64) (
+		*httptest.ResponseRecorder, request.CreateBridgingTxRequest, bool,
+	) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+		if contentType != "" {
+			r.Header.Set("Content-Type", contentType)
+		}
+
+		if maxBodySize > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		}
+
+		model, ok := DecodeModel[request.CreateBridgingTxRequest](w, r, hclog.NewNullLogger())
+
+		return w, model, ok
+	}
+
+	getErr := func(w *httptest.ResponseRecorder) string {
+		var errResponse response.ErrorResponse
+
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResponse))
+
+		return errResponse.Err
+	}
+
+	t.Run("valid request is normalized", func(t *testing.T) {
+		_, model, ok := decode(
+			`{"sourceChainId":" Prime ","destinationChainId":"NEXUS","transactions":[`+
+				`{"addr":"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed","amount":1}]}`+"\n",
+			"application/json; charset=utf-8", 0)
+
+		require.True(t, ok)
+		require.Equal(t, "prime", model.SourceChainID)
+		require.Equal(t, "nexus", model.DestinationChainID)
+		require.Equal(t, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", model.Transactions[0].Addr)
+	})
+
+	for _, tc := range []struct {
+		name        string
+		body        string
+		contentType string
+		maxBodySize int64
+		status      int
+		err         string
+	}{
+		{"unsupported content type", `{}`, "text/plain", 0, http.StatusUnsupportedMediaType, "text/plain"},
+		{"too large", `{"senderAddr":"` + strings.Repeat("a", 100) + `"}`, "", 50,
+			http.StatusRequestEntityTooLarge, "larger than 50 bytes"},
+		{"unknown field", `{"sourceChain":"prime"}`, "", 0, http.StatusBadRequest, `unknown field "sourceChain"`},
+		{"wrong type", `{"transactions":[{"addr":"a","amount":-1}]}`, "", 0, http.StatusBadRequest,
+			"invalid field transactions.0.amount: expected uint64 but got number -1"},
+		{"trailing data", `{}{}`, "", 0, http.StatusBadRequest, "single json object"},
+		{"empty", ``, "", 0, http.StatusBadRequest, "request body is empty"},
+		{"malformed", `{"senderAddr":}`, "", 0, http.StatusBadRequest, "malformed json at offset"},
+		{"invalid checksum", `{"transactions":[{"addr":"a"},{"addr":"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"}]}`,
+			"", 0, http.StatusBadRequest, "invalid field transactions.1.addr: invalid EIP-55 checksum"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _, ok := decode(tc.body, tc.contentType, tc.maxBodySize)
+
+			require.False(t, ok)
+			require.Equal(t, tc.status, w.Code)
+			require.Contains(t, getErr(w), tc.err)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package utxotransformer
+
+import "github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+
+// ChainedUtxosTransformer behaves as CacheUtxosTransformer but also offers change outputs
+// of the txs which are built by this service and not yet confirmed, so txs can be chained
+type ChainedUtxosTransformer struct {
+	UtxoCacher      *UsedUtxoCacher
+	Addr            string
+	ReservationInfo ReservationInfo
+}
+
+var _ IUtxosTransformer = (*ChainedUtxosTransformer)(nil)
+
+func (u *ChainedUtxosTransformer) TransformUtxos(utxos []wallet.Utxo) []wallet.Utxo {
+	existing := make(map[string]struct{}, len(utxos))
+	for _, utxo := range utxos {
+		existing[wallet.TxInput{Hash: utxo.Hash, Index: utxo.Index}.String()] = struct{}{}
+	}
+
+	// pending output is already on chain if the provider returned it
+	for _, utxo := range u.UtxoCacher.GetPending(u.Addr) {
+		if _, exists := existing[wallet.TxInput{Hash: utxo.Hash, Index: utxo.Index}.String()]; !exists {
+			utxos = append(utxos, utxo)
+		}
+	}
+
+	return filterOutUtxos(utxos, u.UtxoCacher.Get(u.Addr))
+}
+
+func (u *ChainedUtxosTransformer) UpdateUtxos(usedInputs []wallet.TxInput) {
+	u.UtxoCacher.AddWithInfo(u.Addr, usedInputs, u.ReservationInfo)
+}
+
+// AddChangeUtxos makes change outputs of the newly built tx available for the next txs
+func (u *ChainedUtxosTransformer) AddChangeUtxos(changeUtxos []wallet.Utxo) {
+	u.UtxoCacher.AddPending(u.Addr, changeUtxos)
+}
@@ -0,0 +1,42 @@
+package utxotransformer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainedUtxosTransformer(t *testing.T) {
+	addr := "addr1"
+	confirmed := []wallet.Utxo{
+		{Hash: "0x01", Index: 0, Amount: 100},
+		{Hash: "0x02", Index: 1, Amount: 200},
+	}
+	transformer := &ChainedUtxosTransformer{
+		UtxoCacher: NewUsedUtxoCacher(time.Millisecond * 100),
+		Addr:       addr,
+	}
+
+	// first tx spends everything
+	transformer.UpdateUtxos([]wallet.TxInput{{Hash: "0x01", Index: 0}, {Hash: "0x02", Index: 1}})
+	require.Empty(t, transformer.TransformUtxos(confirmed))
+
+	// its change is offered to the next tx
+	change := wallet.Utxo{Hash: "0x03", Index: 1, Amount: 250}
+	transformer.AddChangeUtxos([]wallet.Utxo{change})
+	require.Equal(t, []wallet.Utxo{change}, transformer.TransformUtxos(confirmed))
+
+	// change is not duplicated once the tx is confirmed
+	require.Equal(t, []wallet.Utxo{change}, transformer.TransformUtxos([]wallet.Utxo{change}))
+
+	// second tx spends the change
+	transformer.UpdateUtxos([]wallet.TxInput{{Hash: "0x03", Index: 1}})
+	require.Empty(t, transformer.TransformUtxos(confirmed))
+
+	// everything expires
+	time.Sleep(time.Millisecond * 120)
+	require.Equal(t, confirmed, transformer.TransformUtxos(confirmed))
+	require.Empty(t, transformer.UtxoCacher.GetPending(addr))
+}
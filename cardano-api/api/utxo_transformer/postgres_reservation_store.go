@@ -0,0 +1,201 @@
+package utxotransformer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers pgx database/sql driver
+)
+
+const defaultPostgresTableName = "utxo_reservations"
+
+var validTableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// PostgresReservationStore keeps reservations in postgres table.
+// Primary key (addr, tx_hash, tx_index) makes reservation atomic between concurrent transactions
+type PostgresReservationStore struct {
+	db      *sql.DB
+	table   string
+	timeout time.Duration
+}
+
+var _ IReservationStore = (*PostgresReservationStore)(nil)
+
+func NewPostgresReservationStore(db *sql.DB, table string, timeout time.Duration) *PostgresReservationStore {
+	if table == "" {
+		table = defaultPostgresTableName
+	}
+
+	return &PostgresReservationStore{
+		db:      db,
+		table:   table,
+		timeout: timeout,
+	}
+}
+
+// CreateTable creates reservations table if it does not exist
+func (s *PostgresReservationStore) CreateTable(ctx context.Context) error {
+	if !validTableName.MatchString(s.table) {
+		return fmt.Errorf("invalid table name: %s", s.table)
+	}
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		addr TEXT NOT NULL,
+		tx_hash TEXT NOT NULL,
+		tx_index INTEGER NOT NULL,
+		reserved_at TIMESTAMPTZ NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL,
+		api_key_id TEXT NOT NULL,
+		request_id TEXT NOT NULL,
+		PRIMARY KEY (addr, tx_hash, tx_index)
+	)`, s.table))
+	if err != nil {
+		return fmt.Errorf("failed to create reservations table: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PostgresReservationStore) Reserve(
+	ctx context.Context, addr string, inputs []wallet.TxInput, info ReservationInfo,
+) (result bool, err error) {
+	if len(inputs) == 0 {
+		return true, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if !result || err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE addr = $1 AND expires_at <= NOW()", s.table), addr)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove expired reservations: %w", err)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (addr, tx_hash, tx_index, reserved_at, expires_at, api_key_id, request_id)
+		VALUES ($1, $2, $3, NOW(), NOW() + $4 * INTERVAL '1 millisecond', $5, $6)
+		ON CONFLICT (addr, tx_hash, tx_index) DO NOTHING`, s.table)
+
+	for _, input := range inputs {
+		res, err := tx.ExecContext(ctx, query,
+			addr, input.Hash, int64(input.Index), s.timeout.Milliseconds(), info.APIKeyID, info.RequestID)
+		if err != nil {
+			return false, fmt.Errorf("failed to reserve input: %w", err)
+		}
+
+		if affected, err := res.RowsAffected(); err != nil {
+			return false, err
+		} else if affected == 0 {
+			// already reserved by someone else
+			return false, nil
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit reservations: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *PostgresReservationStore) Get(ctx context.Context, addr string) ([]wallet.TxInput, error) {
+	rows, err := s.db.QueryContext(ctx,
+		fmt.Sprintf("SELECT tx_hash, tx_index FROM %s WHERE addr = $1 AND expires_at > NOW()", s.table), addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve reserved inputs: %w", err)
+	}
+
+	defer rows.Close()
+
+	result := []wallet.TxInput{}
+
+	for rows.Next() {
+		var (
+			hash  string
+			index int64
+		)
+
+		if err := rows.Scan(&hash, &index); err != nil {
+			return nil, err
+		}
+
+		result = append(result, wallet.TxInput{
+			Hash:  hash,
+			Index: uint32(index), //nolint:gosec
+		})
+	}
+
+	return result, rows.Err()
+}
+
+func (s *PostgresReservationStore) GetReservations(ctx context.Context, addr string) ([]Reservation, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT addr, tx_hash, tx_index, reserved_at, api_key_id, request_id
+		FROM %s WHERE ($1 = '' OR addr = $1) AND expires_at > NOW()
+		ORDER BY addr, reserved_at, tx_hash, tx_index`, s.table), addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve reservations: %w", err)
+	}
+
+	defer rows.Close()
+
+	result := []Reservation{}
+
+	for rows.Next() {
+		var (
+			reservation Reservation
+			index       int64
+		)
+
+		if err := rows.Scan(&reservation.Addr, &reservation.Input.Hash, &index, &reservation.ReservedAt,
+			&reservation.APIKeyID, &reservation.RequestID); err != nil {
+			return nil, err
+		}
+
+		reservation.Input.Index = uint32(index) //nolint:gosec
+		reservation.ReservedAt = reservation.ReservedAt.UTC()
+
+		result = append(result, reservation)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *PostgresReservationStore) Release(
+	ctx context.Context, addr string, inputs []wallet.TxInput,
+) (int, error) {
+	released := 0
+	query := fmt.Sprintf("DELETE FROM %s WHERE addr = $1 AND tx_hash = $2 AND tx_index = $3", s.table)
+
+	for _, input := range inputs {
+		res, err := s.db.ExecContext(ctx, query, addr, input.Hash, int64(input.Index))
+		if err != nil {
+			return released, fmt.Errorf("failed to release input: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return released, err
+		}
+
+		released += int(affected)
+	}
+
+	return released, nil
+}
+
+func (s *PostgresReservationStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,248 @@
+package utxotransformer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultRedisKeyPrefix = "cardano-api:utxo-reservations"
+
+// reserveScript reserves all the inputs (ARGV[4:]) for the address if none of them is reserved.
+// Reservations are kept in sorted set KEYS[1] (member is input, score is expiration time in ms)
+// and reservation infos in hash KEYS[2]. Expired reservations are removed from both
+var reserveScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local expiresAt = tonumber(ARGV[2])
+local info = ARGV[3]
+
+local expired = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', now)
+for _, input in ipairs(expired) do
+	redis.call('HDEL', KEYS[2], input)
+end
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now)
+
+for i = 4, #ARGV do
+	if redis.call('ZSCORE', KEYS[1], ARGV[i]) then
+		return 0
+	end
+end
+
+for i = 4, #ARGV do
+	redis.call('ZADD', KEYS[1], expiresAt, ARGV[i])
+	redis.call('HSET', KEYS[2], ARGV[i], info)
+end
+
+redis.call('PEXPIREAT', KEYS[1], expiresAt)
+redis.call('PEXPIREAT', KEYS[2], expiresAt)
+
+return 1
+`)
+
+// RedisReservationStore keeps reservations in redis (or any redis protocol compatible server)
+type RedisReservationStore struct {
+	client  *redis.Client
+	prefix  string
+	timeout time.Duration
+}
+
+var _ IReservationStore = (*RedisReservationStore)(nil)
+
+func NewRedisReservationStore(client *redis.Client, prefix string, timeout time.Duration) *RedisReservationStore {
+	if prefix == "" {
+		prefix = defaultRedisKeyPrefix
+	}
+
+	return &RedisReservationStore{
+		client:  client,
+		prefix:  prefix,
+		timeout: timeout,
+	}
+}
+
+func (s *RedisReservationStore) Reserve(
+	ctx context.Context, addr string, inputs []wallet.TxInput, info ReservationInfo,
+) (bool, error) {
+	if len(inputs) == 0 {
+		return true, nil
+	}
+
+	infoBytes, err := json.Marshal(info)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now().UTC()
+	args := make([]any, 0, len(inputs)+3)
+	args = append(args, now.UnixMilli(), now.Add(s.timeout).UnixMilli(), string(infoBytes))
+
+	for _, input := range inputs {
+		args = append(args, input.String())
+	}
+
+	result, err := reserveScript.Run(ctx, s.client, s.getKeys(addr), args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve inputs: %w", err)
+	}
+
+	return result == 1, nil
+}
+
+func (s *RedisReservationStore) Get(ctx context.Context, addr string) ([]wallet.TxInput, error) {
+	members, err := s.client.ZRangeByScore(ctx, s.getKeys(addr)[0], &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(time.Now().UTC().UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve reserved inputs: %w", err)
+	}
+
+	result := make([]wallet.TxInput, 0, len(members))
+
+	for _, member := range members {
+		input, err := parseTxInput(member)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, input)
+	}
+
+	return result, nil
+}
+
+// GetReservations returns reservations with their infos. Reservation time is derived from the expiration time.
+// All the addresses are found by scanning the keys with the store prefix
+func (s *RedisReservationStore) GetReservations(ctx context.Context, addr string) ([]Reservation, error) {
+	addrs := []string{addr}
+
+	if addr == "" {
+		var err error
+
+		addrs, err = s.getAddresses(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := strconv.FormatInt(time.Now().UTC().UnixMilli(), 10)
+	result := []Reservation{}
+
+	for _, currAddr := range addrs {
+		keys := s.getKeys(currAddr)
+
+		members, err := s.client.ZRangeByScoreWithScores(ctx, keys[0], &redis.ZRangeBy{
+			Min: "(" + now,
+			Max: "+inf",
+		}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve reserved inputs: %w", err)
+		}
+
+		if len(members) == 0 {
+			continue
+		}
+
+		fields := make([]string, len(members))
+
+		for i, member := range members {
+			fields[i], _ = member.Member.(string)
+		}
+
+		infos, err := s.client.HMGet(ctx, keys[1], fields...).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve reservation infos: %w", err)
+		}
+
+		for i, member := range members {
+			input, err := parseTxInput(fields[i])
+			if err != nil {
+				return nil, err
+			}
+
+			var info ReservationInfo
+
+			if infoStr, ok := infos[i].(string); ok {
+				if err := json.Unmarshal([]byte(infoStr), &info); err != nil {
+					return nil, fmt.Errorf("invalid reservation info of %s: %w", fields[i], err)
+				}
+			}
+
+			result = append(result, Reservation{
+				Addr:            currAddr,
+				Input:           input,
+				ReservedAt:      time.UnixMilli(int64(member.Score)).UTC().Add(-s.timeout),
+				ReservationInfo: info,
+			})
+		}
+	}
+
+	sortReservations(result)
+
+	return result, nil
+}
+
+func (s *RedisReservationStore) Release(ctx context.Context, addr string, inputs []wallet.TxInput) (int, error) {
+	if len(inputs) == 0 {
+		return 0, nil
+	}
+
+	keys := s.getKeys(addr)
+	members := make([]any, len(inputs))
+	fields := make([]string, len(inputs))
+
+	for i, input := range inputs {
+		members[i] = input.String()
+		fields[i] = input.String()
+	}
+
+	var removed *redis.IntCmd
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		removed = pipe.ZRem(ctx, keys[0], members...)
+		pipe.HDel(ctx, keys[1], fields...)
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to release inputs: %w", err)
+	}
+
+	return int(removed.Val()), nil
+}
+
+func (s *RedisReservationStore) Close() error {
+	return s.client.Close()
+}
+
+// getAddresses returns addresses which have the inputs key
+func (s *RedisReservationStore) getAddresses(ctx context.Context) ([]string, error) {
+	keyPrefix, keySuffix := s.prefix+":{", "}:inputs"
+	addrs := []string{}
+
+	iter := s.client.Scan(ctx, 0, keyPrefix+"*"+keySuffix, 0).Iterator()
+	for iter.Next(ctx) {
+		addrs = append(addrs, strings.TrimSuffix(strings.TrimPrefix(iter.Val(), keyPrefix), keySuffix))
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve reserved addresses: %w", err)
+	}
+
+	return addrs, nil
+}
+
+func (s *RedisReservationStore) getKeys(addr string) []string {
+	// hash tag keeps both keys of the address in the same slot for redis cluster
+	return []string{
+		fmt.Sprintf("%s:{%s}:inputs", s.prefix, addr),
+		fmt.Sprintf("%s:{%s}:infos", s.prefix, addr),
+	}
+}
@@ -0,0 +1,139 @@
+package utxotransformer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/redis/go-redis/v9"
+)
+
+// IReservationStore keeps utxo reservations which are shared between api replicas
+type IReservationStore interface {
+	// Reserve atomically reserves all the inputs for the address if none of them is already reserved.
+	// Returns false if at least one of the inputs is reserved
+	Reserve(ctx context.Context, addr string, inputs []wallet.TxInput, info ReservationInfo) (bool, error)
+	// Get returns all not expired reserved inputs for the address
+	Get(ctx context.Context, addr string) ([]wallet.TxInput, error)
+	// GetReservations returns not expired reservations for the address (or for all addresses if addr is empty)
+	// sorted by address and reservation time
+	GetReservations(ctx context.Context, addr string) ([]Reservation, error)
+	// Release removes reservations of the inputs and returns number of released inputs
+	Release(ctx context.Context, addr string, inputs []wallet.TxInput) (int, error)
+	Close() error
+}
+
+// NewReservationStore creates shared reservation store from the config.
+// Returns nil store if shared store is not configured
+func NewReservationStore(
+	ctx context.Context, config core.UtxoReservationStoreConfig, timeout time.Duration,
+) (IReservationStore, error) {
+	switch config.Type {
+	case "":
+		return nil, nil
+	case core.ReservationStoreTypeRedis:
+		options, err := redis.ParseURL(config.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis url: %w", err)
+		}
+
+		return NewRedisReservationStore(redis.NewClient(options), config.Prefix, timeout), nil
+	case core.ReservationStoreTypePostgres:
+		db, err := sql.Open("pgx", config.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+		}
+
+		store := NewPostgresReservationStore(db, config.Prefix, timeout)
+
+		if err := store.CreateTable(ctx); err != nil {
+			_ = db.Close()
+
+			return nil, err
+		}
+
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown reservation store type: %s", config.Type)
+	}
+}
+
+// ReleaseReservations releases the inputs reserved for the address in the local utxo cache and, if the store is not
+// nil, in the shared reservation store. All the reservations of the address are released if no input is specified.
+// Returns the number of released inputs, counted in the shared store if it is used
+func ReleaseReservations(
+	ctx context.Context, usedUtxoCacher *UsedUtxoCacher, store IReservationStore, addr string, inputs []wallet.TxInput,
+) (int, error) {
+	var released int
+
+	if len(inputs) == 0 {
+		released = usedUtxoCacher.ReleaseAddress(addr)
+	} else {
+		released = usedUtxoCacher.Release(addr, inputs)
+	}
+
+	if store == nil {
+		return released, nil
+	}
+
+	if len(inputs) == 0 {
+		reserved, err := store.Get(ctx, addr)
+		if err != nil {
+			return 0, err
+		}
+
+		inputs = reserved
+	}
+
+	return store.Release(ctx, addr, inputs)
+}
+
+// GetSharedCacheStats returns stats of the local utxo cache with reserved inputs counted in the shared store,
+// so they include reservations of all the replicas. Pending, expired and evicted counters are local to the replica
+func GetSharedCacheStats(
+	ctx context.Context, usedUtxoCacher *UsedUtxoCacher, store IReservationStore,
+) (CacheStats, error) {
+	stats := usedUtxoCacher.GetStats()
+
+	reservations, err := store.GetReservations(ctx, "")
+	if err != nil {
+		return stats, err
+	}
+
+	stats.AddressesCount = 0
+	stats.ReservedInputs = len(reservations)
+	stats.OldestReservedAt = time.Time{}
+
+	// reservations are sorted by address
+	for i, x := range reservations {
+		if i == 0 || reservations[i-1].Addr != x.Addr {
+			stats.AddressesCount++
+		}
+
+		if stats.OldestReservedAt.IsZero() || x.ReservedAt.Before(stats.OldestReservedAt) {
+			stats.OldestReservedAt = x.ReservedAt
+		}
+	}
+
+	return stats, nil
+}
+
+// parseTxInput parses input in hash#index format
+func parseTxInput(value string) (wallet.TxInput, error) {
+	hash, indexStr, found := strings.Cut(value, "#")
+	if !found {
+		return wallet.TxInput{}, fmt.Errorf("invalid tx input: %s", value)
+	}
+
+	index, err := strconv.ParseUint(indexStr, 10, 32)
+	if err != nil {
+		return wallet.TxInput{}, fmt.Errorf("invalid tx input index: %s", value)
+	}
+
+	return wallet.NewTxInput(hash, uint32(index)), nil
+}
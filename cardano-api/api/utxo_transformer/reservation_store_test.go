@@ -0,0 +1,407 @@
+package utxotransformer
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisReservationStore(t *testing.T) {
+	const addr = "addr_test1"
+
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := NewRedisReservationStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "", time.Minute)
+
+	defer store.Close()
+
+	inputs := []wallet.TxInput{
+		wallet.NewTxInput("0x11", 1),
+		wallet.NewTxInput("0x11", 5),
+		wallet.NewTxInput("0x55", 0),
+	}
+	info := ReservationInfo{APIKeyID: "key", RequestID: "req"}
+
+	getAndSort := func() []wallet.TxInput {
+		result, err := store.Get(ctx, addr)
+		require.NoError(t, err)
+
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].String() < result[j].String()
+		})
+
+		return result
+	}
+
+	t.Run("reserve free inputs", func(t *testing.T) {
+		ok, err := store.Reserve(ctx, addr, inputs[:2], info)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, inputs[:2], getAndSort())
+	})
+
+	t.Run("reserve is all or nothing", func(t *testing.T) {
+		ok, err := store.Reserve(ctx, addr, inputs[1:], info)
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Equal(t, inputs[:2], getAndSort())
+	})
+
+	t.Run("release", func(t *testing.T) {
+		released, err := store.Release(ctx, addr, inputs[1:])
+		require.NoError(t, err)
+		require.Equal(t, 1, released)
+
+		ok, err := store.Reserve(ctx, addr, inputs[1:], info)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, inputs, getAndSort())
+	})
+
+	t.Run("reservations expire", func(t *testing.T) {
+		expiringStore := NewRedisReservationStore(
+			redis.NewClient(&redis.Options{Addr: server.Addr()}), "expiring", time.Millisecond*50)
+
+		defer expiringStore.Close()
+
+		ok, err := expiringStore.Reserve(ctx, addr, inputs[:2], info)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		time.Sleep(time.Millisecond * 60)
+
+		reserved, err := expiringStore.Get(ctx, addr)
+		require.NoError(t, err)
+		require.Empty(t, reserved)
+
+		ok, err = expiringStore.Reserve(ctx, addr, inputs[:1], info)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// info of the expired input which is not reserved again is removed too
+		infoFields, err := server.HKeys(expiringStore.getKeys(addr)[1])
+		require.NoError(t, err)
+		require.Equal(t, []string{inputs[0].String()}, infoFields)
+	})
+
+	t.Run("concurrent reservations", func(t *testing.T) {
+		const replicas = 10
+
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded int
+		)
+
+		concurrentInputs := []wallet.TxInput{wallet.NewTxInput("0x99", 0), wallet.NewTxInput("0x99", 1)}
+
+		for i := 0; i < replicas; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				ok, err := store.Reserve(ctx, "addr_concurrent", concurrentInputs, info)
+				require.NoError(t, err)
+
+				if ok {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		require.Equal(t, 1, succeeded)
+	})
+}
+
+func TestPostgresReservationStore(t *testing.T) {
+	const addr = "addr_test1"
+
+	ctx := context.Background()
+	inputs := []wallet.TxInput{
+		wallet.NewTxInput("0x11", 1),
+		wallet.NewTxInput("0x55", 0),
+	}
+	info := ReservationInfo{APIKeyID: "key", RequestID: "req"}
+	insertQuery := regexp.QuoteMeta("INSERT INTO utxo_reservations")
+	deleteExpiredQuery := regexp.QuoteMeta("DELETE FROM utxo_reservations WHERE addr = $1 AND expires_at <= NOW()")
+
+	newStore := func(t *testing.T) (*PostgresReservationStore, sqlmock.Sqlmock) {
+		t.Helper()
+
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		return NewPostgresReservationStore(db, "", time.Minute), mock
+	}
+
+	t.Run("create table", func(t *testing.T) {
+		store, mock := newStore(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS utxo_reservations")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		require.NoError(t, store.CreateTable(ctx))
+		require.NoError(t, mock.ExpectationsWereMet())
+
+		require.ErrorContains(t, NewPostgresReservationStore(nil, "x; DROP", time.Minute).CreateTable(ctx),
+			"invalid table name")
+	})
+
+	t.Run("reserve free inputs", func(t *testing.T) {
+		store, mock := newStore(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteExpiredQuery).WithArgs(addr).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		for _, input := range inputs {
+			mock.ExpectExec(insertQuery).
+				WithArgs(addr, input.Hash, int64(input.Index), int64(60_000), info.APIKeyID, info.RequestID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
+		mock.ExpectCommit()
+
+		ok, err := store.Reserve(ctx, addr, inputs, info)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reserve rolls back when input is already reserved", func(t *testing.T) {
+		store, mock := newStore(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteExpiredQuery).WithArgs(addr).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		ok, err := store.Reserve(ctx, addr, inputs, info)
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("get", func(t *testing.T) {
+		store, mock := newStore(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT tx_hash, tx_index FROM utxo_reservations")).
+			WithArgs(addr).
+			WillReturnRows(sqlmock.NewRows([]string{"tx_hash", "tx_index"}).
+				AddRow(inputs[0].Hash, int64(inputs[0].Index)).
+				AddRow(inputs[1].Hash, int64(inputs[1].Index)))
+
+		result, err := store.Get(ctx, addr)
+		require.NoError(t, err)
+		require.Equal(t, inputs, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("get reservations", func(t *testing.T) {
+		store, mock := newStore(t)
+		reservedAt := time.Now().UTC().Truncate(time.Second)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT addr, tx_hash, tx_index, reserved_at, api_key_id, request_id")).
+			WithArgs("").
+			WillReturnRows(sqlmock.NewRows(
+				[]string{"addr", "tx_hash", "tx_index", "reserved_at", "api_key_id", "request_id"}).
+				AddRow(addr, inputs[0].Hash, int64(inputs[0].Index), reservedAt, info.APIKeyID, info.RequestID))
+
+		result, err := store.GetReservations(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, []Reservation{{
+			Addr:            addr,
+			Input:           inputs[0],
+			ReservedAt:      reservedAt,
+			ReservationInfo: info,
+		}}, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("release", func(t *testing.T) {
+		store, mock := newStore(t)
+		query := regexp.QuoteMeta("DELETE FROM utxo_reservations WHERE addr = $1 AND tx_hash = $2 AND tx_index = $3")
+
+		mock.ExpectExec(query).WithArgs(addr, inputs[0].Hash, int64(inputs[0].Index)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(query).WithArgs(addr, inputs[1].Hash, int64(inputs[1].Index)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		released, err := store.Release(ctx, addr, inputs)
+		require.NoError(t, err)
+		require.Equal(t, 1, released)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSharedUtxosTransformer(t *testing.T) {
+	const addr = "addr_test1"
+
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	newReplica := func() *SharedUtxosTransformer {
+		return &SharedUtxosTransformer{
+			Store: NewRedisReservationStore(
+				redis.NewClient(&redis.Options{Addr: server.Addr()}), "", time.Minute),
+			UtxoCacher: NewUsedUtxoCacher(time.Minute),
+			Addr:       addr,
+		}
+	}
+	utxos := []wallet.Utxo{
+		{Hash: "0x11", Index: 0, Amount: 100},
+		{Hash: "0x11", Index: 1, Amount: 200},
+		{Hash: "0x22", Index: 0, Amount: 300},
+	}
+	firstReplica, secondReplica := newReplica(), newReplica()
+
+	require.NoError(t, firstReplica.Load(ctx))
+	require.NoError(t, secondReplica.Load(ctx))
+
+	// both replicas select the same utxo, only the first one reserves it
+	ok, err := firstReplica.Reserve(ctx, []wallet.TxInput{wallet.NewTxInput("0x11", 0)})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = secondReplica.Reserve(ctx, []wallet.TxInput{wallet.NewTxInput("0x11", 0)})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Empty(t, secondReplica.UtxoCacher.Get(addr))
+
+	// after reloading the second replica does not offer reserved utxo anymore
+	require.NoError(t, secondReplica.Load(ctx))
+	require.Equal(t, utxos[1:], secondReplica.TransformUtxos(utxos))
+
+	ok, err = secondReplica.Reserve(ctx, []wallet.TxInput{wallet.NewTxInput("0x11", 1)})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.Equal(t, utxos[1:], firstReplica.TransformUtxos(utxos))
+	require.NoError(t, firstReplica.Load(ctx))
+	require.Equal(t, utxos[2:], firstReplica.TransformUtxos(utxos))
+}
+
+func TestReleaseReservations(t *testing.T) {
+	const addr = "addr_test1"
+
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := NewRedisReservationStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "", time.Minute)
+
+	defer store.Close()
+
+	inputs := []wallet.TxInput{
+		wallet.NewTxInput("0x11", 0),
+		wallet.NewTxInput("0x11", 1),
+		wallet.NewTxInput("0x22", 0),
+	}
+	transformer := &SharedUtxosTransformer{
+		Store:      store,
+		UtxoCacher: NewUsedUtxoCacher(time.Minute),
+		Addr:       addr,
+	}
+
+	ok, err := transformer.Reserve(ctx, inputs)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	t.Run("inputs", func(t *testing.T) {
+		released, err := ReleaseReservations(ctx, transformer.UtxoCacher, store, addr, inputs[:1])
+		require.NoError(t, err)
+		require.Equal(t, 1, released)
+		require.Len(t, transformer.UtxoCacher.Get(addr), 2)
+
+		reserved, err := store.Get(ctx, addr)
+		require.NoError(t, err)
+		require.Len(t, reserved, 2)
+	})
+
+	t.Run("whole address", func(t *testing.T) {
+		released, err := ReleaseReservations(ctx, transformer.UtxoCacher, store, addr, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, released)
+		require.Empty(t, transformer.UtxoCacher.Get(addr))
+
+		reserved, err := store.Get(ctx, addr)
+		require.NoError(t, err)
+		require.Empty(t, reserved)
+	})
+
+	t.Run("without store", func(t *testing.T) {
+		cacher := NewUsedUtxoCacher(time.Minute)
+		cacher.Add(addr, inputs)
+
+		released, err := ReleaseReservations(ctx, cacher, nil, addr, inputs[1:])
+		require.NoError(t, err)
+		require.Equal(t, 2, released)
+		require.Equal(t, inputs[:1], cacher.Get(addr))
+	})
+}
+
+func TestRedisReservationStoreGetReservations(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := NewRedisReservationStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "", time.Minute)
+
+	defer store.Close()
+
+	firstInfo := ReservationInfo{APIKeyID: "key1", RequestID: "req1"}
+	secondInfo := ReservationInfo{APIKeyID: "key2", RequestID: "req2"}
+	startTime := time.Now().UTC().Truncate(time.Millisecond)
+
+	ok, err := store.Reserve(ctx, "addr_b", []wallet.TxInput{wallet.NewTxInput("0x22", 0)}, secondInfo)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = store.Reserve(ctx, "addr_a",
+		[]wallet.TxInput{wallet.NewTxInput("0x11", 1), wallet.NewTxInput("0x11", 0)}, firstInfo)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	t.Run("address", func(t *testing.T) {
+		result, err := store.GetReservations(ctx, "addr_b")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, "addr_b", result[0].Addr)
+		require.Equal(t, wallet.NewTxInput("0x22", 0), result[0].Input)
+		require.Equal(t, secondInfo, result[0].ReservationInfo)
+		require.False(t, result[0].ReservedAt.Before(startTime))
+		require.False(t, result[0].ReservedAt.After(time.Now().UTC()))
+	})
+
+	t.Run("all addresses", func(t *testing.T) {
+		result, err := store.GetReservations(ctx, "")
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+		require.Equal(t, "addr_a", result[0].Addr)
+		require.Equal(t, wallet.NewTxInput("0x11", 0), result[0].Input)
+		require.Equal(t, wallet.NewTxInput("0x11", 1), result[1].Input)
+		require.Equal(t, firstInfo, result[1].ReservationInfo)
+		require.Equal(t, "addr_b", result[2].Addr)
+	})
+
+	t.Run("shared stats", func(t *testing.T) {
+		cacher := NewUsedUtxoCacher(time.Minute)
+		cacher.Add("addr_c", []wallet.TxInput{wallet.NewTxInput("0x33", 0)})
+
+		stats, err := GetSharedCacheStats(ctx, cacher, store)
+		require.NoError(t, err)
+		require.Equal(t, 2, stats.AddressesCount)
+		require.Equal(t, 3, stats.ReservedInputs)
+		require.False(t, stats.OldestReservedAt.Before(startTime))
+		require.Equal(t, time.Minute, stats.ReservationTimeout)
+	})
+}
@@ -0,0 +1,67 @@
+package utxotransformer
+
+import (
+	"context"
+
+	"github.com/Ethernal-Tech/cardano-api/telemetry"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SharedUtxosTransformer filters out inputs reserved in the shared reservation store.
+// Reserved inputs must be loaded with Load before the tx is built
+type SharedUtxosTransformer struct {
+	Store           IReservationStore
+	UtxoCacher      *UsedUtxoCacher
+	Addr            string
+	ReservationInfo ReservationInfo
+
+	reserved []wallet.TxInput
+}
+
+var _ IUtxosTransformer = (*SharedUtxosTransformer)(nil)
+
+// Load retrieves inputs currently reserved in the shared store
+func (u *SharedUtxosTransformer) Load(ctx context.Context) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "utxoReservationStore.Get", attribute.String("addr", u.Addr))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	reserved, err := u.Store.Get(ctx, u.Addr)
+	if err != nil {
+		return err
+	}
+
+	u.reserved = reserved
+
+	span.SetAttributes(attribute.Int("reserved", len(reserved)))
+
+	return nil
+}
+
+func (u *SharedUtxosTransformer) TransformUtxos(utxos []wallet.Utxo) []wallet.Utxo {
+	return filterOutUtxos(filterOutUtxos(utxos, u.reserved), u.UtxoCacher.Get(u.Addr))
+}
+
+// UpdateUtxos only updates local cache, inputs are reserved in the shared store with Reserve
+func (u *SharedUtxosTransformer) UpdateUtxos(usedInputs []wallet.TxInput) {
+	u.UtxoCacher.AddWithInfo(u.Addr, usedInputs, u.ReservationInfo)
+}
+
+// Reserve atomically reserves the inputs in the shared store and, if successful, in the local cache.
+// Returns false if some of the inputs have been reserved in the meantime by another replica
+func (u *SharedUtxosTransformer) Reserve(ctx context.Context, usedInputs []wallet.TxInput) (_ bool, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "utxoReservationStore.Reserve",
+		attribute.String("addr", u.Addr), attribute.Int("inputs", len(usedInputs)))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	ok, err := u.Store.Reserve(ctx, u.Addr, usedInputs, u.ReservationInfo)
+	if err != nil || !ok {
+		span.SetAttributes(attribute.Bool("alreadyReserved", err == nil))
+
+		return false, err
+	}
+
+	u.UpdateUtxos(usedInputs)
+
+	return true, nil
+}
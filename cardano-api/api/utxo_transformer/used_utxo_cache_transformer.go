@@ -3,8 +3,9 @@ package utxotransformer
 import "github.com/Ethernal-Tech/cardano-infrastructure/wallet"
 
 type CacheUtxosTransformer struct {
-	UtxoCacher *UsedUtxoCacher
-	Addr       string
+	UtxoCacher      *UsedUtxoCacher
+	Addr            string
+	ReservationInfo ReservationInfo
 }
 
 var _ IUtxosTransformer = (*CacheUtxosTransformer)(nil)
@@ -16,5 +17,5 @@ func (u *CacheUtxosTransformer) TransformUtxos(utxos []wallet.Utxo) []wallet.Utx
 }
 
 func (u *CacheUtxosTransformer) UpdateUtxos(usedInputs []wallet.TxInput) {
-	u.UtxoCacher.Add(u.Addr, usedInputs)
+	u.UtxoCacher.AddWithInfo(u.Addr, usedInputs, u.ReservationInfo)
 }
@@ -1,31 +1,149 @@
 package utxotransformer
 
 import (
+	"container/list"
+	"context"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
 )
 
+const minJanitorInterval = time.Second
+
+// ReservationInfo identifies who reserved the inputs
+type ReservationInfo struct {
+	APIKeyID  string
+	RequestID string
+}
+
 type txInputWithTime struct {
 	wallet.TxInput
 	Time time.Time
+	ReservationInfo
+}
+
+type utxoWithTime struct {
+	wallet.Utxo
+	Time time.Time
+}
+
+type Reservation struct {
+	Addr       string
+	Input      wallet.TxInput
+	ReservedAt time.Time
+	ReservationInfo
+}
+
+type CacheStats struct {
+	AddressesCount     int
+	ReservedInputs     int
+	PendingUtxos       int
+	OldestReservedAt   time.Time
+	ReservationTimeout time.Duration
+	// ExpiredInputs is total number of reservations removed because they expired
+	ExpiredInputs uint64
+	// EvictedAddresses and EvictedInputs are totals of entries removed because the cache limits were reached
+	EvictedAddresses uint64
+	EvictedInputs    uint64
+}
+
+type UsedUtxoCacherOption func(*UsedUtxoCacher)
+
+// WithMaxAddresses limits number of addresses in the cache (0 means without limit)
+func WithMaxAddresses(maxAddresses int) UsedUtxoCacherOption {
+	return func(c *UsedUtxoCacher) {
+		c.maxAddresses = maxAddresses
+	}
+}
+
+// WithMaxInputs limits number of reserved inputs in the cache (0 means without limit)
+func WithMaxInputs(maxInputs int) UsedUtxoCacherOption {
+	return func(c *UsedUtxoCacher) {
+		c.maxInputs = maxInputs
+	}
 }
 
 type UsedUtxoCacher struct {
 	timeout time.Duration
 	data    map[string]map[string]txInputWithTime
+	// pending contains outputs of not yet confirmed txs built by this service (used for tx chaining)
+	pending map[string]map[string]utxoWithTime
 	lock    sync.Mutex
+
+	maxAddresses int
+	maxInputs    int
+	inputsCount  int
+	// lru contains addresses ordered by the last access (front is the most recently used)
+	lru         *list.List
+	lruElements map[string]*list.Element
+
+	expiredInputs    uint64
+	evictedAddresses uint64
+	evictedInputs    uint64
+}
+
+func NewUsedUtxoCacher(timeout time.Duration, opts ...UsedUtxoCacherOption) *UsedUtxoCacher {
+	cacher := &UsedUtxoCacher{
+		data:        map[string]map[string]txInputWithTime{},
+		pending:     map[string]map[string]utxoWithTime{},
+		timeout:     timeout,
+		lru:         list.New(),
+		lruElements: map[string]*list.Element{},
+	}
+
+	for _, opt := range opts {
+		opt(cacher)
+	}
+
+	return cacher
+}
+
+// Start runs janitor which periodically removes expired entries until the context is done
+func (c *UsedUtxoCacher) Start(ctx context.Context) {
+	ticker := time.NewTicker(max(c.timeout, minJanitorInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.RemoveExpired()
+		}
+	}
 }
 
-func NewUsedUtxoCacher(timeout time.Duration) *UsedUtxoCacher {
-	return &UsedUtxoCacher{
-		data:    map[string]map[string]txInputWithTime{},
-		timeout: timeout,
+// RemoveExpired removes all the expired reservations and pending outputs
+func (c *UsedUtxoCacher) RemoveExpired() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tm := time.Now().UTC()
+
+	for addr := range c.data {
+		c.removeExpiredInputs(addr, tm)
+		c.cleanupAddr(addr)
+	}
+
+	for addr, submap := range c.pending {
+		for k, v := range submap {
+			if tm.Sub(v.Time) >= c.timeout {
+				delete(submap, k)
+			}
+		}
+
+		c.cleanupAddr(addr)
 	}
 }
 
 func (c *UsedUtxoCacher) Add(addr string, txInputs []wallet.TxInput) {
+	c.AddWithInfo(addr, txInputs, ReservationInfo{})
+}
+
+// AddWithInfo reserves inputs and records who reserved them
+func (c *UsedUtxoCacher) AddWithInfo(addr string, txInputs []wallet.TxInput, info ReservationInfo) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
@@ -41,20 +159,25 @@ func (c *UsedUtxoCacher) Add(addr string, txInputs []wallet.TxInput) {
 		submap = child
 	}
 
+	c.touch(addr)
+
 	// remove old ones
-	for k, v := range submap {
-		if tm.Sub(v.Time) >= c.timeout {
-			delete(submap, k)
-		}
-	}
+	c.removeExpiredInputs(addr, tm)
 
 	// add new ones
 	for _, x := range txInputs {
+		if _, exists := submap[x.String()]; !exists {
+			c.inputsCount++
+		}
+
 		submap[x.String()] = txInputWithTime{
-			TxInput: x,
-			Time:    tm,
+			TxInput:         x,
+			Time:            tm,
+			ReservationInfo: info,
 		}
 	}
+
+	c.evict(addr)
 }
 
 func (c *UsedUtxoCacher) Get(addr string) []wallet.TxInput {
@@ -68,16 +191,298 @@ func (c *UsedUtxoCacher) Get(addr string) []wallet.TxInput {
 		return nil
 	}
 
+	c.touch(addr)
+
+	// retrieve those that are still valid (remove expired and the address if nothing is left)
+	c.removeExpiredInputs(addr, tm)
+	c.cleanupAddr(addr)
+
 	result := make([]wallet.TxInput, 0, len(submap))
 
-	// retrieve those that are still valid (remove expired)
+	for _, v := range submap {
+		result = append(result, v.TxInput)
+	}
+
+	return result
+}
+
+// AddPending adds outputs of the not yet confirmed tx which can be spent by the next (chained) tx
+func (c *UsedUtxoCacher) AddPending(addr string, utxos []wallet.Utxo) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tm := time.Now().UTC()
+
+	submap, exists := c.pending[addr]
+	if !exists {
+		submap = map[string]utxoWithTime{}
+		c.pending[addr] = submap
+	}
+
+	c.touch(addr)
+
+	for _, x := range utxos {
+		submap[wallet.TxInput{Hash: x.Hash, Index: x.Index}.String()] = utxoWithTime{
+			Utxo: x,
+			Time: tm,
+		}
+	}
+
+	c.evict(addr)
+}
+
+// GetPending returns not expired outputs of the not yet confirmed txs for the address
+func (c *UsedUtxoCacher) GetPending(addr string) []wallet.Utxo {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tm := time.Now().UTC()
+	submap, exists := c.pending[addr]
+
+	if !exists {
+		return nil
+	}
+
+	result := make([]wallet.Utxo, 0, len(submap))
+
 	for k, v := range submap {
 		if tm.Sub(v.Time) >= c.timeout {
 			delete(submap, k)
 		} else {
-			result = append(result, v.TxInput)
+			result = append(result, v.Utxo)
 		}
 	}
 
+	c.cleanupAddr(addr)
+
 	return result
 }
+
+// GetReservations returns not expired reservations for the address (or for all addresses if addr is empty)
+// sorted by address and reservation time
+func (c *UsedUtxoCacher) GetReservations(addr string) []Reservation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tm := time.Now().UTC()
+	result := []Reservation{}
+
+	for currAddr, submap := range c.data {
+		if addr != "" && addr != currAddr {
+			continue
+		}
+
+		c.removeExpiredInputs(currAddr, tm)
+
+		for _, v := range submap {
+			result = append(result, Reservation{
+				Addr:            currAddr,
+				Input:           v.TxInput,
+				ReservedAt:      v.Time,
+				ReservationInfo: v.ReservationInfo,
+			})
+		}
+
+		c.cleanupAddr(currAddr)
+	}
+
+	sortReservations(result)
+
+	return result
+}
+
+// sortReservations sorts reservations by address, reservation time and input
+func sortReservations(reservations []Reservation) {
+	sort.Slice(reservations, func(i, j int) bool {
+		if reservations[i].Addr != reservations[j].Addr {
+			return reservations[i].Addr < reservations[j].Addr
+		}
+
+		if !reservations[i].ReservedAt.Equal(reservations[j].ReservedAt) {
+			return reservations[i].ReservedAt.Before(reservations[j].ReservedAt)
+		}
+
+		return reservations[i].Input.String() < reservations[j].Input.String()
+	})
+}
+
+// Release removes reservations of the inputs for the address and returns number of released inputs
+func (c *UsedUtxoCacher) Release(addr string, txInputs []wallet.TxInput) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	submap, exists := c.data[addr]
+	if !exists {
+		return 0
+	}
+
+	released := 0
+
+	for _, x := range txInputs {
+		if _, exists := submap[x.String()]; exists {
+			c.removeInput(submap, x.String())
+
+			released++
+		}
+	}
+
+	c.cleanupAddr(addr)
+
+	return released
+}
+
+// ReleaseAddress removes all the reservations and pending outputs for the address
+// and returns number of released inputs
+func (c *UsedUtxoCacher) ReleaseAddress(addr string) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.removeAddr(addr)
+}
+
+// GetStats returns cache wide statistics (expired items are not counted)
+func (c *UsedUtxoCacher) GetStats() CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tm := time.Now().UTC()
+	stats := CacheStats{
+		ReservationTimeout: c.timeout,
+		ExpiredInputs:      c.expiredInputs,
+		EvictedAddresses:   c.evictedAddresses,
+		EvictedInputs:      c.evictedInputs,
+	}
+
+	for _, submap := range c.data {
+		count := 0
+
+		for _, v := range submap {
+			if tm.Sub(v.Time) >= c.timeout {
+				continue
+			}
+
+			count++
+
+			if stats.OldestReservedAt.IsZero() || v.Time.Before(stats.OldestReservedAt) {
+				stats.OldestReservedAt = v.Time
+			}
+		}
+
+		if count > 0 {
+			stats.AddressesCount++
+			stats.ReservedInputs += count
+		}
+	}
+
+	for _, submap := range c.pending {
+		for _, v := range submap {
+			if tm.Sub(v.Time) < c.timeout {
+				stats.PendingUtxos++
+			}
+		}
+	}
+
+	return stats
+}
+
+// touch marks address as the most recently used
+func (c *UsedUtxoCacher) touch(addr string) {
+	if elem, exists := c.lruElements[addr]; exists {
+		c.lru.MoveToFront(elem)
+	} else {
+		c.lruElements[addr] = c.lru.PushFront(addr)
+	}
+}
+
+// evict removes the least recently used addresses (except the current one) while the limits are exceeded.
+// If the current address alone exceeds the inputs limit, its oldest reservations are removed
+func (c *UsedUtxoCacher) evict(currAddr string) {
+	for elem := c.lru.Back(); elem != nil && c.isOverLimit(); {
+		addr, _ := elem.Value.(string)
+		elem = elem.Prev()
+
+		if addr == currAddr {
+			continue
+		}
+
+		c.evictedInputs += uint64(c.removeAddr(addr)) //nolint:gosec
+		c.evictedAddresses++
+	}
+
+	if c.maxInputs <= 0 || c.inputsCount <= c.maxInputs {
+		return
+	}
+
+	submap := c.data[currAddr]
+	inputs := make([]txInputWithTime, 0, len(submap))
+
+	for _, v := range submap {
+		inputs = append(inputs, v)
+	}
+
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].Time.Before(inputs[j].Time)
+	})
+
+	for i := 0; i < len(inputs) && c.inputsCount > c.maxInputs; i++ {
+		c.removeInput(submap, inputs[i].String())
+		c.evictedInputs++
+	}
+}
+
+func (c *UsedUtxoCacher) isOverLimit() bool {
+	return (c.maxAddresses > 0 && c.lru.Len() > c.maxAddresses) ||
+		(c.maxInputs > 0 && c.inputsCount > c.maxInputs)
+}
+
+func (c *UsedUtxoCacher) removeExpiredInputs(addr string, tm time.Time) {
+	submap := c.data[addr]
+
+	for k, v := range submap {
+		if tm.Sub(v.Time) >= c.timeout {
+			c.removeInput(submap, k)
+			c.expiredInputs++
+		}
+	}
+}
+
+func (c *UsedUtxoCacher) removeInput(submap map[string]txInputWithTime, key string) {
+	delete(submap, key)
+
+	c.inputsCount--
+}
+
+// removeAddr removes all the entries for the address and returns number of removed reservations
+func (c *UsedUtxoCacher) removeAddr(addr string) int {
+	removed := len(c.data[addr])
+
+	c.inputsCount -= removed
+
+	delete(c.data, addr)
+	delete(c.pending, addr)
+
+	if elem, exists := c.lruElements[addr]; exists {
+		c.lru.Remove(elem)
+		delete(c.lruElements, addr)
+	}
+
+	return removed
+}
+
+// cleanupAddr removes empty maps for the address and the address itself if it has no entries
+func (c *UsedUtxoCacher) cleanupAddr(addr string) {
+	if submap, exists := c.data[addr]; exists && len(submap) == 0 {
+		delete(c.data, addr)
+	}
+
+	if submap, exists := c.pending[addr]; exists && len(submap) == 0 {
+		delete(c.pending, addr)
+	}
+
+	_, hasData := c.data[addr]
+	_, hasPending := c.pending[addr]
+
+	if !hasData && !hasPending {
+		c.removeAddr(addr)
+	}
+}
@@ -1,6 +1,7 @@
 package utxotransformer
 
 import (
+	"fmt"
 	"sort"
 	"testing"
 	"time"
@@ -69,3 +70,141 @@ func TestUsedItemsCacher(t *testing.T) {
 		require.Equal(t, []wallet.TxInput{}, getAndSort(secondAddr))
 	})
 }
+
+func TestUsedUtxoCacherReservations(t *testing.T) {
+	cacher := NewUsedUtxoCacher(time.Millisecond * 100)
+	info := ReservationInfo{APIKeyID: "key1", RequestID: "req1"}
+	inputs := []wallet.TxInput{
+		{Hash: "0x11", Index: 1},
+		{Hash: "0x22", Index: 0},
+	}
+
+	cacher.AddWithInfo("addr2", inputs, info)
+	cacher.Add("addr1", inputs[:1])
+	cacher.AddPending("addr2", []wallet.Utxo{{Hash: "0x33", Index: 0, Amount: 10}})
+
+	reservations := cacher.GetReservations("")
+	require.Len(t, reservations, 3)
+	require.Equal(t, "addr1", reservations[0].Addr)
+	require.Equal(t, ReservationInfo{}, reservations[0].ReservationInfo)
+	require.Equal(t, inputs[0], reservations[1].Input)
+	require.Equal(t, info, reservations[1].ReservationInfo)
+
+	require.Len(t, cacher.GetReservations("addr2"), 2)
+	require.Empty(t, cacher.GetReservations("addr3"))
+
+	stats := cacher.GetStats()
+	require.Equal(t, 2, stats.AddressesCount)
+	require.Equal(t, 3, stats.ReservedInputs)
+	require.Equal(t, 1, stats.PendingUtxos)
+	require.False(t, stats.OldestReservedAt.IsZero())
+
+	require.Equal(t, 1, cacher.Release("addr2", []wallet.TxInput{inputs[1], {Hash: "0x99"}}))
+	require.Equal(t, []wallet.TxInput{inputs[0]}, cacher.Get("addr2"))
+
+	require.Equal(t, 1, cacher.ReleaseAddress("addr2"))
+	require.Empty(t, cacher.Get("addr2"))
+	require.Empty(t, cacher.GetPending("addr2"))
+
+	time.Sleep(time.Millisecond * 120)
+
+	stats = cacher.GetStats()
+	require.Equal(t, 0, stats.AddressesCount)
+	require.Equal(t, 0, stats.ReservedInputs)
+	require.Empty(t, cacher.GetReservations(""))
+}
+
+func TestUsedUtxoCacherEviction(t *testing.T) {
+	input := func(hash string, index uint32) wallet.TxInput {
+		return wallet.TxInput{Hash: hash, Index: index}
+	}
+
+	t.Run("max addresses", func(t *testing.T) {
+		cacher := NewUsedUtxoCacher(time.Minute, WithMaxAddresses(2))
+
+		cacher.Add("addr1", []wallet.TxInput{input("0x1", 0)})
+		cacher.Add("addr2", []wallet.TxInput{input("0x2", 0)})
+		cacher.Get("addr1") // addr1 becomes most recently used
+		cacher.Add("addr3", []wallet.TxInput{input("0x3", 0), input("0x3", 1)})
+
+		require.Len(t, cacher.Get("addr1"), 1)
+		require.Nil(t, cacher.Get("addr2"))
+		require.Len(t, cacher.Get("addr3"), 2)
+
+		stats := cacher.GetStats()
+		require.Equal(t, 2, stats.AddressesCount)
+		require.Equal(t, uint64(1), stats.EvictedAddresses)
+		require.Equal(t, uint64(1), stats.EvictedInputs)
+	})
+
+	t.Run("max inputs", func(t *testing.T) {
+		cacher := NewUsedUtxoCacher(time.Minute, WithMaxInputs(3))
+
+		cacher.Add("addr1", []wallet.TxInput{input("0x1", 0), input("0x1", 1)})
+		cacher.Add("addr2", []wallet.TxInput{input("0x2", 0), input("0x2", 1)})
+
+		require.Nil(t, cacher.Get("addr1"))
+		require.Len(t, cacher.Get("addr2"), 2)
+
+		// single address over the limit keeps only the newest inputs
+		time.Sleep(time.Millisecond * 5)
+		cacher.Add("addr2", []wallet.TxInput{input("0x2", 2), input("0x2", 3)})
+
+		getAndSort := func(addr string) []wallet.TxInput {
+			result := cacher.Get(addr)
+
+			sort.Slice(result, func(i, j int) bool {
+				return result[i].String() < result[j].String()
+			})
+
+			return result
+		}
+
+		require.Len(t, getAndSort("addr2"), 3)
+		require.Contains(t, getAndSort("addr2"), input("0x2", 2))
+		require.Contains(t, getAndSort("addr2"), input("0x2", 3))
+
+		stats := cacher.GetStats()
+		require.Equal(t, 3, stats.ReservedInputs)
+		require.Equal(t, uint64(3), stats.EvictedInputs)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		cacher := NewUsedUtxoCacher(time.Millisecond*50, WithMaxAddresses(10))
+
+		for i := range 5 {
+			cacher.Add(fmt.Sprintf("addr%d", i), []wallet.TxInput{input("0x1", uint32(i))})
+		}
+
+		cacher.AddPending("addr0", []wallet.Utxo{{Hash: "0x9"}})
+
+		time.Sleep(time.Millisecond * 60)
+		cacher.RemoveExpired()
+
+		require.Empty(t, cacher.data)
+		require.Empty(t, cacher.pending)
+		require.Equal(t, 0, cacher.lru.Len())
+		require.Equal(t, 0, cacher.inputsCount)
+		require.Equal(t, uint64(5), cacher.GetStats().ExpiredInputs)
+	})
+
+	t.Run("expired address is removed on read", func(t *testing.T) {
+		cacher := NewUsedUtxoCacher(time.Millisecond*50, WithMaxAddresses(10))
+
+		cacher.Add("addr0", []wallet.TxInput{input("0x1", 0)})
+		cacher.Add("addr1", []wallet.TxInput{input("0x1", 1)})
+		cacher.AddPending("addr2", []wallet.Utxo{{Hash: "0x9"}})
+		cacher.Add("addr3", []wallet.TxInput{input("0x1", 3)})
+
+		time.Sleep(time.Millisecond * 60)
+
+		require.Empty(t, cacher.Get("addr0"))
+		require.Empty(t, cacher.GetPending("addr2"))
+		require.Empty(t, cacher.GetReservations("addr3"))
+		require.Len(t, cacher.data, 1)
+		require.Contains(t, cacher.data, "addr1")
+		require.Empty(t, cacher.pending)
+		require.Equal(t, 1, cacher.lru.Len())
+		require.Equal(t, 1, cacher.inputsCount)
+	})
+}
@@ -0,0 +1,78 @@
+package cardanotx
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+)
+
+// GetSpendableAmount returns the sum of lovelace of the largest utxos which can be used as inputs of a single tx
+func GetSpendableAmount(utxos []wallet.Utxo, maxInputs int) uint64 {
+	amounts := make([]uint64, len(utxos))
+	for i, utxo := range utxos {
+		amounts[i] = utxo.Amount
+	}
+
+	sort.Slice(amounts, func(i, j int) bool {
+		return amounts[i] > amounts[j]
+	})
+
+	if maxInputs > 0 && len(amounts) > maxInputs {
+		amounts = amounts[:maxInputs]
+	}
+
+	sum := uint64(0)
+	for _, amount := range amounts {
+		sum += amount
+	}
+
+	return sum
+}
+
+// FindAmountForDebit iteratively searches for the largest amount whose debit (amount plus all the fees) does not
+// exceed maxDebit. The first tried amount is maxDebit reduced by estimatedOverhead and every next one is maxDebit
+// reduced by the overhead of the previous try, because fees slightly depend on the amount (tx size, min utxo)
+func FindAmountForDebit(
+	maxDebit uint64, estimatedOverhead uint64, maxIterations int, getDebit func(amount uint64) (uint64, error),
+) (uint64, error) {
+	if estimatedOverhead >= maxDebit {
+		return 0, fmt.Errorf("fees of %d are greater than available %d", estimatedOverhead, maxDebit)
+	}
+
+	amount := maxDebit - estimatedOverhead
+	best, found := uint64(0), false
+
+	for i := 0; i < maxIterations; i++ {
+		debit, err := getDebit(amount)
+		if err != nil {
+			return 0, err
+		}
+
+		if debit <= maxDebit && (!found || amount > best) {
+			best, found = amount, true
+		}
+
+		if debit == maxDebit || debit < amount {
+			break
+		}
+
+		overhead := debit - amount
+		if overhead >= maxDebit {
+			break
+		}
+
+		next := maxDebit - overhead
+		if next == amount || (found && next <= best) {
+			break
+		}
+
+		amount = next
+	}
+
+	if !found {
+		return 0, fmt.Errorf("no amount fits into %d after fees", maxDebit)
+	}
+
+	return best, nil
+}
@@ -0,0 +1,67 @@
+package cardanotx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSpendableAmount(t *testing.T) {
+	utxos := []wallet.Utxo{
+		{Hash: "a", Amount: 1_000_000},
+		{Hash: "b", Amount: 5_000_000},
+		{Hash: "c", Amount: 2_000_000},
+		{Hash: "d", Amount: 3_000_000},
+	}
+
+	require.Equal(t, uint64(11_000_000), GetSpendableAmount(utxos, 0))
+	require.Equal(t, uint64(11_000_000), GetSpendableAmount(utxos, 10))
+	require.Equal(t, uint64(8_000_000), GetSpendableAmount(utxos, 2))
+	require.Equal(t, uint64(0), GetSpendableAmount(nil, 2))
+	// input order is not changed
+	require.Equal(t, "a", utxos[0].Hash)
+}
+
+func TestFindAmountForDebit(t *testing.T) {
+	// fee grows by 1 lovelace for every 1_000_000 of the amount
+	getDebit := func(fixedFee uint64) func(amount uint64) (uint64, error) {
+		return func(amount uint64) (uint64, error) {
+			return amount + fixedFee + amount/1_000_000, nil
+		}
+	}
+
+	t.Run("converges from below", func(t *testing.T) {
+		amount, err := FindAmountForDebit(100_000_000, 1_000_000, 5, getDebit(200_000))
+		require.NoError(t, err)
+
+		debit, _ := getDebit(200_000)(amount)
+		require.LessOrEqual(t, debit, uint64(100_000_000))
+		require.Equal(t, uint64(100_000_000-200_000-99), amount)
+	})
+
+	t.Run("converges from above", func(t *testing.T) {
+		amount, err := FindAmountForDebit(100_000_000, 0, 5, getDebit(200_000))
+		require.NoError(t, err)
+
+		debit, _ := getDebit(200_000)(amount)
+		require.LessOrEqual(t, debit, uint64(100_000_000))
+		require.Greater(t, amount, uint64(100_000_000-200_000-100))
+	})
+
+	t.Run("fees greater than available", func(t *testing.T) {
+		_, err := FindAmountForDebit(100_000, 200_000, 5, getDebit(200_000))
+		require.ErrorContains(t, err, "fees of 200000 are greater than available 100000")
+
+		_, err = FindAmountForDebit(300_000, 100_000, 5, getDebit(400_000))
+		require.ErrorContains(t, err, "no amount fits into 300000 after fees")
+	})
+
+	t.Run("debit error", func(t *testing.T) {
+		_, err := FindAmountForDebit(100_000_000, 0, 5, func(uint64) (uint64, error) {
+			return 0, errors.New("not enough funds")
+		})
+		require.ErrorContains(t, err, "not enough funds")
+	})
+}
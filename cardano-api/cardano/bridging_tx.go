@@ -0,0 +1,113 @@
+package cardanotx
+
+import (
+	"context"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-infrastructure/sendtx"
+)
+
+// CreateVersionedBridgingTx builds the same bridging tx as sendtx.TxSender.CreateBridgingTx,
+// but with the metadata encoded in the given version and with the optional refund address
+func CreateVersionedBridgingTx(
+	ctx context.Context, txSender *sendtx.TxSender, srcConfig sendtx.ChainConfig,
+	txDto sendtx.BridgingTxDto, refundAddr string, version common.MetadataVersion,
+) (*sendtx.TxInfo, *sendtx.BridgingRequestMetadata, error) {
+	metadataRaw, metadata, output, err := createVersionedBridgingTxOutput(
+		ctx, txSender, srcConfig, txDto, refundAddr, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txInfo, err := txSender.CreateTxGeneric(ctx, sendtx.GenericTxDto{
+		SrcChainID:             txDto.SrcChainID,
+		SenderAddr:             txDto.SenderAddr,
+		SenderAddrPolicyScript: txDto.SenderAddrPolicyScript,
+		Metadata:               metadataRaw,
+		Receivers:              []sendtx.TxReceiversDto{output},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return txInfo, metadata, nil
+}
+
+// CalculateVersionedBridgingTxFee returns the fee of the tx built by CreateVersionedBridgingTx, so the calculated fee
+// includes the bytes of the versioned metadata and the refund address. Returned metadata is the one sendtx would create
+func CalculateVersionedBridgingTxFee(
+	ctx context.Context, txSender *sendtx.TxSender, srcConfig sendtx.ChainConfig,
+	txDto sendtx.BridgingTxDto, refundAddr string, version common.MetadataVersion,
+) (*sendtx.TxFeeInfo, *sendtx.BridgingRequestMetadata, error) {
+	txInfo, metadata, err := CreateVersionedBridgingTx(ctx, txSender, srcConfig, txDto, refundAddr, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fee, err := GetTxFee(txInfo.TxRaw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &sendtx.TxFeeInfo{
+		Fee:                 fee,
+		ChangeMinUtxoAmount: txInfo.ChangeMinUtxoAmount,
+	}, metadata, nil
+}
+
+// createVersionedBridgingTxOutput returns the json metadata in the given version, the same metadata as created by
+// sendtx and the bridging address output.
+// Bridging fee, together with the top up of the output below min utxo, is calculated by sendtx from the protocol
+// parameters, so the output is the same as the one of the tx created by sendtx
+func createVersionedBridgingTxOutput(
+	ctx context.Context, txSender *sendtx.TxSender, srcConfig sendtx.ChainConfig,
+	txDto sendtx.BridgingTxDto, refundAddr string, version common.MetadataVersion,
+) ([]byte, *sendtx.BridgingRequestMetadata, sendtx.TxReceiversDto, error) {
+	bridgingFee, err := txSender.GetBridgingFee(ctx, txDto)
+	if err != nil {
+		return nil, nil, sendtx.TxReceiversDto{}, err
+	}
+
+	receiversSum := uint64(0)
+	for _, receiver := range txDto.Receivers {
+		receiversSum += receiver.Amount
+	}
+
+	bridgingAddress := txDto.BridgingAddress
+	if bridgingAddress == "" {
+		bridgingAddress = srcConfig.MultiSigAddr
+	}
+
+	sendTxMetadata, err := txSender.CreateMetadata(
+		txDto.SenderAddr, txDto.SrcChainID, txDto.DstChainID, txDto.Receivers, bridgingFee)
+	if err != nil {
+		return nil, nil, sendtx.TxReceiversDto{}, err
+	}
+
+	metadata := common.BridgingRequestMetadata{
+		BridgingTxType:     common.BridgingTxTypeBridgingRequest,
+		DestinationChainID: sendTxMetadata.DestinationChainID,
+		SenderAddr:         sendTxMetadata.SenderAddr,
+		Transactions:       make([]common.BridgingRequestMetadataTransaction, len(sendTxMetadata.Transactions)),
+		FeeAmount:          sendTxMetadata.BridgingFee,
+		RefundAddr:         common.SplitString(refundAddr, common.MetadataStringMaxLength),
+	}
+
+	for i, tx := range sendTxMetadata.Transactions {
+		metadata.Transactions[i] = common.BridgingRequestMetadataTransaction{
+			Address: tx.Address,
+			Amount:  tx.Amount,
+		}
+	}
+
+	// cardano cli expects json metadata, as the one created by sendtx
+	metadataRaw, err := common.MarshalBridgingRequestMetadata(common.MetadataEncodingTypeJSON, version, metadata)
+	if err != nil {
+		return nil, nil, sendtx.TxReceiversDto{}, err
+	}
+
+	return metadataRaw, sendTxMetadata, sendtx.TxReceiversDto{
+		Addr:   bridgingAddress,
+		Amount: receiversSum + bridgingFee,
+	}, nil
+}
@@ -0,0 +1,237 @@
+package cardanotx
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-infrastructure/sendtx"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateVersionedBridgingTxOutput(t *testing.T) {
+	const (
+		senderAddr   = "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw"
+		receiverAddr = "addr_test1vruaegs6djpxaj9vkn8njh9uys63jdaluetqkf5r4w95zhc8sctxa"
+		multisigAddr = "addr_test1wrcwpfmznfe4ufx4sy8x3gn4yy8zqy2x4ydhczsgzfyxpngpc3mw7"
+		refundAddr   = "addr_test1qz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3jcu5d8ps7zex2k2xt3uqxgjqnnj83ws8lhrn648jjxtwq2ytjqp"
+	)
+
+	ctx := context.Background()
+	srcConfig := sendtx.ChainConfig{
+		CardanoCliBinary:     useFakeCardanoCli(t),
+		TxProvider:           &testTxProvider{},
+		MultiSigAddr:         multisigAddr,
+		MinUtxoValue:         1_000_000,
+		MinBridgingFeeAmount: 1_100_000,
+	}
+	txSender := sendtx.NewTxSender(map[string]sendtx.ChainConfig{
+		common.ChainIDStrPrime:  srcConfig,
+		common.ChainIDStrVector: {MinUtxoValue: 500_000},
+	})
+	txDto := sendtx.BridgingTxDto{
+		SrcChainID:  common.ChainIDStrPrime,
+		DstChainID:  common.ChainIDStrVector,
+		SenderAddr:  senderAddr,
+		Receivers:   []sendtx.BridgingTxReceiver{{Addr: receiverAddr, Amount: 2_000_000}},
+		BridgingFee: 1_100_000,
+	}
+
+	decode := func(t *testing.T, metadataRaw []byte) *common.BridgingRequestMetadata {
+		t.Helper()
+
+		wrapped, err := json.Marshal(map[int]json.RawMessage{0: metadataRaw})
+		require.NoError(t, err)
+
+		metadata, err := common.UnmarshalMetadata[common.BridgingRequestMetadata](common.MetadataEncodingTypeJSON, wrapped)
+		require.NoError(t, err)
+
+		return metadata
+	}
+
+	t.Run("version 2", func(t *testing.T) {
+		metadataRaw, sendTxMetadata, output, err := createVersionedBridgingTxOutput(
+			ctx, txSender, srcConfig, txDto, "", common.MetadataVersion2)
+		require.NoError(t, err)
+		require.Equal(t, sendtx.TxReceiversDto{Addr: multisigAddr, Amount: 3_100_000}, output)
+
+		metadata := decode(t, metadataRaw)
+		require.Equal(t, common.MetadataVersion2, metadata.Version)
+		require.Equal(t, common.ChainIDStrVector, metadata.DestinationChainID)
+		require.Equal(t, senderAddr, common.JoinAddrChunks(metadata.SenderAddr, common.ChainTypeCardano))
+		require.Equal(t, receiverAddr, common.JoinAddrChunks(metadata.Transactions[0].Address, common.ChainTypeCardano))
+		require.Equal(t, uint64(1_100_000), metadata.FeeAmount)
+
+		// the only difference from the metadata created by sendtx is the version key
+		expectedMetadata, err := txSender.CreateMetadata(
+			senderAddr, common.ChainIDStrPrime, common.ChainIDStrVector, txDto.Receivers, txDto.BridgingFee)
+		require.NoError(t, err)
+		require.Equal(t, expectedMetadata, sendTxMetadata)
+
+		sendTxMetadataRaw, err := sendTxMetadata.Marshal()
+		require.NoError(t, err)
+
+		metadataRaw, _, _, err = createVersionedBridgingTxOutput(
+			ctx, txSender, srcConfig, txDto, "", common.MetadataVersion1)
+		require.NoError(t, err)
+		require.JSONEq(t, string(sendTxMetadataRaw), string(metadataRaw))
+	})
+
+	t.Run("refund address", func(t *testing.T) {
+		metadataRaw, _, _, err := createVersionedBridgingTxOutput(
+			ctx, txSender, srcConfig, txDto, refundAddr, common.MetadataVersion2)
+		require.NoError(t, err)
+
+		metadata := decode(t, metadataRaw)
+		require.Len(t, metadata.RefundAddr, 2)
+		require.Equal(t, refundAddr, common.JoinAddrChunks(metadata.RefundAddr, common.ChainTypeCardano))
+
+		_, _, _, err = createVersionedBridgingTxOutput(
+			ctx, txSender, srcConfig, txDto, refundAddr, common.MetadataVersion1)
+		require.ErrorIs(t, err, common.ErrRefundAddrNotSupported)
+	})
+
+	t.Run("output below min utxo is topped up from the fee", func(t *testing.T) {
+		dto := txDto
+		dto.Receivers = nil
+		dto.BridgingAddress = "addr_test1_custom"
+
+		metadataRaw, _, output, err := createVersionedBridgingTxOutput(
+			ctx, txSender, srcConfig, dto, "", common.MetadataVersion2)
+		require.NoError(t, err)
+		require.Equal(t, sendtx.TxReceiversDto{Addr: "addr_test1_custom", Amount: 2_100_000}, output)
+		require.Equal(t, uint64(2_100_000), decode(t, metadataRaw).FeeAmount)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		dto := txDto
+		dto.BridgingFee = 1_000_000
+
+		_, _, _, err := createVersionedBridgingTxOutput(ctx, txSender, srcConfig, dto, "", common.MetadataVersion2)
+		require.ErrorContains(t, err, "bridging fee is less than")
+
+		_, _, _, err = createVersionedBridgingTxOutput(
+			ctx, txSender, srcConfig, txDto, "", common.MetadataVersion(3))
+		require.ErrorIs(t, err, common.ErrUnsupportedMetadataVersion)
+	})
+}
+
+func TestCreateVersionedBridgingTx(t *testing.T) {
+	const (
+		senderAddr   = "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw"
+		receiverAddr = "addr_test1vruaegs6djpxaj9vkn8njh9uys63jdaluetqkf5r4w95zhc8sctxa"
+		multisigAddr = "addr_test1wrsuz7s9nyqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqvd9vej"
+		refundAddr   = "addr_test1qz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3jcu5d8ps7zex2k2xt3uqxgjqnnj83ws8lhrn648jjxtwq2ytjqp"
+	)
+
+	ctx := context.Background()
+	srcConfig := sendtx.ChainConfig{
+		CardanoCliBinary: useFakeCardanoCli(t),
+		TxProvider: &testTxProvider{utxos: []wallet.Utxo{
+			{Hash: "3a7bc8a0ae4e9bfa4a4d4fe1e1ad6e2a4b6bc8f8e35ad34ff5a3e2ab6ea3a4b1", Amount: 20_000_000},
+			{Hash: "4c5a2f1c8b9e8c45a1d3e5c9a2f1e0b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1", Index: 1, Amount: 3_000_000},
+		}},
+		MultiSigAddr:         multisigAddr,
+		TestNetMagic:         2,
+		MinUtxoValue:         1_000_000,
+		MinBridgingFeeAmount: 1_100_000,
+	}
+	txSender := sendtx.NewTxSender(map[string]sendtx.ChainConfig{
+		common.ChainIDStrPrime:  srcConfig,
+		common.ChainIDStrVector: {MinUtxoValue: 1_000_000},
+	})
+
+	newTxDto := func(receivers ...sendtx.BridgingTxReceiver) sendtx.BridgingTxDto {
+		return sendtx.BridgingTxDto{
+			SrcChainID:  common.ChainIDStrPrime,
+			DstChainID:  common.ChainIDStrVector,
+			SenderAddr:  senderAddr,
+			Receivers:   receivers,
+			BridgingFee: 1_100_000,
+		}
+	}
+
+	getBridgingOutput := func(t *testing.T, txInfo *sendtx.TxInfo) uint64 {
+		t.Helper()
+
+		outputs, err := GetTxOutputsForAddress(txInfo.TxRaw, txInfo.TxHash, multisigAddr)
+		require.NoError(t, err)
+		require.Len(t, outputs, 1)
+
+		return outputs[0].Amount
+	}
+
+	for _, tc := range []struct {
+		name           string
+		txDto          sendtx.BridgingTxDto
+		bridgingOutput uint64
+	}{
+		{
+			name:           "receivers",
+			txDto:          newTxDto(sendtx.BridgingTxReceiver{Addr: receiverAddr, Amount: 2_000_000}),
+			bridgingOutput: 3_100_000,
+		},
+		{
+			// output below min utxo is topped up and the top up is added to the bridging fee
+			name:           "without receivers",
+			txDto:          newTxDto(),
+			bridgingOutput: 2_100_000,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sendTxInfo, sendTxMetadata, err := txSender.CreateBridgingTx(ctx, tc.txDto)
+			require.NoError(t, err)
+
+			txInfo, metadata, err := CreateVersionedBridgingTx(
+				ctx, txSender, srcConfig, tc.txDto, "", common.MetadataVersion1)
+			require.NoError(t, err)
+
+			// version 1 tx is the same as the one created by sendtx
+			require.Equal(t, sendTxInfo, txInfo)
+			require.Equal(t, sendTxMetadata, metadata)
+			require.Equal(t, tc.bridgingOutput, getBridgingOutput(t, txInfo))
+
+			sendTxFee, err := GetTxFee(sendTxInfo.TxRaw)
+			require.NoError(t, err)
+
+			txFeeInfo, metadata, err := CalculateVersionedBridgingTxFee(
+				ctx, txSender, srcConfig, tc.txDto, "", common.MetadataVersion1)
+			require.NoError(t, err)
+			require.Equal(t, sendTxMetadata, metadata)
+
+			sendTxFeeInfo, _, err := txSender.CalculateBridgingTxFee(ctx, tc.txDto)
+			require.NoError(t, err)
+			require.Equal(t, sendTxFeeInfo, txFeeInfo)
+			require.Equal(t, sendTxFee, txFeeInfo.Fee)
+
+			// version 2 with the refund address differs only in the metadata and the fee of the bigger tx
+			txInfo, _, err = CreateVersionedBridgingTx(
+				ctx, txSender, srcConfig, tc.txDto, refundAddr, common.MetadataVersion2)
+			require.NoError(t, err)
+			require.Equal(t, tc.bridgingOutput, getBridgingOutput(t, txInfo))
+			require.Equal(t, sendTxInfo.ChosenInputs, txInfo.ChosenInputs)
+			require.Equal(t, sendTxInfo.ChangeMinUtxoAmount, txInfo.ChangeMinUtxoAmount)
+
+			fee, err := GetTxFee(txInfo.TxRaw)
+			require.NoError(t, err)
+			require.Greater(t, fee, sendTxFee)
+
+			// quoted fee is the fee of the built tx
+			txFeeInfo, metadata, err = CalculateVersionedBridgingTxFee(
+				ctx, txSender, srcConfig, tc.txDto, refundAddr, common.MetadataVersion2)
+			require.NoError(t, err)
+			require.Equal(t, sendTxMetadata, metadata)
+			require.Equal(t, fee, txFeeInfo.Fee)
+			require.Equal(t, txInfo.ChangeMinUtxoAmount, txFeeInfo.ChangeMinUtxoAmount)
+
+			decoded, err := DecodeTxMetadata(txInfo.TxRaw)
+			require.NoError(t, err)
+			require.NotNil(t, decoded.BridgingRequest)
+			require.Equal(t, common.MetadataVersion2, decoded.BridgingRequest.Version)
+			require.Equal(t, refundAddr, common.JoinAddrChunks(decoded.BridgingRequest.RefundAddr, common.ChainTypeCardano))
+			require.Equal(t, sendTxMetadata.BridgingFee, decoded.BridgingRequest.FeeAmount)
+		})
+	}
+}
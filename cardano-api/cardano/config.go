@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/telemetry"
 	cardanowallet "github.com/Ethernal-Tech/cardano-infrastructure/wallet"
 )
 
@@ -43,20 +44,28 @@ func (config CardanoChainConfig) Serialize() ([]byte, error) {
 
 func (config CardanoChainConfig) CreateTxProvider() (cardanowallet.ITxProvider, error) {
 	if config.OgmiosURL != "" {
-		return cardanowallet.NewTxProviderOgmios(config.OgmiosURL), nil
+		return telemetry.NewTxProviderWithTracing(
+			cardanowallet.NewTxProviderOgmios(config.OgmiosURL), "ogmios"), nil
 	}
 
 	if config.SocketPath != "" {
-		return cardanowallet.NewTxProviderCli(
+		txProvider, err := cardanowallet.NewTxProviderCli(
 			uint(config.NetworkMagic), config.SocketPath, cardanowallet.ResolveCardanoCliBinary(config.NetworkID))
+		if err != nil {
+			return nil, err
+		}
+
+		return telemetry.NewTxProviderWithTracing(txProvider, "cli"), nil
 	}
 
 	if config.BlockfrostURL != "" {
 		if config.UseDemeter {
-			return cardanowallet.NewTxProviderDemeter(config.BlockfrostURL, config.BlockfrostAPIKey, "", ""), nil
+			return telemetry.NewTxProviderWithTracing(
+				cardanowallet.NewTxProviderDemeter(config.BlockfrostURL, config.BlockfrostAPIKey, "", ""), "demeter"), nil
 		}
 
-		return cardanowallet.NewTxProviderBlockFrost(config.BlockfrostURL, config.BlockfrostAPIKey), nil
+		return telemetry.NewTxProviderWithTracing(
+			cardanowallet.NewTxProviderBlockFrost(config.BlockfrostURL, config.BlockfrostAPIKey), "blockfrost"), nil
 	}
 
 	return nil, errors.New("neither a blockfrost nor a ogmios nor a socket path is specified")
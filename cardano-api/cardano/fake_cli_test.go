@@ -0,0 +1,313 @@
+package cardanotx
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+// fakeCardanoCliEnv makes the test binary act as cardano-cli, so txs can be built by sendtx without the real one
+const fakeCardanoCliEnv = "CARDANO_API_FAKE_CARDANO_CLI"
+
+var testProtocolParams = []byte(`{"txFeeFixed":155381,"txFeePerByte":44,"utxoCostPerByte":4310}`)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeCardanoCliEnv) != "" {
+		if err := runFakeCardanoCli(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// useFakeCardanoCli returns the cardano-cli binary which should be used by the tx builder in the test
+func useFakeCardanoCli(t *testing.T) string {
+	t.Helper()
+
+	t.Setenv(fakeCardanoCliEnv, "1")
+
+	return os.Args[0]
+}
+
+// runFakeCardanoCli supports the commands used by sendtx. Built tx contains inputs, outputs, fee, ttl and metadata,
+// so its size and fee depend on them as they do for the real one
+func runFakeCardanoCli(args []string) error {
+	if len(args) == 1 && args[0] == "--help" {
+		fmt.Println("Latest era commands (Conway)")
+
+		return nil
+	}
+
+	if len(args) < 3 || args[1] != "transaction" {
+		return fmt.Errorf("unsupported command: %v", args)
+	}
+
+	flags := map[string][]string{}
+
+	for i := 3; i < len(args); i++ {
+		if args[i] == "--mainnet" {
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return fmt.Errorf("missing value of %s", args[i])
+		}
+
+		flags[args[i]] = append(flags[args[i]], args[i+1])
+		i++
+	}
+
+	switch args[2] {
+	case "build-raw":
+		return fakeBuildRaw(flags)
+	case "calculate-min-fee":
+		return fakeCalculateMinFee(flags)
+	case "calculate-min-required-utxo":
+		params, err := readFakeProtocolParams(flags)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Coin %d\n", params.UtxoCostPerByte*uint64(160+len(flags["--tx-out"][0])))
+
+		return nil
+	case "txid":
+		txRaw, err := readFakeTxFile(flags["--tx-body-file"][0])
+		if err != nil {
+			return err
+		}
+
+		tx, err := decodeTx(txRaw)
+		if err != nil {
+			return err
+		}
+
+		hash := blake2b.Sum256(tx[0])
+
+		fmt.Printf("{\"txhash\":\"%s\"}\n", hex.EncodeToString(hash[:]))
+
+		return nil
+	default:
+		return fmt.Errorf("unsupported command: %s", args[2])
+	}
+}
+
+func fakeBuildRaw(flags map[string][]string) error {
+	fee, err := strconv.ParseUint(flags["--fee"][0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	ttl, err := strconv.ParseUint(flags["--invalid-hereafter"][0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	outputs := make([]any, len(flags["--tx-out"]))
+
+	for i, txOut := range flags["--tx-out"] {
+		parts := strings.Split(txOut, "+")
+		if len(parts) != 2 {
+			return fmt.Errorf("unsupported tx out: %s", txOut)
+		}
+
+		addr, err := wallet.NewCardanoAddressFromString(parts[0])
+		if err != nil {
+			return err
+		}
+
+		amount, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		outputs[i] = []any{addr.GetBytes(), amount}
+	}
+
+	var auxData any
+
+	if metadataFiles := flags["--metadata-json-file"]; len(metadataFiles) > 0 {
+		metadataJSON, err := os.ReadFile(metadataFiles[0])
+		if err != nil {
+			return err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(metadataJSON))
+		decoder.UseNumber()
+
+		var metadata any
+
+		if err := decoder.Decode(&metadata); err != nil {
+			return err
+		}
+
+		metadataCbor, err := jsonToFakeMetadata(metadata)
+		if err != nil {
+			return err
+		}
+
+		auxData = cbor.Tag{Number: auxiliaryDataCborTag, Content: map[uint64]any{0: metadataCbor}}
+	}
+
+	// canonical encoding, so the same tx is always encoded the same way
+	encMode, err := cbor.CanonicalEncOptions().EncMode()
+	if err != nil {
+		return err
+	}
+
+	txRaw, err := encMode.Marshal([]any{
+		map[uint64]any{0: flags["--tx-in"], 1: outputs, 2: fee, 3: ttl},
+		map[uint64]any{},
+		true,
+		auxData,
+	})
+	if err != nil {
+		return err
+	}
+
+	txJSON, err := json.Marshal(map[string]string{
+		"type":    "Unwitnessed Tx ConwayEra",
+		"cborHex": hex.EncodeToString(txRaw),
+	})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(flags["--out-file"][0], txJSON, 0600)
+}
+
+func fakeCalculateMinFee(flags map[string][]string) error {
+	params, err := readFakeProtocolParams(flags)
+	if err != nil {
+		return err
+	}
+
+	txRaw, err := readFakeTxFile(flags["--tx-body-file"][0])
+	if err != nil {
+		return err
+	}
+
+	witnessCount, err := strconv.ParseUint(flags["--witness-count"][0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	// every vkey witness adds around 100 bytes to the signed tx
+	fmt.Printf("{\"fee\":%d}\n", params.TxFeeFixed+params.TxFeePerByte*(uint64(len(txRaw))+witnessCount*100))
+
+	return nil
+}
+
+type fakeProtocolParams struct {
+	TxFeeFixed      uint64 `json:"txFeeFixed"`
+	TxFeePerByte    uint64 `json:"txFeePerByte"`
+	UtxoCostPerByte uint64 `json:"utxoCostPerByte"`
+}
+
+func readFakeProtocolParams(flags map[string][]string) (params fakeProtocolParams, err error) {
+	bytes, err := os.ReadFile(flags["--protocol-params-file"][0])
+	if err != nil {
+		return params, err
+	}
+
+	return params, json.Unmarshal(bytes, &params)
+}
+
+func readFakeTxFile(path string) ([]byte, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var txFile struct {
+		CborHex string `json:"cborHex"`
+	}
+
+	if err := json.Unmarshal(bytes, &txFile); err != nil {
+		return nil, err
+	}
+
+	return hex.DecodeString(txFile.CborHex)
+}
+
+// jsonToFakeMetadata converts json metadata to cbor values as cardano-cli does in no schema mode:
+// numeric keys and numbers are integers, everything else is kept
+func jsonToFakeMetadata(value any) (any, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return strconv.ParseUint(v.String(), 10, 64)
+	case string:
+		return v, nil
+	case []any:
+		result := make([]any, len(v))
+
+		for i, item := range v {
+			converted, err := jsonToFakeMetadata(item)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = converted
+		}
+
+		return result, nil
+	case map[string]any:
+		result := make(map[any]any, len(v))
+
+		for key, item := range v {
+			converted, err := jsonToFakeMetadata(item)
+			if err != nil {
+				return nil, err
+			}
+
+			if numericKey, err := strconv.ParseUint(key, 10, 64); err == nil {
+				result[numericKey] = converted
+			} else {
+				result[key] = converted
+			}
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported metadata value: %T", value)
+	}
+}
+
+type testTxProvider struct {
+	utxos []wallet.Utxo
+}
+
+var _ wallet.ITxProvider = (*testTxProvider)(nil)
+
+func (p *testTxProvider) SubmitTx(context.Context, []byte) error {
+	return errors.New("not supported")
+}
+
+func (p *testTxProvider) GetTip(context.Context) (wallet.QueryTipData, error) {
+	return wallet.QueryTipData{Slot: 1_000}, nil
+}
+
+func (p *testTxProvider) GetProtocolParameters(context.Context) ([]byte, error) {
+	return testProtocolParams, nil
+}
+
+func (p *testTxProvider) GetUtxos(context.Context, string) ([]wallet.Utxo, error) {
+	return p.utxos, nil
+}
+
+func (p *testTxProvider) Dispose() {}
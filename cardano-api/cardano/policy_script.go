@@ -0,0 +1,201 @@
+package cardanotx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	policyScriptAllType    = "all"
+	policyScriptAnyType    = "any"
+	policyScriptAfterType  = "after"
+	policyScriptBeforeType = "before"
+
+	// native scripts are hashed with this prefix (language tag) prepended to their cbor
+	nativeScriptHashPrefix = byte(0)
+)
+
+var (
+	ErrPolicyScriptMismatch = errors.New("policy script does not match sender address")
+	ErrInvalidPolicyScript  = errors.New("invalid policy script")
+)
+
+// PolicyScriptWitnesses holds the key hashes which can sign for a policy script
+// and the minimal number of signatures needed to satisfy it
+type PolicyScriptWitnesses struct {
+	KeyHashes []string
+	Threshold int
+}
+
+// GetPolicyScriptHash calculates the hash (policy id) of the native policy script without using cardano-cli
+func GetPolicyScriptHash(ps wallet.PolicyScript) ([]byte, error) {
+	nativeScript, err := toNativeScript(ps)
+	if err != nil {
+		return nil, err
+	}
+
+	scriptCbor, err := cbor.Marshal(nativeScript)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher, err := blake2b.New(wallet.KeyHashSize, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher.Write([]byte{nativeScriptHashPrefix})
+	hasher.Write(scriptCbor)
+
+	return hasher.Sum(nil), nil
+}
+
+// ValidatePolicyScriptForAddress checks that the policy script hashes to the payment credential of the address
+func ValidatePolicyScriptForAddress(addr string, ps wallet.PolicyScript) error {
+	cardAddr, err := wallet.NewCardanoAddressFromString(addr)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %s: %w", addr, err)
+	}
+
+	payment := cardAddr.GetInfo().Payment
+	if payment == nil || !payment.IsScript {
+		return fmt.Errorf("%w: payment credential of %s is not a script", ErrPolicyScriptMismatch, addr)
+	}
+
+	policyID, err := GetPolicyScriptHash(ps)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(policyID, payment.Payload[:]) {
+		return fmt.Errorf("%w: policy script hash %s, address payment credential %s",
+			ErrPolicyScriptMismatch, hex.EncodeToString(policyID), hex.EncodeToString(payment.Payload[:]))
+	}
+
+	return nil
+}
+
+// GetPolicyScriptWitnesses returns all the key hashes from the policy script (sorted, without duplicates)
+// and the minimal number of signatures required to satisfy the script
+func GetPolicyScriptWitnesses(ps wallet.PolicyScript) (*PolicyScriptWitnesses, error) {
+	keyHashesMap := map[string]bool{}
+
+	threshold, err := collectWitnesses(ps, keyHashesMap)
+	if err != nil {
+		return nil, err
+	}
+
+	keyHashes := make([]string, 0, len(keyHashesMap))
+	for keyHash := range keyHashesMap {
+		keyHashes = append(keyHashes, keyHash)
+	}
+
+	sort.Strings(keyHashes)
+
+	return &PolicyScriptWitnesses{
+		KeyHashes: keyHashes,
+		Threshold: threshold,
+	}, nil
+}
+
+func collectWitnesses(ps wallet.PolicyScript, keyHashes map[string]bool) (int, error) {
+	switch ps.Type {
+	case wallet.PolicyScriptSigType:
+		keyHashes[ps.KeyHash] = true
+
+		return 1, nil
+	case policyScriptAfterType, policyScriptBeforeType:
+		return 0, nil
+	case policyScriptAllType, policyScriptAnyType, wallet.PolicyScriptAtLeastType:
+	default:
+		return 0, fmt.Errorf("%w: unknown type %s", ErrInvalidPolicyScript, ps.Type)
+	}
+
+	thresholds := make([]int, len(ps.Scripts))
+
+	for i, script := range ps.Scripts {
+		threshold, err := collectWitnesses(script, keyHashes)
+		if err != nil {
+			return 0, err
+		}
+
+		thresholds[i] = threshold
+	}
+
+	// the cheapest way to satisfy the script is to satisfy the sub scripts with the lowest thresholds
+	sort.Ints(thresholds)
+
+	required := len(thresholds)
+
+	switch ps.Type {
+	case policyScriptAnyType:
+		required = min(1, len(thresholds))
+	case wallet.PolicyScriptAtLeastType:
+		if ps.Required <= 0 || ps.Required > len(thresholds) {
+			return 0, fmt.Errorf("%w: required %d out of %d scripts",
+				ErrInvalidPolicyScript, ps.Required, len(thresholds))
+		}
+
+		required = ps.Required
+	}
+
+	sum := 0
+	for _, x := range thresholds[:required] {
+		sum += x
+	}
+
+	return sum, nil
+}
+
+// toNativeScript converts policy script to its cbor representation
+// sig: [0, keyHash], all: [1, scripts], any: [2, scripts], atLeast: [3, n, scripts], after: [4, slot], before: [5, slot]
+func toNativeScript(ps wallet.PolicyScript) ([]any, error) {
+	switch ps.Type {
+	case wallet.PolicyScriptSigType:
+		keyHash, err := hex.DecodeString(ps.KeyHash)
+		if err != nil || len(keyHash) != wallet.KeyHashSize {
+			return nil, fmt.Errorf("%w: invalid key hash %s", ErrInvalidPolicyScript, ps.KeyHash)
+		}
+
+		return []any{0, keyHash}, nil
+	case policyScriptAfterType:
+		return []any{4, ps.Slot}, nil
+	case policyScriptBeforeType:
+		return []any{5, ps.Slot}, nil
+	case policyScriptAllType, policyScriptAnyType, wallet.PolicyScriptAtLeastType:
+	default:
+		return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidPolicyScript, ps.Type)
+	}
+
+	scripts := make([]any, len(ps.Scripts))
+
+	for i, script := range ps.Scripts {
+		nativeScript, err := toNativeScript(script)
+		if err != nil {
+			return nil, err
+		}
+
+		scripts[i] = nativeScript
+	}
+
+	switch ps.Type {
+	case policyScriptAllType:
+		return []any{1, scripts}, nil
+	case policyScriptAnyType:
+		return []any{2, scripts}, nil
+	default:
+		if ps.Required <= 0 || ps.Required > len(ps.Scripts) {
+			return nil, fmt.Errorf("%w: required %d out of %d scripts",
+				ErrInvalidPolicyScript, ps.Required, len(ps.Scripts))
+		}
+
+		return []any{3, ps.Required, scripts}, nil
+	}
+}
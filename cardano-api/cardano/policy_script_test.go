@@ -0,0 +1,105 @@
+package cardanotx
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyScript(t *testing.T) {
+	keyHashes := []string{
+		"30356731c6f4d92598732163a68d9dcec7c386075d5da4f1dca5724d",
+		"794eb34ded015c701fcf7b6ec4e0476e3dc2054a8831f636361680c9",
+		"8d2f93fdc4dbe32b1cb6951a441f081d2d111cb4a4c79a69f27d00a9",
+		"9f584550989f8a6cd6ce152b1c34661a764e0237200359e0f553d7db",
+	}
+	// policy id of the script calculated by cardano-cli (transaction policyid)
+	expectedPolicyID := "b59d7c9f689fcbc2a19da2689f9fe52c5f65c3b3c56b7b7e2f08f15f"
+	ps := wallet.NewPolicyScript(keyHashes, 3)
+
+	policyID, err := GetPolicyScriptHash(*ps)
+	require.NoError(t, err)
+	require.Equal(t, expectedPolicyID, hex.EncodeToString(policyID))
+
+	addr, err := wallet.NewPolicyScriptEnterpriseAddress(wallet.TestNetNetwork, expectedPolicyID)
+	require.NoError(t, err)
+
+	t.Run("matching address", func(t *testing.T) {
+		require.NoError(t, ValidatePolicyScriptForAddress(addr.String(), *ps))
+
+		baseAddr, err := wallet.NewPolicyScriptBaseAddress(wallet.TestNetNetwork, expectedPolicyID, expectedPolicyID)
+		require.NoError(t, err)
+
+		require.NoError(t, ValidatePolicyScriptForAddress(baseAddr.String(), *ps))
+	})
+
+	t.Run("different script", func(t *testing.T) {
+		err := ValidatePolicyScriptForAddress(addr.String(), *wallet.NewPolicyScript(keyHashes, 2))
+		require.ErrorIs(t, err, ErrPolicyScriptMismatch)
+	})
+
+	t.Run("key address", func(t *testing.T) {
+		keyAddr, err := wallet.CardanoAddressInfo{
+			AddressType: wallet.EnterpriseAddress,
+			Network:     wallet.TestNetNetwork,
+			Payment:     &wallet.CardanoAddressPayload{Payload: [wallet.KeyHashSize]byte(policyID)},
+		}.ToCardanoAddress()
+		require.NoError(t, err)
+
+		err = ValidatePolicyScriptForAddress(keyAddr.String(), *ps)
+		require.ErrorIs(t, err, ErrPolicyScriptMismatch)
+	})
+
+	t.Run("invalid script", func(t *testing.T) {
+		_, err := GetPolicyScriptHash(*wallet.NewPolicyScript([]string{"1234"}, 1))
+		require.ErrorIs(t, err, ErrInvalidPolicyScript)
+
+		_, err = GetPolicyScriptHash(*wallet.NewPolicyScript(keyHashes, 5))
+		require.ErrorIs(t, err, ErrInvalidPolicyScript)
+
+		_, err = GetPolicyScriptHash(wallet.PolicyScript{Type: "unknown"})
+		require.ErrorIs(t, err, ErrInvalidPolicyScript)
+	})
+
+	t.Run("witnesses", func(t *testing.T) {
+		witnesses, err := GetPolicyScriptWitnesses(*ps)
+		require.NoError(t, err)
+		require.Equal(t, keyHashes, witnesses.KeyHashes)
+		require.Equal(t, 3, witnesses.Threshold)
+
+		adminHash := strings.Repeat("d4", wallet.KeyHashSize)
+
+		witnesses, err = GetPolicyScriptWitnesses(wallet.PolicyScript{
+			Type: "any",
+			Scripts: []wallet.PolicyScript{
+				*ps,
+				{Type: wallet.PolicyScriptSigType, KeyHash: adminHash},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, append(append([]string{}, keyHashes...), adminHash), witnesses.KeyHashes)
+		require.Equal(t, 1, witnesses.Threshold)
+
+		witnesses, err = GetPolicyScriptWitnesses(wallet.PolicyScript{
+			Type: "all",
+			Scripts: []wallet.PolicyScript{
+				*ps,
+				{Type: wallet.PolicyScriptSigType, KeyHash: adminHash},
+				{Type: "after", Slot: 100},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 4, witnesses.Threshold)
+
+		for _, required := range []int{0, 5} {
+			_, err = GetPolicyScriptWitnesses(wallet.PolicyScript{
+				Type:    "any",
+				Scripts: []wallet.PolicyScript{*wallet.NewPolicyScript(keyHashes, required)},
+			})
+			require.ErrorIs(t, err, ErrInvalidPolicyScript)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package cardanotx
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+// alonzo (and later eras) auxiliary data is a map wrapped in this cbor tag
+const auxiliaryDataCborTag = 259
+
+// GetTxHash calculates hash of the raw cbor transaction (blake2b-256 of the tx body)
+func GetTxHash(txRaw []byte) (string, error) {
+	tx, err := decodeTx(txRaw)
+	if err != nil {
+		return "", err
+	}
+
+	hash := blake2b.Sum256(tx[0])
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// GetTxMetadata extracts the metadata from the auxiliary data of the raw cbor transaction
+// and returns it cbor encoded in the same shape common.UnmarshalMetadata expects
+func GetTxMetadata(txRaw []byte) ([]byte, error) {
+	tx, err := decodeTx(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	// shelley: [body, witnesses, auxiliary data], alonzo+: [body, witnesses, is valid, auxiliary data]
+	if len(tx) < 3 {
+		return nil, fmt.Errorf("invalid tx: expected at least 3 items, got %d", len(tx))
+	}
+
+	auxDataRaw := tx[len(tx)-1]
+
+	var auxData any
+
+	if err := cbor.Unmarshal(auxDataRaw, &auxData); err != nil {
+		return nil, fmt.Errorf("failed to decode auxiliary data: %w", err)
+	}
+
+	var metadata any
+
+	switch v := auxData.(type) {
+	case nil:
+		return nil, common.ErrTxWithoutMetadata
+	case cbor.Tag:
+		// alonzo format: #6.259({0: metadata, ...})
+		auxMap, ok := v.Content.(map[any]any)
+		if v.Number != auxiliaryDataCborTag || !ok {
+			return nil, fmt.Errorf("unsupported auxiliary data tag: %d", v.Number)
+		}
+
+		metadata = auxMap[uint64(0)]
+	case []any:
+		// allegra/mary format: [metadata, scripts]
+		if len(v) == 0 {
+			return nil, common.ErrTxWithoutMetadata
+		}
+
+		metadata = v[0]
+	case map[any]any:
+		// shelley format: metadata
+		metadata = v
+	default:
+		return nil, fmt.Errorf("unsupported auxiliary data type: %T", auxData)
+	}
+
+	if metadata == nil {
+		return nil, common.ErrTxWithoutMetadata
+	}
+
+	return cbor.Marshal(map[int]any{0: metadata})
+}
+
+// DecodeTxMetadata extracts metadata from the raw cbor transaction and decodes it according to its version and type
+func DecodeTxMetadata(txRaw []byte) (*common.DecodedMetadata, error) {
+	metadataRaw, err := GetTxMetadata(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.DecodeMetadata(metadataRaw)
+}
+
+func decodeTx(txRaw []byte) ([]cbor.RawMessage, error) {
+	var tx []cbor.RawMessage
+
+	if err := cbor.Unmarshal(txRaw, &tx); err != nil {
+		return nil, fmt.Errorf("failed to decode tx: %w", err)
+	}
+
+	if len(tx) == 0 {
+		return nil, errors.New("invalid tx: empty")
+	}
+
+	return tx, nil
+}
+
+func decodeTxBody(txRaw []byte) (map[uint64]cbor.RawMessage, error) {
+	tx, err := decodeTx(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	var body map[uint64]cbor.RawMessage
+
+	if err := cbor.Unmarshal(tx[0], &body); err != nil {
+		return nil, fmt.Errorf("failed to decode tx body: %w", err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,82 @@
+package cardanotx
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestDecodeTxMetadata(t *testing.T) {
+	metadataRaw, err := common.MarshalMetadata(common.MetadataEncodingTypeCbor, common.BatchExecutedMetadata{
+		BridgingTxType: common.BridgingTxTypeBatchExecution, BatchNonceID: 3,
+	})
+	require.NoError(t, err)
+
+	var metadata map[uint64]any
+
+	require.NoError(t, cbor.Unmarshal(metadataRaw, &metadata))
+
+	body := map[uint64]any{2: uint64(200_000)}
+	bodyRaw, err := cbor.Marshal(body)
+	require.NoError(t, err)
+
+	expectedTxHash := blake2b.Sum256(bodyRaw)
+
+	for name, auxData := range map[string]any{
+		"shelley": metadata,
+		"allegra": []any{metadata, []any{}},
+		"alonzo":  cbor.Tag{Number: auxiliaryDataCborTag, Content: map[uint64]any{0: metadata}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			txRaw, err := cbor.Marshal([]any{body, map[uint64]any{}, true, auxData})
+			require.NoError(t, err)
+
+			decoded, err := DecodeTxMetadata(txRaw)
+			require.NoError(t, err)
+			require.Equal(t, common.BridgingTxTypeBatchExecution, decoded.BridgingTxType)
+			require.Equal(t, uint64(3), decoded.BatchExecuted.BatchNonceID)
+
+			txHash, err := GetTxHash(txRaw)
+			require.NoError(t, err)
+			require.Equal(t, hex.EncodeToString(expectedTxHash[:]), txHash)
+		})
+	}
+
+	txRaw, err := cbor.Marshal([]any{body, map[uint64]any{}, true, nil})
+	require.NoError(t, err)
+
+	_, err = DecodeTxMetadata(txRaw)
+	require.ErrorIs(t, err, common.ErrTxWithoutMetadata)
+
+	_, err = GetTxHash([]byte{1, 2, 3})
+	require.Error(t, err)
+}
+
+func TestGetTxRawTimeout(t *testing.T) {
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-stop:
+		case <-r.Context().Done():
+		}
+	}))
+
+	defer server.Close()
+	defer close(stop)
+
+	oldClient := txRetrieverHTTPClient
+	txRetrieverHTTPClient = &http.Client{Timeout: 50 * time.Millisecond}
+
+	defer func() { txRetrieverHTTPClient = oldClient }()
+
+	_, err := CardanoChainConfig{BlockfrostURL: server.URL}.GetTxRaw(context.Background(), "aa")
+	require.ErrorContains(t, err, "Client.Timeout exceeded")
+}
@@ -0,0 +1,80 @@
+package cardanotx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/sendtx"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+)
+
+type BridgingTxFeeReceiver struct {
+	Addr   string
+	Amount uint64
+}
+
+// BridgingTxFeeBreakdown describes where the funds debited from the sender go
+type BridgingTxFeeBreakdown struct {
+	// SourceTxFee is the network fee of the tx on the source chain
+	SourceTxFee uint64
+	// BridgingFee is the amount which goes to the fee address on the destination chain (including MinUtxoTopUp)
+	BridgingFee uint64
+	// MinUtxoTopUp is added to the bridging fee when the bridging address output is below min utxo value
+	MinUtxoTopUp uint64
+	// ChangeMinUtxoAmount is the minimal change which must stay on the sender address
+	ChangeMinUtxoAmount uint64
+	// TotalDebit is the sum of the received amounts, bridging fee and source tx fee
+	TotalDebit uint64
+	// TxSize is the size of the tx in bytes estimated from the fee
+	TxSize    uint64
+	Receivers []BridgingTxFeeReceiver
+}
+
+func NewBridgingTxFeeBreakdown(
+	txFeeInfo *sendtx.TxFeeInfo, metadata *sendtx.BridgingRequestMetadata,
+	requestedBridgingFee uint64, protocolParams []byte,
+) (*BridgingTxFeeBreakdown, error) {
+	txSize, err := EstimateTxSize(txFeeInfo.Fee, protocolParams)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BridgingTxFeeBreakdown{
+		SourceTxFee:         txFeeInfo.Fee,
+		BridgingFee:         metadata.BridgingFee,
+		ChangeMinUtxoAmount: txFeeInfo.ChangeMinUtxoAmount,
+		TotalDebit:          txFeeInfo.Fee + metadata.BridgingFee,
+		TxSize:              txSize,
+		Receivers:           make([]BridgingTxFeeReceiver, len(metadata.Transactions)),
+	}
+
+	if metadata.BridgingFee > requestedBridgingFee {
+		result.MinUtxoTopUp = metadata.BridgingFee - requestedBridgingFee
+	}
+
+	for i, tx := range metadata.Transactions {
+		result.Receivers[i] = BridgingTxFeeReceiver{
+			Addr:   strings.Join(tx.Address, ""),
+			Amount: tx.Amount,
+		}
+		result.TotalDebit += tx.Amount
+	}
+
+	return result, nil
+}
+
+// EstimateTxSize calculates tx size from the linear fee formula: fee = txFeeFixed + txFeePerByte * size
+func EstimateTxSize(fee uint64, protocolParams []byte) (uint64, error) {
+	var params wallet.ProtocolParameters
+
+	if err := json.Unmarshal(protocolParams, &params); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal protocol parameters: %w", err)
+	}
+
+	if params.TxFeePerByte == 0 || fee < params.TxFeeFixed {
+		return 0, fmt.Errorf("invalid fee %d for protocol parameters", fee)
+	}
+
+	return (fee - params.TxFeeFixed) / params.TxFeePerByte, nil
+}
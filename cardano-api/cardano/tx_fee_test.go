@@ -0,0 +1,43 @@
+package cardanotx
+
+import (
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/sendtx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBridgingTxFeeBreakdown(t *testing.T) {
+	protocolParams := []byte(`{"txFeeFixed":155381,"txFeePerByte":44}`)
+
+	breakdown, err := NewBridgingTxFeeBreakdown(
+		&sendtx.TxFeeInfo{Fee: 155381 + 44*450, ChangeMinUtxoAmount: 1_000_000},
+		&sendtx.BridgingRequestMetadata{
+			Transactions: []sendtx.BridgingRequestMetadataTransaction{
+				{Address: []string{"addr_test1vqfuetznnmngqzquslwcu0ygn2hq", "29vjlpytlpwss762vcgun5vvw"}, Amount: 2_000_000},
+				{Address: []string{"0x0000000000000000000000000000000000000001"}, Amount: 3_000_000},
+			},
+			BridgingFee: 1_100_000,
+		},
+		1_000_000, protocolParams)
+	require.NoError(t, err)
+
+	require.Equal(t, &BridgingTxFeeBreakdown{
+		SourceTxFee:         155381 + 44*450,
+		BridgingFee:         1_100_000,
+		MinUtxoTopUp:        100_000,
+		ChangeMinUtxoAmount: 1_000_000,
+		TotalDebit:          155381 + 44*450 + 1_100_000 + 5_000_000,
+		TxSize:              450,
+		Receivers: []BridgingTxFeeReceiver{
+			{Addr: "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw", Amount: 2_000_000},
+			{Addr: "0x0000000000000000000000000000000000000001", Amount: 3_000_000},
+		},
+	}, breakdown)
+
+	_, err = EstimateTxSize(100, protocolParams)
+	require.Error(t, err)
+
+	_, err = EstimateTxSize(200_000, []byte(`{"txFeeFixed":155381}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package cardanotx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	txBodyOutputsKey   = 1
+	txBodyFeeKey       = 2
+	txOutputAddressKey = 0
+	txOutputValueKey   = 1
+)
+
+// GetTxOutputsForAddress parses raw cbor transaction and returns its outputs which belong to the address as utxos.
+// These utxos are not spendable until the transaction is included in a block
+func GetTxOutputsForAddress(txRaw []byte, txHash string, addr string) ([]wallet.Utxo, error) {
+	cardAddr, err := wallet.NewCardanoAddressFromString(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s: %w", addr, err)
+	}
+
+	body, err := decodeTxBody(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []cbor.RawMessage
+
+	if err := cbor.Unmarshal(body[txBodyOutputsKey], &outputs); err != nil {
+		return nil, fmt.Errorf("failed to decode tx outputs: %w", err)
+	}
+
+	var result []wallet.Utxo
+
+	for i, output := range outputs {
+		outputAddr, value, err := decodeTxOutput(output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode tx output %d: %w", i, err)
+		}
+
+		if !bytes.Equal(outputAddr, cardAddr.GetBytes()) {
+			continue
+		}
+
+		utxo, err := decodeTxOutputValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode tx output %d value: %w", i, err)
+		}
+
+		utxo.Hash = txHash
+		utxo.Index = uint32(i) //nolint:gosec
+
+		result = append(result, utxo)
+	}
+
+	return result, nil
+}
+
+// GetTxFee parses raw cbor transaction and returns its fee
+func GetTxFee(txRaw []byte) (uint64, error) {
+	body, err := decodeTxBody(txRaw)
+	if err != nil {
+		return 0, err
+	}
+
+	var fee uint64
+
+	if err := cbor.Unmarshal(body[txBodyFeeKey], &fee); err != nil {
+		return 0, fmt.Errorf("failed to decode tx fee: %w", err)
+	}
+
+	return fee, nil
+}
+
+// decodeTxOutput returns address and value of the output
+// legacy output is [address, value, ?datum hash], post-alonzo output is {0: address, 1: value, ...}
+func decodeTxOutput(output cbor.RawMessage) (addr []byte, value cbor.RawMessage, err error) {
+	var legacyOutput []cbor.RawMessage
+
+	if err := cbor.Unmarshal(output, &legacyOutput); err == nil {
+		if len(legacyOutput) < 2 {
+			return nil, nil, errors.New("invalid legacy output")
+		}
+
+		if err := cbor.Unmarshal(legacyOutput[0], &addr); err != nil {
+			return nil, nil, err
+		}
+
+		return addr, legacyOutput[1], nil
+	}
+
+	var postAlonzoOutput map[uint64]cbor.RawMessage
+
+	if err := cbor.Unmarshal(output, &postAlonzoOutput); err != nil {
+		return nil, nil, err
+	}
+
+	if err := cbor.Unmarshal(postAlonzoOutput[txOutputAddressKey], &addr); err != nil {
+		return nil, nil, err
+	}
+
+	return addr, postAlonzoOutput[txOutputValueKey], nil
+}
+
+// decodeTxOutputValue decodes value which is either coin or [coin, {policy id: {asset name: amount}}].
+// Policy id and asset name are cbor byte strings. Asset name is kept raw, as the tx providers return it
+func decodeTxOutputValue(value cbor.RawMessage) (wallet.Utxo, error) {
+	var coin uint64
+
+	if err := cbor.Unmarshal(value, &coin); err == nil {
+		return wallet.Utxo{Amount: coin}, nil
+	}
+
+	var multiAssetValue []cbor.RawMessage
+
+	if err := cbor.Unmarshal(value, &multiAssetValue); err != nil || len(multiAssetValue) != 2 {
+		return wallet.Utxo{}, errors.New("invalid value")
+	}
+
+	var (
+		multiAsset map[cbor.ByteString]map[cbor.ByteString]uint64
+		utxo       wallet.Utxo
+	)
+
+	if err := cbor.Unmarshal(multiAssetValue[0], &utxo.Amount); err != nil {
+		return wallet.Utxo{}, err
+	}
+
+	if err := cbor.Unmarshal(multiAssetValue[1], &multiAsset); err != nil {
+		return wallet.Utxo{}, err
+	}
+
+	for policyID, assets := range multiAsset {
+		for name, amount := range assets {
+			utxo.Tokens = append(utxo.Tokens, wallet.NewTokenAmount(
+				wallet.NewToken(hex.EncodeToString(policyID.Bytes()), string(name)), amount))
+		}
+	}
+
+	// map iteration order is random
+	sort.Slice(utxo.Tokens, func(i, j int) bool {
+		return utxo.Tokens[i].TokenName() < utxo.Tokens[j].TokenName()
+	})
+
+	return utxo, nil
+}
@@ -0,0 +1,82 @@
+package cardanotx
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTxOutputsForAddress(t *testing.T) {
+	newAddr := func(b byte) *wallet.CardanoAddress {
+		addr, err := wallet.CardanoAddressInfo{
+			AddressType: wallet.EnterpriseAddress,
+			Network:     wallet.TestNetNetwork,
+			Payment:     &wallet.CardanoAddressPayload{Payload: [wallet.KeyHashSize]byte{b}},
+		}.ToCardanoAddress()
+		require.NoError(t, err)
+
+		return addr
+	}
+
+	senderAddr, receiverAddr := newAddr(1), newAddr(2)
+	policyID := []byte(strings.Repeat("p", wallet.KeyHashSize))
+	otherPolicyID := []byte(strings.Repeat("q", wallet.KeyHashSize))
+	binaryAssetName := string([]byte{0x00, 0xff, 0x10})
+
+	txRaw, err := cbor.Marshal([]any{
+		map[uint64]any{
+			0: []any{},
+			1: []any{
+				[]any{receiverAddr.GetBytes(), uint64(1_000_000)},
+				map[uint64]any{
+					0: senderAddr.GetBytes(),
+					// policy ids and asset names are byte strings as in the txs created by cardano-cli
+					1: []any{uint64(5_000_000), map[cbor.ByteString]map[cbor.ByteString]uint64{
+						cbor.ByteString(policyID):      {cbor.ByteString("token"): 10},
+						cbor.ByteString(otherPolicyID): {cbor.ByteString(binaryAssetName): 7},
+					}},
+				},
+				[]any{senderAddr.GetBytes(), uint64(2_000_000)},
+			},
+			2: uint64(200_000),
+		},
+		map[uint64]any{},
+		true,
+		nil,
+	})
+	require.NoError(t, err)
+
+	utxos, err := GetTxOutputsForAddress(txRaw, "aa", senderAddr.String())
+	require.NoError(t, err)
+	require.Equal(t, []wallet.Utxo{
+		{
+			Hash:   "aa",
+			Index:  1,
+			Amount: 5_000_000,
+			Tokens: []wallet.TokenAmount{
+				wallet.NewTokenAmount(wallet.NewToken(hex.EncodeToString(policyID), "token"), 10),
+				wallet.NewTokenAmount(wallet.NewToken(hex.EncodeToString(otherPolicyID), binaryAssetName), 7),
+			},
+		},
+		{
+			Hash:   "aa",
+			Index:  2,
+			Amount: 2_000_000,
+		},
+	}, utxos)
+
+	utxos, err = GetTxOutputsForAddress(txRaw, "aa", newAddr(3).String())
+	require.NoError(t, err)
+	require.Empty(t, utxos)
+
+	_, err = GetTxOutputsForAddress([]byte{1, 2, 3}, "aa", senderAddr.String())
+	require.Error(t, err)
+
+	fee, err := GetTxFee(txRaw)
+	require.NoError(t, err)
+	require.Equal(t, uint64(200_000), fee)
+}
@@ -0,0 +1,81 @@
+package cardanotx
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	blockfrostAuthHeaderKey = "project_id"
+	demeterAuthHeaderKey    = "dmtr-api-key"
+
+	txRetrieverTimeout = 10 * time.Second
+)
+
+var (
+	ErrTxNotFound = errors.New("transaction not found")
+
+	// txRetrieverHTTPClient has timeout so a stalled provider can not block the request forever
+	txRetrieverHTTPClient = &http.Client{Timeout: txRetrieverTimeout}
+)
+
+// GetBlockfrostAuthHeaderKey returns the name of the header which carries blockfrost api key
+func (config CardanoChainConfig) GetBlockfrostAuthHeaderKey() string {
+	if config.UseDemeter {
+		return demeterAuthHeaderKey
+	}
+
+	return blockfrostAuthHeaderKey
+}
+
+// GetTxRaw retrieves raw cbor of the confirmed transaction by its hash.
+// Only blockfrost (and demeter) providers can retrieve transactions by hash
+func (config CardanoChainConfig) GetTxRaw(ctx context.Context, txHash string) ([]byte, error) {
+	type txCborResponse struct {
+		Cbor string `json:"cbor"`
+	}
+
+	if config.BlockfrostURL == "" {
+		return nil, errors.New("retrieving tx by hash requires blockfrost provider")
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, fmt.Sprintf("%s/txs/%s/cbor", config.BlockfrostURL, txHash), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(config.GetBlockfrostAuthHeaderKey(), config.BlockfrostAPIKey)
+
+	resp, err := txRetrieverHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrTxNotFound, txHash)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http status for %s code is %d", req.URL.String(), resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response txCborResponse
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	return hex.DecodeString(response.Cbor)
+}
@@ -0,0 +1,239 @@
+package cardanotx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+)
+
+// potentialFee is the amount reserved for the fee while selecting inputs for fan-out tx
+const potentialFee = 400_000
+
+var (
+	ErrNotEnoughUtxos      = errors.New("not enough utxos")
+	ErrFanOutAmountTooHigh = errors.New("fan-out amount is too high")
+)
+
+type UtxoTxConfig struct {
+	CardanoCliBinary string
+	TxProvider       wallet.ITxProvider
+	TestNetMagic     uint
+	TTLSlotNumberInc uint64
+	MinUtxoValue     uint64
+}
+
+type UtxoTxInfo struct {
+	TxRaw        []byte
+	TxHash       string
+	Fee          uint64
+	Inputs       []wallet.TxInput
+	OutputsCount int
+}
+
+// CreateConsolidationTx builds unsigned tx which merges up to maxInputs smallest utxos
+// (with lovelace amount not greater than maxUtxoAmount, if specified) into the single output of the sender
+func CreateConsolidationTx(
+	ctx context.Context, config UtxoTxConfig, senderAddr string, policyScript *wallet.PolicyScript,
+	utxos []wallet.Utxo, maxUtxoAmount uint64, maxInputs int,
+) (*UtxoTxInfo, error) {
+	inputs := SelectConsolidationUtxos(utxos, maxUtxoAmount, maxInputs)
+	if len(inputs) < 2 {
+		return nil, fmt.Errorf("%w: found %d utxos to consolidate", ErrNotEnoughUtxos, len(inputs))
+	}
+
+	return createUtxoTx(ctx, config, senderAddr, policyScript, inputs, nil)
+}
+
+// CreateFanOutTx builds unsigned tx which splits the largest utxos of the sender
+// into outputsCount outputs of outputAmount lovelace each. Remaining funds (and tokens) go to the change output
+func CreateFanOutTx(
+	ctx context.Context, config UtxoTxConfig, senderAddr string, policyScript *wallet.PolicyScript,
+	utxos []wallet.Utxo, outputAmount uint64, outputsCount int, maxInputs int,
+) (*UtxoTxInfo, error) {
+	if outputAmount < config.MinUtxoValue {
+		return nil, fmt.Errorf("output amount %d is lower than minimal utxo value %d", outputAmount, config.MinUtxoValue)
+	}
+
+	desiredAmount, err := GetFanOutDesiredAmount(outputAmount, outputsCount, config.MinUtxoValue)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make([]wallet.TxOutput, outputsCount)
+	for i := range outputs {
+		outputs[i] = wallet.TxOutput{
+			Addr:   senderAddr,
+			Amount: outputAmount,
+		}
+	}
+
+	inputs, err := SelectFanOutUtxos(utxos, desiredAmount, maxInputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return createUtxoTx(ctx, config, senderAddr, policyScript, inputs, outputs)
+}
+
+// GetFanOutDesiredAmount returns the amount which inputs of the fan-out tx should have:
+// all the outputs, potential fee and min utxo value of the change. Error is returned if the amount overflows
+func GetFanOutDesiredAmount(outputAmount uint64, outputsCount int, minUtxoValue uint64) (uint64, error) {
+	if outputsCount < 1 {
+		return 0, fmt.Errorf("invalid outputs count %d", outputsCount)
+	}
+
+	hi, amount := bits.Mul64(outputAmount, uint64(outputsCount)) //nolint:gosec
+	amount, carry1 := bits.Add64(amount, potentialFee, 0)
+	amount, carry2 := bits.Add64(amount, minUtxoValue, 0)
+
+	if hi != 0 || carry1 != 0 || carry2 != 0 {
+		return 0, fmt.Errorf("%w: %d outputs of %d", ErrFanOutAmountTooHigh, outputsCount, outputAmount)
+	}
+
+	return amount, nil
+}
+
+// SelectConsolidationUtxos returns up to maxInputs smallest utxos with amount not greater than maxUtxoAmount
+// (0 means without limit)
+func SelectConsolidationUtxos(utxos []wallet.Utxo, maxUtxoAmount uint64, maxInputs int) []wallet.Utxo {
+	result := make([]wallet.Utxo, 0, len(utxos))
+
+	for _, utxo := range utxos {
+		if maxUtxoAmount == 0 || utxo.Amount <= maxUtxoAmount {
+			result = append(result, utxo)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Amount < result[j].Amount
+	})
+
+	if len(result) > maxInputs {
+		result = result[:maxInputs]
+	}
+
+	return result
+}
+
+// SelectFanOutUtxos returns the smallest number of the largest utxos whose sum is at least desiredAmount
+func SelectFanOutUtxos(utxos []wallet.Utxo, desiredAmount uint64, maxInputs int) ([]wallet.Utxo, error) {
+	sorted := append([]wallet.Utxo(nil), utxos...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Amount > sorted[j].Amount
+	})
+
+	sum := uint64(0)
+
+	for i, utxo := range sorted {
+		if i == maxInputs {
+			break
+		}
+
+		sum += utxo.Amount
+		if sum >= desiredAmount {
+			return sorted[:i+1], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: required %d, available %d", ErrNotEnoughUtxos, desiredAmount, sum)
+}
+
+// createUtxoTx builds tx which spends all the inputs into the outputs and the change output of the sender
+func createUtxoTx(
+	ctx context.Context, config UtxoTxConfig, senderAddr string, policyScript *wallet.PolicyScript,
+	utxos []wallet.Utxo, outputs []wallet.TxOutput,
+) (*UtxoTxInfo, error) {
+	txBuilder, err := wallet.NewTxBuilder(config.CardanoCliBinary)
+	if err != nil {
+		return nil, err
+	}
+
+	defer txBuilder.Dispose()
+
+	err = txBuilder.SetProtocolParametersAndTTL(ctx, config.TxProvider, config.TTLSlotNumberInc)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]wallet.TxInput, len(utxos))
+	for i, utxo := range utxos {
+		inputs[i] = wallet.TxInput{
+			Hash:  utxo.Hash,
+			Index: utxo.Index,
+		}
+	}
+
+	inputsSum := wallet.GetUtxosSum(utxos)
+	outputsSum := wallet.GetOutputsSum(outputs)
+	changeLovelace := inputsSum[wallet.AdaTokenName]
+
+	if changeLovelace < outputsSum[wallet.AdaTokenName] {
+		return nil, fmt.Errorf("%w: inputs %d, outputs %d",
+			ErrNotEnoughUtxos, changeLovelace, outputsSum[wallet.AdaTokenName])
+	}
+
+	changeLovelace -= outputsSum[wallet.AdaTokenName]
+
+	changeTokens, err := wallet.GetTokensFromSumMap(inputsSum)
+	if err != nil {
+		return nil, err
+	}
+
+	changeOutput := wallet.TxOutput{
+		Addr:   senderAddr,
+		Amount: changeLovelace,
+		Tokens: changeTokens,
+	}
+
+	changeMinUtxo, err := txBuilder.CalculateMinUtxo(changeOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	changeMinUtxo = max(changeMinUtxo, config.MinUtxoValue)
+
+	txBuilder.SetTestNetMagic(config.TestNetMagic)
+
+	if policyScript != nil {
+		txBuilder.AddInputsWithScript(policyScript, inputs...)
+	} else {
+		txBuilder.AddInputs(inputs...)
+	}
+
+	txBuilder.AddOutputs(outputs...).AddOutputs(changeOutput)
+
+	witnessCount := 1
+	if policyScript != nil {
+		witnessCount = policyScript.GetCount()
+	}
+
+	fee, err := txBuilder.CalculateFee(witnessCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if changeLovelace < fee || changeLovelace-fee < changeMinUtxo {
+		return nil, fmt.Errorf("%w: change %d is not enough for fee %d and minimal utxo %d",
+			ErrNotEnoughUtxos, changeLovelace, fee, changeMinUtxo)
+	}
+
+	txBuilder.SetFee(fee).UpdateOutputAmount(-1, changeLovelace-fee)
+
+	txRaw, txHash, err := txBuilder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &UtxoTxInfo{
+		TxRaw:        txRaw,
+		TxHash:       txHash,
+		Fee:          fee,
+		Inputs:       inputs,
+		OutputsCount: len(outputs) + 1,
+	}, nil
+}
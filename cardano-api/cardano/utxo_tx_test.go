@@ -0,0 +1,68 @@
+package cardanotx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectUtxos(t *testing.T) {
+	utxos := []wallet.Utxo{
+		{Hash: "0x1", Amount: 5_000_000},
+		{Hash: "0x2", Amount: 1_000_000},
+		{Hash: "0x3", Amount: 100_000_000},
+		{Hash: "0x4", Amount: 2_000_000},
+		{Hash: "0x5", Amount: 1_500_000},
+	}
+
+	t.Run("consolidation", func(t *testing.T) {
+		require.Equal(t,
+			[]wallet.Utxo{utxos[1], utxos[4], utxos[3]},
+			SelectConsolidationUtxos(utxos, 2_000_000, 10))
+		require.Equal(t,
+			[]wallet.Utxo{utxos[1], utxos[4]},
+			SelectConsolidationUtxos(utxos, 0, 2))
+		require.Empty(t, SelectConsolidationUtxos(utxos, 999_999, 10))
+	})
+
+	t.Run("fan-out", func(t *testing.T) {
+		selected, err := SelectFanOutUtxos(utxos, 50_000_000, 10)
+		require.NoError(t, err)
+		require.Equal(t, []wallet.Utxo{utxos[2]}, selected)
+
+		selected, err = SelectFanOutUtxos(utxos, 104_000_000, 10)
+		require.NoError(t, err)
+		require.Equal(t, []wallet.Utxo{utxos[2], utxos[0]}, selected)
+
+		_, err = SelectFanOutUtxos(utxos, 106_000_000, 2)
+		require.ErrorIs(t, err, ErrNotEnoughUtxos)
+
+		_, err = SelectFanOutUtxos(utxos, 200_000_000, 10)
+		require.ErrorIs(t, err, ErrNotEnoughUtxos)
+	})
+}
+
+func TestGetFanOutDesiredAmount(t *testing.T) {
+	amount, err := GetFanOutDesiredAmount(2_000_000, 10, 1_000_000)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20_000_000+potentialFee+1_000_000), amount)
+
+	for _, tc := range []struct {
+		outputAmount uint64
+		outputsCount int
+		minUtxoValue uint64
+	}{
+		{math.MaxUint64/10 + 1, 10, 1_000_000},
+		{math.MaxUint64 / 2, 2, 1_000_000},
+		{math.MaxUint64 - potentialFee, 1, 1_000_000},
+		{1_000_000, 1, math.MaxUint64},
+	} {
+		_, err := GetFanOutDesiredAmount(tc.outputAmount, tc.outputsCount, tc.minUtxoValue)
+		require.ErrorIs(t, err, ErrFanOutAmountTooHigh)
+	}
+
+	_, err = GetFanOutDesiredAmount(1_000_000, 0, 1_000_000)
+	require.Error(t, err)
+}
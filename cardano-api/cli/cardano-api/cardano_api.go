@@ -6,16 +6,27 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/Ethernal-Tech/cardano-api/api"
 	"github.com/Ethernal-Tech/cardano-api/api/controllers"
+	eventstream "github.com/Ethernal-Tech/cardano-api/api/event_stream"
+	feequote "github.com/Ethernal-Tech/cardano-api/api/fee_quote"
+	utxotransformer "github.com/Ethernal-Tech/cardano-api/api/utxo_transformer"
 	"github.com/Ethernal-Tech/cardano-api/common"
 	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-api/telemetry"
 	validatorchange "github.com/Ethernal-Tech/cardano-api/validator-change"
+	"github.com/Ethernal-Tech/cardano-api/webhook"
 	loggerInfra "github.com/Ethernal-Tech/cardano-infrastructure/logger"
 	"github.com/spf13/cobra"
 )
 
+const (
+	telemetryShutdownTimeout = 5 * time.Second
+	settingsRefreshInterval  = time.Minute
+)
+
 var caParams = &cardanoAPIParams{}
 
 func GetCardanoAPICommand() *cobra.Command {
@@ -46,6 +57,12 @@ func runCommand(cmd *cobra.Command, _ []string) {
 		return
 	}
 
+	if err := config.ValidateFeeQuoteTimeout(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
 	logger, err := loggerInfra.NewLogger(config.Settings.Logger)
 	if err != nil {
 		outputter.SetError(err)
@@ -56,6 +73,22 @@ func runCommand(cmd *cobra.Command, _ []string) {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	defer cancelCtx()
 
+	shutdownTelemetry, err := telemetry.Init(ctx, config.Telemetry)
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	defer func() {
+		shutdownCtx, cancelShutdownCtx := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+		defer cancelShutdownCtx()
+
+		if err := shutdownTelemetry(shutdownCtx); err != nil {
+			logger.Error("error while telemetry shutdown", "err", err)
+		}
+	}()
+
 	validatorChangeTracker := core.NewValidatorChangeTracker()
 
 	err = config.FillOut(ctx, logger)
@@ -72,10 +105,58 @@ func runCommand(cmd *cobra.Command, _ []string) {
 		}
 	}()
 
+	usedUtxoCacher := utxotransformer.NewUsedUtxoCacher(
+		config.UtxoCacheTimeout,
+		utxotransformer.WithMaxAddresses(config.UtxoCacheMaxAddresses),
+		utxotransformer.WithMaxInputs(config.UtxoCacheMaxInputs))
+
+	reservationStore, err := utxotransformer.NewReservationStore(
+		ctx, config.UtxoReservationStore, config.UtxoCacheTimeout)
+	if err != nil {
+		logger.Error("utxo reservation store creation failed", "err", err)
+		outputter.SetError(err)
+
+		return
+	}
+
+	if reservationStore != nil {
+		defer func() {
+			if err := reservationStore.Close(); err != nil {
+				logger.Error("error while closing utxo reservation store", "err", err)
+			}
+		}()
+	}
+
+	var feeQuoteStore *feequote.FeeQuoteStore
+	if config.FeeQuoteTimeout > 0 {
+		feeQuoteStore = feequote.NewFeeQuoteStore([]byte(config.FeeQuoteSecret), config.FeeQuoteTimeout)
+	}
+
+	var webhookService *webhook.Service
+
+	if config.Webhooks.IsEnabled() {
+		webhookService, err = webhook.NewService(config.Webhooks, config.GetOracleClient(), logger.Named("webhook"))
+		if err != nil {
+			logger.Error("webhook service creation failed", "err", err)
+			outputter.SetError(err)
+
+			return
+		}
+	}
+
+	eventBroker := eventstream.NewBroker(config.APIConfig.MaxEventStreamsPerKey)
+	config.SetChangeNotifier(eventBroker)
+
 	apiControllers := []core.APIController{
 		controllers.NewCardanoTxController(
-			config, logger.Named("cardano_tx_controller"),
-			validatorChangeTracker),
+			config, usedUtxoCacher, reservationStore, feeQuoteStore, logger.Named("cardano_tx_controller"),
+			validatorChangeTracker, webhookService, eventBroker),
+		controllers.NewUtxoCacheController(usedUtxoCacher, reservationStore, logger.Named("utxo_cache_controller")),
+	}
+
+	if webhookService != nil {
+		apiControllers = append(apiControllers,
+			controllers.NewWebhookController(webhookService, logger.Named("webhook_controller")))
 	}
 
 	apiObj, err := api.NewAPI(config.APIConfig, apiControllers, logger.Named("api"))
@@ -86,12 +167,32 @@ func runCommand(cmd *cobra.Command, _ []string) {
 		return
 	}
 
-	validatorChange := validatorchange.NewValidatorChange(ctx, logger, config, validatorChangeTracker)
+	validatorChangeNotifiers := []common.ValidatorChangeNotifier{eventBroker}
+	if webhookService != nil {
+		validatorChangeNotifiers = append(validatorChangeNotifiers, webhookService)
+	}
+
+	validatorChange := validatorchange.NewValidatorChange(
+		ctx, logger, config, validatorChangeTracker, validatorChangeNotifiers...)
 
 	go apiObj.Start(ctx)
 
+	go usedUtxoCacher.Start(ctx)
+
 	go validatorChange.Start(ctx)
 
+	go config.StartSettingsRefresh(ctx, logger, settingsRefreshInterval)
+
+	if webhookService != nil {
+		go webhookService.Start(ctx)
+
+		defer func() {
+			if err := webhookService.Flush(); err != nil {
+				logger.Error("error while saving webhook store", "err", err)
+			}
+		}()
+	}
+
 	defer func() {
 		err := apiObj.Dispose()
 		if err != nil {
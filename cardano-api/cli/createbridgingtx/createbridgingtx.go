@@ -0,0 +1,47 @@
+package clicreatebridgingtx
+
+import (
+	"fmt"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/spf13/cobra"
+)
+
+var paramsData = &createBridgingTxParams{}
+
+func GetCreateBridgingTxCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "create-bridging-tx",
+		Short:   "creates (and optionally signs and submits) bridging tx without running the api",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	paramsData.setFlags(cmd)
+
+	return cmd
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return paramsData.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := common.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	defer func() {
+		if r := recover(); r != nil {
+			outputter.SetError(fmt.Errorf("%v", r))
+		}
+	}()
+
+	result, err := paramsData.Execute()
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(result)
+}
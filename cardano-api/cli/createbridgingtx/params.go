@@ -0,0 +1,339 @@
+package clicreatebridgingtx
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/api/controllers"
+	"github.com/Ethernal-Tech/cardano-api/api/model/request"
+	utxotransformer "github.com/Ethernal-Tech/cardano-api/api/utxo_transformer"
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	validatorchange "github.com/Ethernal-Tech/cardano-api/validator-change"
+	loggerInfra "github.com/Ethernal-Tech/cardano-infrastructure/logger"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/spf13/cobra"
+)
+
+const (
+	configFlag             = "config"
+	requestFileFlag        = "request-file"
+	senderAddrFlag         = "sender-addr"
+	refundAddrFlag         = "refund-addr"
+	sourceChainIDFlag      = "source-chain"
+	destinationChainIDFlag = "destination-chain"
+	receiverFlag           = "receiver"
+	bridgingFeeFlag        = "bridging-fee"
+	useFallbackFlag        = "use-fallback"
+	signingKeyFlag         = "signing-key"
+	submitFlag             = "submit"
+	timeoutFlag            = "timeout"
+
+	configFlagDesc             = "path to config json file"
+	requestFileFlagDesc        = "path to json file with the CreateBridgingTx request body (replaces other request flags)"
+	senderAddrFlagDesc         = "sender address"
+	refundAddrFlagDesc         = "address on the source chain which receives the funds if the request is refunded"
+	sourceChainIDFlagDesc      = "source chain id"
+	destinationChainIDFlagDesc = "destination chain id"
+	receiverFlagDesc           = "receiver in format <address>:<amount>"
+	bridgingFeeFlagDesc        = "bridging fee (default is the minimal fee for the destination chain)"
+	useFallbackFlagDesc        = "send to the fallback bridging address"
+	signingKeyFlagDesc         = "path to the signing key file used to sign the tx"
+	submitFlagDesc             = "submit the signed tx"
+	timeoutFlagDesc            = "timeout for fetching settings, building and submitting the tx"
+
+	defaultTimeout = 2 * time.Minute
+)
+
+type createBridgingTxParams struct {
+	config             string
+	requestFile        string
+	senderAddr         string
+	refundAddr         string
+	sourceChainID      string
+	destinationChainID string
+	receivers          []string
+	bridgingFee        uint64
+	useFallback        bool
+	signingKey         string
+	submit             bool
+	timeout            time.Duration
+}
+
+func (p *createBridgingTxParams) validateFlags() error {
+	if p.config == "" {
+		return fmt.Errorf("specify: %s", configFlag)
+	}
+
+	if p.requestFile == "" {
+		if p.senderAddr == "" {
+			return fmt.Errorf("specify: %s", senderAddrFlag)
+		}
+
+		if p.sourceChainID == "" || p.destinationChainID == "" {
+			return fmt.Errorf("specify: %s and %s", sourceChainIDFlag, destinationChainIDFlag)
+		}
+
+		if len(p.receivers) == 0 {
+			return fmt.Errorf("specify at least one %s", receiverFlag)
+		}
+
+		if _, err := parseReceivers(p.receivers); err != nil {
+			return err
+		}
+	}
+
+	if p.submit && p.signingKey == "" {
+		return fmt.Errorf("%s requires %s", submitFlag, signingKeyFlag)
+	}
+
+	if p.timeout <= 0 {
+		return fmt.Errorf("invalid %s: %s", timeoutFlag, p.timeout)
+	}
+
+	return nil
+}
+
+func (p *createBridgingTxParams) setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&p.config,
+		configFlag,
+		"",
+		configFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.requestFile,
+		requestFileFlag,
+		"",
+		requestFileFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.senderAddr,
+		senderAddrFlag,
+		"",
+		senderAddrFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.refundAddr,
+		refundAddrFlag,
+		"",
+		refundAddrFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.sourceChainID,
+		sourceChainIDFlag,
+		"",
+		sourceChainIDFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.destinationChainID,
+		destinationChainIDFlag,
+		"",
+		destinationChainIDFlagDesc,
+	)
+	cmd.Flags().StringArrayVar(
+		&p.receivers,
+		receiverFlag,
+		nil,
+		receiverFlagDesc,
+	)
+	cmd.Flags().Uint64Var(
+		&p.bridgingFee,
+		bridgingFeeFlag,
+		0,
+		bridgingFeeFlagDesc,
+	)
+	cmd.Flags().BoolVar(
+		&p.useFallback,
+		useFallbackFlag,
+		false,
+		useFallbackFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.signingKey,
+		signingKeyFlag,
+		"",
+		signingKeyFlagDesc,
+	)
+	cmd.Flags().BoolVar(
+		&p.submit,
+		submitFlag,
+		false,
+		submitFlagDesc,
+	)
+	cmd.Flags().DurationVar(
+		&p.timeout,
+		timeoutFlag,
+		defaultTimeout,
+		timeoutFlagDesc,
+	)
+
+	cmd.MarkFlagsMutuallyExclusive(requestFileFlag, senderAddrFlag)
+	cmd.MarkFlagsMutuallyExclusive(requestFileFlag, receiverFlag)
+}
+
+func (p *createBridgingTxParams) Execute() (common.ICommandResult, error) {
+	requestBody, err := p.getRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := common.LoadConfig[core.AppConfig](p.config, "")
+	if err != nil {
+		return nil, err
+	}
+
+	logger, err := loggerInfra.NewLogger(config.Settings.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), p.timeout)
+	defer cancelCtx()
+
+	if err := config.FillOut(ctx, logger); err != nil {
+		return nil, fmt.Errorf("failed to fetch settings: %w", err)
+	}
+
+	validatorChangeTracker := core.NewValidatorChangeTracker()
+
+	err = validatorchange.NewValidatorChange(ctx, logger, config, validatorChangeTracker).SyncStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch validator change status: %w", err)
+	}
+
+	controller := controllers.NewCardanoTxController(
+		config, utxotransformer.NewUsedUtxoCacher(config.UtxoCacheTimeout), nil, nil,
+		logger.Named("cardano_tx_controller"), validatorChangeTracker, nil, nil)
+
+	txInfo, err := controller.CreateBridgingTx(ctx, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CmdResult{
+		TxHash:      txInfo.TxHash,
+		TxRaw:       hex.EncodeToString(txInfo.TxRaw),
+		BridgingFee: requestBody.BridgingFee,
+	}
+
+	if p.signingKey == "" {
+		return result, nil
+	}
+
+	cardanoConfig, _ := config.GetChainConfig(requestBody.SourceChainID)
+
+	txSigned, err := signTx(p.signingKey, cardanoConfig, txInfo.TxRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TxRaw = hex.EncodeToString(txSigned)
+	result.Signed = true
+
+	if !p.submit {
+		return result, nil
+	}
+
+	if err := submitTx(ctx, cardanoConfig, txSigned); err != nil {
+		return nil, err
+	}
+
+	result.Submitted = true
+
+	return result, nil
+}
+
+func (p *createBridgingTxParams) getRequest() (*request.CreateBridgingTxRequest, error) {
+	if p.requestFile != "" {
+		return common.LoadJSON[request.CreateBridgingTxRequest](p.requestFile)
+	}
+
+	receivers, err := parseReceivers(p.receivers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &request.CreateBridgingTxRequest{
+		SenderAddr:         p.senderAddr,
+		RefundAddr:         p.refundAddr,
+		SourceChainID:      p.sourceChainID,
+		DestinationChainID: p.destinationChainID,
+		Transactions:       receivers,
+		BridgingFee:        p.bridgingFee,
+		UseFallback:        p.useFallback,
+	}, nil
+}
+
+func parseReceivers(values []string) ([]request.CreateBridgingTxTransactionRequest, error) {
+	result := make([]request.CreateBridgingTxTransactionRequest, len(values))
+
+	for i, value := range values {
+		idx := strings.LastIndex(value, ":")
+		if idx <= 0 || idx == len(value)-1 {
+			return nil, fmt.Errorf("invalid %s: %s", receiverFlag, value)
+		}
+
+		amount, err := strconv.ParseUint(value[idx+1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s amount: %s", receiverFlag, value)
+		}
+
+		result[i] = request.CreateBridgingTxTransactionRequest{
+			Addr:   value[:idx],
+			Amount: amount,
+		}
+	}
+
+	return result, nil
+}
+
+func signTx(signingKeyPath string, cardanoConfig *core.CardanoChainConfig, txRaw []byte) ([]byte, error) {
+	if cardanoConfig == nil {
+		return nil, errors.New("source chain is not a cardano chain")
+	}
+
+	key, err := wallet.NewKey(signingKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing key: %w", err)
+	}
+
+	keyBytes, err := key.GetKeyBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signing key: %w", err)
+	}
+
+	txBuilder, err := wallet.NewTxBuilder(wallet.ResolveCardanoCliBinary(cardanoConfig.NetworkID))
+	if err != nil {
+		return nil, err
+	}
+
+	defer txBuilder.Dispose()
+
+	txSigned, err := txBuilder.SignTx(txRaw, []wallet.ITxSigner{wallet.NewWallet(keyBytes, nil)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
+	}
+
+	return txSigned, nil
+}
+
+func submitTx(ctx context.Context, cardanoConfig *core.CardanoChainConfig, txSigned []byte) error {
+	txProvider, err := cardanoConfig.ChainSpecific.CreateTxProvider()
+	if err != nil {
+		return err
+	}
+
+	defer txProvider.Dispose()
+
+	if err := txProvider.SubmitTx(ctx, txSigned); err != nil {
+		return fmt.Errorf("failed to submit tx: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package clicreatebridgingtx
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+)
+
+type CmdResult struct {
+	TxHash      string `json:"txHash"`
+	TxRaw       string `json:"txRaw"`
+	BridgingFee uint64 `json:"bridgingFee"`
+	Signed      bool   `json:"signed"`
+	Submitted   bool   `json:"submitted"`
+}
+
+func (r CmdResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("[BRIDGING TX]\n")
+	buffer.WriteString(common.FormatKV([]string{
+		fmt.Sprintf("Tx hash|%s", r.TxHash),
+		fmt.Sprintf("Bridging fee|%d", r.BridgingFee),
+		fmt.Sprintf("Signed|%t", r.Signed),
+		fmt.Sprintf("Submitted|%t", r.Submitted),
+		fmt.Sprintf("Tx raw|%s", r.TxRaw),
+	}))
+
+	return buffer.String()
+}
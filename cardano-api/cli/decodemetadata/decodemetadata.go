@@ -0,0 +1,47 @@
+package clidecodemetadata
+
+import (
+	"fmt"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/spf13/cobra"
+)
+
+var paramsData = &decodeMetadataParams{}
+
+func GetDecodeMetadataCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "decode-metadata",
+		Short:   "decodes bridging metadata of a transaction",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	paramsData.setFlags(cmd)
+
+	return cmd
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return paramsData.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := common.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	defer func() {
+		if r := recover(); r != nil {
+			outputter.SetError(fmt.Errorf("%v", r))
+		}
+	}()
+
+	result, err := paramsData.Execute(cmd.Context())
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(result)
+}
@@ -0,0 +1,135 @@
+package clidecodemetadata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	cardanotx "github.com/Ethernal-Tech/cardano-api/cardano"
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	txRawFlag   = "tx-raw"
+	txHashFlag  = "tx-hash"
+	chainIDFlag = "chain"
+	configFlag  = "config"
+	timeoutFlag = "timeout"
+
+	txRawFlagDesc   = "raw transaction cbor (hex encoded)"
+	txHashFlagDesc  = "hash of the transaction to retrieve from the chain provider (requires blockfrost)"
+	chainIDFlagDesc = "chain id of the transaction, used with tx hash"
+	configFlagDesc  = "path to config json file, used with tx hash"
+	timeoutFlagDesc = "timeout for retrieving the transaction"
+
+	defaultTimeout = 30 * time.Second
+)
+
+type decodeMetadataParams struct {
+	txRaw   string
+	txHash  string
+	chainID string
+	config  string
+	timeout time.Duration
+}
+
+func (p *decodeMetadataParams) validateFlags() error {
+	if (p.txRaw == "") == (p.txHash == "") {
+		return fmt.Errorf("specify either %s or %s", txRawFlag, txHashFlag)
+	}
+
+	if p.txHash != "" && (p.chainID == "" || p.config == "") {
+		return fmt.Errorf("%s requires %s and %s", txHashFlag, chainIDFlag, configFlag)
+	}
+
+	return nil
+}
+
+func (p *decodeMetadataParams) setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&p.txRaw,
+		txRawFlag,
+		"",
+		txRawFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.txHash,
+		txHashFlag,
+		"",
+		txHashFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.chainID,
+		chainIDFlag,
+		"",
+		chainIDFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.config,
+		configFlag,
+		"",
+		configFlagDesc,
+	)
+	cmd.Flags().DurationVar(
+		&p.timeout,
+		timeoutFlag,
+		defaultTimeout,
+		timeoutFlagDesc,
+	)
+
+	cmd.MarkFlagsMutuallyExclusive(txRawFlag, txHashFlag)
+}
+
+func (p *decodeMetadataParams) Execute(ctx context.Context) (common.ICommandResult, error) {
+	txRaw, err := p.getTxRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash, err := cardanotx.GetTxHash(txRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := cardanotx.DecodeTxMetadata(txRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode metadata of tx %s: %w", txHash, err)
+	}
+
+	return &CmdResult{
+		DecodedTxResponse: *response.NewDecodedTxResponse(txHash, metadata),
+	}, nil
+}
+
+func (p *decodeMetadataParams) getTxRaw(ctx context.Context) ([]byte, error) {
+	if p.txRaw != "" {
+		txRaw, err := common.DecodeHex(p.txRaw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", txRawFlag, err)
+		}
+
+		return txRaw, nil
+	}
+
+	config, err := common.LoadConfig[core.AppConfig](p.config, "")
+	if err != nil {
+		return nil, err
+	}
+
+	chainConfig, exists := config.CardanoChains[p.chainID]
+	if !exists || chainConfig.ChainSpecific == nil {
+		return nil, fmt.Errorf("cardano chain not found in config: %s", p.chainID)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, cancelFn := context.WithTimeout(ctx, p.timeout)
+	defer cancelFn()
+
+	return chainConfig.ChainSpecific.GetTxRaw(ctx, p.txHash)
+}
@@ -0,0 +1,56 @@
+package clidecodemetadata
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	"github.com/Ethernal-Tech/cardano-api/common"
+)
+
+type CmdResult struct {
+	response.DecodedTxResponse
+}
+
+func (r CmdResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("[TX METADATA]\n")
+
+	values := []string{
+		fmt.Sprintf("Tx hash|%s", r.TxHash),
+		fmt.Sprintf("Type|%s", r.MetadataType),
+		fmt.Sprintf("Version|%d", r.MetadataVersion),
+	}
+
+	switch common.BridgingTxType(r.MetadataType) {
+	case common.BridgingTxTypeBridgingRequest:
+		values = append(values,
+			fmt.Sprintf("Destination chain|%s", r.DestinationChainID),
+			fmt.Sprintf("Sender|%s", r.SenderAddr),
+			fmt.Sprintf("Fee amount|%d", r.FeeAmount),
+		)
+
+		if r.RefundAddr != "" {
+			values = append(values, fmt.Sprintf("Refund address|%s", r.RefundAddr))
+		}
+	case common.BridgingTxTypeBatchExecution:
+		values = append(values, fmt.Sprintf("Batch nonce id|%d", r.BatchNonceID))
+	case common.BridgingTxTypeRefundExecution:
+	}
+
+	buffer.WriteString(common.FormatKV(values))
+
+	if len(r.Transactions) > 0 {
+		buffer.WriteString("\n\n[RECEIVERS]\n")
+
+		receivers := make([]string, len(r.Transactions))
+		for i, tx := range r.Transactions {
+			receivers[i] = fmt.Sprintf("%s|%d", tx.Addr, tx.Amount)
+		}
+
+		buffer.WriteString(common.FormatKV(receivers))
+	}
+
+	return buffer.String()
+}
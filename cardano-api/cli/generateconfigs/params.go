@@ -8,6 +8,8 @@ import (
 	cardanotx "github.com/Ethernal-Tech/cardano-api/cardano"
 	"github.com/Ethernal-Tech/cardano-api/common"
 	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-api/telemetry"
+	"github.com/Ethernal-Tech/cardano-api/webhook"
 	"github.com/Ethernal-Tech/cardano-infrastructure/logger"
 	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
 	"github.com/hashicorp/go-hclog"
@@ -38,14 +40,36 @@ const (
 
 	logsPathFlag = "logs-path"
 
-	utxoCacheTimeoutFlag = "utxo-cache-timeout"
-	utxoCacheKeysFlag    = "utxo-cache-keys"
+	utxoCacheTimeoutFlag      = "utxo-cache-timeout"
+	utxoCacheKeysFlag         = "utxo-cache-keys"
+	utxoCacheMaxAddressesFlag = "utxo-cache-max-addresses"
+	utxoCacheMaxInputsFlag    = "utxo-cache-max-inputs"
+
+	utxoReservationStoreTypeFlag   = "utxo-reservation-store-type"
+	utxoReservationStoreURLFlag    = "utxo-reservation-store-url"
+	utxoReservationStorePrefixFlag = "utxo-reservation-store-prefix"
+
+	feeQuoteTimeoutFlag = "fee-quote-timeout"
+	feeQuoteSecretFlag  = "fee-quote-secret"
+
+	otlpEndpointFlag    = "otlp-endpoint"
+	otlpServiceNameFlag = "otlp-service-name"
+	otlpSampleRatioFlag = "otlp-sample-ratio"
+
+	webhookStorePathFlag = "webhook-store-path"
 
 	oracleAPIURLFlag = "oracle-api-url"
 	oracleAPIKeyFlag = "oracle-api-key"
 
-	apiPortFlag = "api-port"
-	apiKeysFlag = "api-keys"
+	apiPortFlag      = "api-port"
+	apiKeysFlag      = "api-keys"
+	adminAPIKeysFlag = "admin-api-keys"
+
+	tlsCertFileFlag          = "tls-cert-file"
+	tlsKeyFileFlag           = "tls-key-file"
+	tlsMinVersionFlag        = "tls-min-version"
+	tlsClientCAFileFlag      = "tls-client-ca-file"
+	tlsRequireClientCertFlag = "tls-require-client-cert"
 
 	outputDirFlag      = "output-dir"
 	outputFileNameFlag = "output-file-name"
@@ -73,14 +97,36 @@ const (
 
 	logsPathFlagDesc = "path to where logs will be stored"
 
-	utxoCacheTimeoutFlagDec = "for how long should a UTXO be reserved in the cache"
-	utxoCacheKeysFlagDesc   = "list of keys for UTXO cache functionality"
+	utxoCacheTimeoutFlagDec       = "for how long should a UTXO be reserved in the cache"
+	utxoCacheKeysFlagDesc         = "list of keys for UTXO cache functionality"
+	utxoCacheMaxAddressesFlagDesc = "maximal number of addresses in the UTXO cache (0 for unlimited)"
+	utxoCacheMaxInputsFlagDesc    = "maximal number of reserved UTXOs in the cache (0 for unlimited)"
+
+	utxoReservationStoreTypeFlagDesc   = "type of UTXO reservation store shared between api replicas (redis or postgres)"
+	utxoReservationStoreURLFlagDesc    = "redis url or postgres connection string of UTXO reservation store"
+	utxoReservationStorePrefixFlagDesc = "redis key prefix or postgres table name of UTXO reservation store"
+
+	feeQuoteTimeoutFlagDesc = "for how long is the fee quote valid (0 disables fee quotes)"
+	feeQuoteSecretFlagDesc  = "secret for signing fee quote ids, should be the same for all api replicas" //nolint:gosec
+
+	otlpEndpointFlagDesc    = "OTLP/HTTP collector endpoint, e.g. http://localhost:4318 (empty disables tracing)"
+	otlpServiceNameFlagDesc = "service name reported in traces"
+	otlpSampleRatioFlagDesc = "ratio of sampled traces between 0 and 1 (0 samples all traces)"
+
+	webhookStorePathFlagDesc = "file in which webhooks and their pending events are stored (empty disables webhooks)"
 
 	oracleAPIURLFlagDesc = "(mandatory) URL of Oracle API"
 	oracleAPIKeyFlagDesc = "(mandatory) API Key of Oracle API" //nolint:gosec
 
-	apiPortFlagDesc = "port at which API should run"
-	apiKeysFlagDesc = "(mandatory) list of keys for API access"
+	apiPortFlagDesc      = "port at which API should run"
+	apiKeysFlagDesc      = "(mandatory unless client certificate is required) list of keys for API access"
+	adminAPIKeysFlagDesc = "list of keys for admin API access"
+
+	tlsCertFileFlagDesc          = "path to TLS certificate file (enables HTTPS)"
+	tlsKeyFileFlagDesc           = "path to TLS private key file"
+	tlsMinVersionFlagDesc        = "minimal TLS version (1.2 or 1.3)"
+	tlsClientCAFileFlagDesc      = "path to CA bundle for verifying client certificates (enables mTLS)"
+	tlsRequireClientCertFlagDesc = "reject clients without verified certificate"
 
 	outputDirFlagDesc      = "path to config jsons output directory"
 	outputFileNameFlagDesc = "config json output file name"
@@ -90,7 +136,11 @@ const (
 	defaultNetworkMagic                 = 0
 	defaultLogsPath                     = "./logs"
 	defaultUtxoCacheTimeout             = time.Second * 90
+	defaultUtxoCacheMaxAddresses        = 10_000
+	defaultUtxoCacheMaxInputs           = 100_000
+	defaultFeeQuoteTimeout              = time.Second * 60
 	defaultAPIPort                      = 10000
+	defaultTLSMinVersion                = "1.2"
 	defaultOutputDir                    = "./"
 	defaultOutputFileName               = "config.json"
 	defaultPrimeTTLSlotNumberInc        = 1800 + defaultPrimeBlockConfirmationCount*10  // BlockTimeSeconds
@@ -121,15 +171,37 @@ type generateConfigsParams struct {
 
 	nexusIsEnabled bool
 
-	logsPath         string
-	utxoCacheTimeout time.Duration
-	utxoCacheKeys    []string
+	logsPath              string
+	utxoCacheTimeout      time.Duration
+	utxoCacheKeys         []string
+	utxoCacheMaxAddresses int
+	utxoCacheMaxInputs    int
+
+	utxoReservationStoreType   string
+	utxoReservationStoreURL    string
+	utxoReservationStorePrefix string
+
+	feeQuoteTimeout time.Duration
+	feeQuoteSecret  string
+
+	otlpEndpoint    string
+	otlpServiceName string
+	otlpSampleRatio float64
+
+	webhookStorePath string
 
 	oracleAPIURL string
 	oracleAPIKey string
 
-	apiPort uint32
-	apiKeys []string
+	apiPort      uint32
+	apiKeys      []string
+	adminAPIKeys []string
+
+	tlsCertFile          string
+	tlsKeyFile           string
+	tlsMinVersion        string
+	tlsClientCAFile      string
+	tlsRequireClientCert bool
 
 	outputDir      string
 	outputFileName string
@@ -193,6 +265,24 @@ func (p *generateConfigsParams) validateFlags() error {
 			vectorBlockfrostURLFlag, vectorSocketPathFlag, vectorOgmiosURLFlag)
 	}
 
+	switch p.utxoReservationStoreType {
+	case "":
+	case core.ReservationStoreTypeRedis, core.ReservationStoreTypePostgres:
+		if p.utxoReservationStoreURL == "" {
+			return fmt.Errorf("missing %s", utxoReservationStoreURLFlag)
+		}
+	default:
+		return fmt.Errorf("invalid %s: %s", utxoReservationStoreTypeFlag, p.utxoReservationStoreType)
+	}
+
+	if p.feeQuoteTimeout > p.utxoCacheTimeout {
+		return fmt.Errorf("%s should not be greater than %s", feeQuoteTimeoutFlag, utxoCacheTimeoutFlag)
+	}
+
+	if p.otlpSampleRatio < 0 || p.otlpSampleRatio > 1 {
+		return fmt.Errorf("invalid %s: %v", otlpSampleRatioFlag, p.otlpSampleRatio)
+	}
+
 	if !common.IsValidHTTPURL(p.oracleAPIURL) {
 		return fmt.Errorf("invalid oracle API url: %s", p.oracleAPIURL)
 	}
@@ -201,7 +291,23 @@ func (p *generateConfigsParams) validateFlags() error {
 		return fmt.Errorf("missing %s", oracleAPIKeyFlag)
 	}
 
-	if len(p.apiKeys) == 0 {
+	if (p.tlsCertFile == "") != (p.tlsKeyFile == "") {
+		return fmt.Errorf("specify both %s and %s", tlsCertFileFlag, tlsKeyFileFlag)
+	}
+
+	if p.tlsCertFile == "" && (p.tlsClientCAFile != "" || p.tlsRequireClientCert) {
+		return fmt.Errorf("%s and %s require %s", tlsClientCAFileFlag, tlsRequireClientCertFlag, tlsCertFileFlag)
+	}
+
+	if p.tlsRequireClientCert && p.tlsClientCAFile == "" {
+		return fmt.Errorf("%s requires %s", tlsRequireClientCertFlag, tlsClientCAFileFlag)
+	}
+
+	if _, err := (core.TLSConfig{MinVersion: p.tlsMinVersion}).GetMinVersion(); err != nil {
+		return fmt.Errorf("invalid %s: %w", tlsMinVersionFlag, err)
+	}
+
+	if len(p.apiKeys) == 0 && !p.tlsRequireClientCert {
 		return fmt.Errorf("specify at least one %s", apiKeysFlag)
 	}
 
@@ -340,6 +446,76 @@ func (p *generateConfigsParams) setFlags(cmd *cobra.Command) {
 		nil,
 		utxoCacheKeysFlagDesc,
 	)
+	cmd.Flags().IntVar(
+		&p.utxoCacheMaxAddresses,
+		utxoCacheMaxAddressesFlag,
+		defaultUtxoCacheMaxAddresses,
+		utxoCacheMaxAddressesFlagDesc,
+	)
+	cmd.Flags().IntVar(
+		&p.utxoCacheMaxInputs,
+		utxoCacheMaxInputsFlag,
+		defaultUtxoCacheMaxInputs,
+		utxoCacheMaxInputsFlagDesc,
+	)
+
+	cmd.Flags().StringVar(
+		&p.utxoReservationStoreType,
+		utxoReservationStoreTypeFlag,
+		"",
+		utxoReservationStoreTypeFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.utxoReservationStoreURL,
+		utxoReservationStoreURLFlag,
+		"",
+		utxoReservationStoreURLFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.utxoReservationStorePrefix,
+		utxoReservationStorePrefixFlag,
+		"",
+		utxoReservationStorePrefixFlagDesc,
+	)
+
+	cmd.Flags().DurationVar(
+		&p.feeQuoteTimeout,
+		feeQuoteTimeoutFlag,
+		defaultFeeQuoteTimeout,
+		feeQuoteTimeoutFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.feeQuoteSecret,
+		feeQuoteSecretFlag,
+		"",
+		feeQuoteSecretFlagDesc,
+	)
+
+	cmd.Flags().StringVar(
+		&p.otlpEndpoint,
+		otlpEndpointFlag,
+		"",
+		otlpEndpointFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.otlpServiceName,
+		otlpServiceNameFlag,
+		"",
+		otlpServiceNameFlagDesc,
+	)
+	cmd.Flags().Float64Var(
+		&p.otlpSampleRatio,
+		otlpSampleRatioFlag,
+		0,
+		otlpSampleRatioFlagDesc,
+	)
+
+	cmd.Flags().StringVar(
+		&p.webhookStorePath,
+		webhookStorePathFlag,
+		"",
+		webhookStorePathFlagDesc,
+	)
 
 	cmd.Flags().StringVar(
 		&p.oracleAPIURL,
@@ -379,6 +555,43 @@ func (p *generateConfigsParams) setFlags(cmd *cobra.Command) {
 		nil,
 		apiKeysFlagDesc,
 	)
+	cmd.Flags().StringArrayVar(
+		&p.adminAPIKeys,
+		adminAPIKeysFlag,
+		nil,
+		adminAPIKeysFlagDesc,
+	)
+
+	cmd.Flags().StringVar(
+		&p.tlsCertFile,
+		tlsCertFileFlag,
+		"",
+		tlsCertFileFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.tlsKeyFile,
+		tlsKeyFileFlag,
+		"",
+		tlsKeyFileFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.tlsMinVersion,
+		tlsMinVersionFlag,
+		defaultTLSMinVersion,
+		tlsMinVersionFlagDesc,
+	)
+	cmd.Flags().StringVar(
+		&p.tlsClientCAFile,
+		tlsClientCAFileFlag,
+		"",
+		tlsClientCAFileFlagDesc,
+	)
+	cmd.Flags().BoolVar(
+		&p.tlsRequireClientCert,
+		tlsRequireClientCertFlag,
+		false,
+		tlsRequireClientCertFlagDesc,
+	)
 
 	cmd.MarkFlagsMutuallyExclusive(primeBlockfrostAPIKeyFlag, primeSocketPathFlag, primeOgmiosURLFlag)
 	cmd.MarkFlagsMutuallyExclusive(vectorBlockfrostURLFlag, vectorSocketPathFlag, vectorOgmiosURLFlag)
@@ -426,7 +639,24 @@ func (p *generateConfigsParams) Execute() (common.ICommandResult, error) {
 				IsEnabled: p.nexusIsEnabled,
 			},
 		},
-		UtxoCacheTimeout: p.utxoCacheTimeout,
+		UtxoCacheTimeout:      p.utxoCacheTimeout,
+		UtxoCacheMaxAddresses: p.utxoCacheMaxAddresses,
+		UtxoCacheMaxInputs:    p.utxoCacheMaxInputs,
+		UtxoReservationStore: core.UtxoReservationStoreConfig{
+			Type:   p.utxoReservationStoreType,
+			URL:    p.utxoReservationStoreURL,
+			Prefix: p.utxoReservationStorePrefix,
+		},
+		FeeQuoteTimeout: p.feeQuoteTimeout,
+		FeeQuoteSecret:  p.feeQuoteSecret,
+		Telemetry: telemetry.Config{
+			Endpoint:    p.otlpEndpoint,
+			ServiceName: p.otlpServiceName,
+			SampleRatio: p.otlpSampleRatio,
+		},
+		Webhooks: webhook.Config{
+			StorePath: p.webhookStorePath,
+		},
 		OracleAPI: core.OracleAPISettings{
 			URL:    p.oracleAPIURL,
 			APIKey: p.oracleAPIKey,
@@ -466,6 +696,14 @@ func (p *generateConfigsParams) Execute() (common.ICommandResult, error) {
 			APIKeyHeader:  "x-api-key",
 			APIKeys:       p.apiKeys,
 			UTXOCacheKeys: p.utxoCacheKeys,
+			AdminAPIKeys:  p.adminAPIKeys,
+			TLS: core.TLSConfig{
+				CertFile:          p.tlsCertFile,
+				KeyFile:           p.tlsKeyFile,
+				MinVersion:        p.tlsMinVersion,
+				ClientCAFile:      p.tlsClientCAFile,
+				RequireClientCert: p.tlsRequireClientCert,
+			},
 		},
 	}
 
@@ -480,6 +718,6 @@ func (p *generateConfigsParams) Execute() (common.ICommandResult, error) {
 	}
 
 	return &CmdResult{
-		configPath: configPath,
+		ConfigPath: configPath,
 	}, nil
 }
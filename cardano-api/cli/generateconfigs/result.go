@@ -8,7 +8,7 @@ import (
 )
 
 type CmdResult struct {
-	configPath string
+	ConfigPath string `json:"configPath"`
 }
 
 func (r CmdResult) GetOutput() string {
@@ -16,7 +16,7 @@ func (r CmdResult) GetOutput() string {
 
 	buffer.WriteString(common.FormatKV(
 		[]string{
-			fmt.Sprintf("Config|%s", r.configPath),
+			fmt.Sprintf("Config|%s", r.ConfigPath),
 		}))
 
 	return buffer.String()
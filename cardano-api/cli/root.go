@@ -5,8 +5,12 @@ import (
 	"os"
 
 	clicardanoapi "github.com/Ethernal-Tech/cardano-api/cli/cardano-api"
+	clicreatebridgingtx "github.com/Ethernal-Tech/cardano-api/cli/createbridgingtx"
+	clidecodemetadata "github.com/Ethernal-Tech/cardano-api/cli/decodemetadata"
 	cligenerateconfigs "github.com/Ethernal-Tech/cardano-api/cli/generateconfigs"
+	clivalidateconfig "github.com/Ethernal-Tech/cardano-api/cli/validateconfig"
 	cliversion "github.com/Ethernal-Tech/cardano-api/cli/version"
+	"github.com/Ethernal-Tech/cardano-api/common"
 
 	"github.com/spf13/cobra"
 )
@@ -18,10 +22,23 @@ type RootCommand struct {
 func NewRootCommand() *RootCommand {
 	rootCommand := &RootCommand{
 		baseCmd: &cobra.Command{
-			Short: "cli commands for apex bridge",
+			Short:         "cli commands for apex bridge",
+			SilenceErrors: true,
+			PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+				if err := common.ValidateOutputFlag(cmd); err != nil {
+					return err
+				}
+
+				// usage text would break the json output
+				cmd.SilenceUsage = common.GetOutputFormat(cmd) == common.OutputFormatJSON
+
+				return nil
+			},
 		},
 	}
 
+	common.RegisterOutputFlag(rootCommand.baseCmd)
+
 	rootCommand.registerSubCommands()
 
 	return rootCommand
@@ -30,14 +47,21 @@ func NewRootCommand() *RootCommand {
 func (rc *RootCommand) registerSubCommands() {
 	rc.baseCmd.AddCommand(
 		cligenerateconfigs.GetGenerateConfigsCommand(),
+		clivalidateconfig.GetValidateConfigCommand(),
 		clicardanoapi.GetCardanoAPICommand(),
 		cliversion.GetVersionCommand(),
+		clicreatebridgingtx.GetCreateBridgingTxCommand(),
+		clidecodemetadata.GetDecodeMetadataCommand(),
 	)
 }
 
 func (rc *RootCommand) Execute() {
-	if err := rc.baseCmd.Execute(); err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
+	if cmd, err := rc.baseCmd.ExecuteC(); err != nil {
+		if common.GetOutputFormat(cmd) == common.OutputFormatJSON {
+			common.WriteJSONError(err)
+		} else {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+		}
 
 		os.Exit(1)
 	}
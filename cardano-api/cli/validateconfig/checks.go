@@ -0,0 +1,589 @@
+package clivalidateconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-api/oracle"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+)
+
+const minAPIKeyLength = 16
+
+var (
+	mainNetMagics = []uint32{
+		uint32(wallet.MainNetProtocolMagic), uint32(wallet.VectorMainNetProtocolMagic),
+	}
+	testNetMagics = []uint32{
+		uint32(wallet.TestNetProtocolMagic), uint32(wallet.PrimeTestNetProtocolMagic),
+		uint32(wallet.VectorTestNetProtocolMagic),
+	}
+)
+
+// checkUnknownFields decodes the config file strictly, so typos in keys are reported
+// instead of being silently ignored by common.LoadConfig
+func checkUnknownFields(configPath string) checkResult {
+	const name = "schema: fields"
+
+	f, err := os.Open(configPath)
+	if err != nil {
+		return failed(name, "failed to open config: %v", err)
+	}
+
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+
+	var config core.AppConfig
+
+	if err := decoder.Decode(&config); err != nil {
+		return failed(name, "%v", err)
+	}
+
+	return passed(name, "no unknown fields")
+}
+
+func checkSchema(config *core.AppConfig) []checkResult {
+	var errs []string
+
+	if len(config.CardanoChains) == 0 {
+		errs = append(errs, "at least one cardano chain must be configured")
+	}
+
+	for _, chainID := range sortedKeys(config.CardanoChains) {
+		if common.ToNumChainID(chainID) == 0 {
+			errs = append(errs, fmt.Sprintf("unknown cardano chain id: %s", chainID))
+		}
+
+		chainConfig := config.CardanoChains[chainID]
+		if chainConfig == nil || chainConfig.ChainSpecific == nil {
+			errs = append(errs, fmt.Sprintf("chainSpecific not specified for chain: %s", chainID))
+
+			continue
+		}
+
+		if chainConfig.NetworkID != wallet.MainNetNetwork && chainConfig.NetworkID != wallet.TestNetNetwork {
+			errs = append(errs, fmt.Sprintf("invalid networkID %d for chain: %s", chainConfig.NetworkID, chainID))
+		}
+
+		if chainConfig.ChainSpecific.TTLSlotNumberInc == 0 {
+			errs = append(errs, fmt.Sprintf("ttlSlotNumberIncrement not specified for chain: %s", chainID))
+		}
+	}
+
+	for _, chainID := range sortedKeys(config.EthChains) {
+		if common.ToNumChainID(chainID) == 0 {
+			errs = append(errs, fmt.Sprintf("unknown eth chain id: %s", chainID))
+		}
+	}
+
+	if !isWellFormedHTTPURL(config.OracleAPI.URL) {
+		errs = append(errs, fmt.Sprintf("invalid oracle API url: %s", config.OracleAPI.URL))
+	}
+
+	if oracleAPI := config.OracleAPI; oracleAPI.Timeout < 0 || oracleAPI.MaxAttempts < 0 ||
+		oracleAPI.InitialBackoff < 0 || oracleAPI.MaxBackoff < 0 || oracleAPI.BreakerTimeout < 0 {
+		errs = append(errs, "oracle API timeout, maxAttempts, backoffs and breakerTimeout should not be negative")
+	} else if oracleAPI.MaxBackoff > 0 && oracleAPI.MaxBackoff < oracleAPI.InitialBackoff {
+		errs = append(errs, "oracle API maxBackoff should not be less than initialBackoff")
+	}
+
+	if config.APIConfig.Port == 0 || config.APIConfig.Port > 65535 {
+		errs = append(errs, fmt.Sprintf("invalid api port: %d", config.APIConfig.Port))
+	}
+
+	if config.APIConfig.APIKeyHeader == "" {
+		errs = append(errs, "api key header not specified")
+	}
+
+	if config.APIConfig.MaxEventStreamsPerKey < 0 || config.APIConfig.MaxRequestBodySize < 0 {
+		errs = append(errs, "maxEventStreamsPerKey and maxRequestBodySize should not be negative")
+	}
+
+	if len(config.APIConfig.UTXOCacheKeys) > 0 && config.UtxoCacheTimeout <= 0 {
+		errs = append(errs, "utxo cache keys specified but utxoCacheTimeout is not positive")
+	}
+
+	if config.UtxoCacheMaxAddresses < 0 || config.UtxoCacheMaxInputs < 0 {
+		errs = append(errs, "utxoCacheMaxAddresses and utxoCacheMaxInputs should not be negative")
+	}
+
+	if err := config.ValidateFeeQuoteTimeout(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	switch config.UtxoReservationStore.Type {
+	case "":
+	case core.ReservationStoreTypeRedis, core.ReservationStoreTypePostgres:
+		if config.UtxoReservationStore.URL == "" {
+			errs = append(errs, "utxo reservation store url not specified")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("invalid utxo reservation store type: %s", config.UtxoReservationStore.Type))
+	}
+
+	if config.Telemetry.SampleRatio < 0 || config.Telemetry.SampleRatio > 1 {
+		errs = append(errs, "telemetry sampleRatio should be between 0 and 1")
+	}
+
+	if endpoint := config.Telemetry.Endpoint; strings.Contains(endpoint, "://") && !isWellFormedHTTPURL(endpoint) {
+		errs = append(errs, fmt.Sprintf("invalid telemetry endpoint: %s", endpoint))
+	}
+
+	if webhooks := config.Webhooks; webhooks.Timeout < 0 || webhooks.MaxAttempts < 0 ||
+		webhooks.InitialBackoff < 0 || webhooks.MaxBackoff < 0 || webhooks.TxPollInterval < 0 ||
+		webhooks.TxExpiryGracePeriod < 0 || webhooks.TxTrackTimeout < 0 {
+		errs = append(errs, "webhooks durations and maxAttempts should not be negative")
+	}
+
+	if storePath := config.Webhooks.StorePath; storePath != "" {
+		if info, err := os.Stat(filepath.Dir(storePath)); err != nil || !info.IsDir() {
+			errs = append(errs, fmt.Sprintf("directory of webhooks store does not exist: %s", storePath))
+		}
+	}
+
+	if len(errs) > 0 {
+		return []checkResult{failed("schema", "%s", strings.Join(errs, "; "))}
+	}
+
+	return []checkResult{passed("schema", "required fields are present")}
+}
+
+func checkAddresses(config *core.AppConfig) (results []checkResult) {
+	for _, chainID := range sortedKeys(config.CardanoChains) {
+		chainConfig := config.CardanoChains[chainID]
+		if chainConfig == nil {
+			continue
+		}
+
+		name := fmt.Sprintf("addresses: %s", chainID)
+
+		var errs []string
+
+		if chainConfig.NetworkID == wallet.TestNetNetwork && containsUint32(mainNetMagics, chainConfig.NetworkMagic) {
+			errs = append(errs, fmt.Sprintf("mainnet network magic %d used with testnet network id",
+				chainConfig.NetworkMagic))
+		} else if chainConfig.NetworkID == wallet.MainNetNetwork &&
+			containsUint32(testNetMagics, chainConfig.NetworkMagic) {
+			errs = append(errs, fmt.Sprintf("testnet network magic %d used with mainnet network id",
+				chainConfig.NetworkMagic))
+		}
+
+		addresses := map[string]string{
+			"fallbackAddress": chainConfig.BridgingAddresses.FallbackAddress,
+			"address":         chainConfig.BridgingAddresses.BridgingAddress,
+			"feeAddress":      chainConfig.BridgingAddresses.FeeAddress,
+		}
+
+		for _, key := range sortedKeys(addresses) {
+			if addr := addresses[key]; addr != "" && !isAddressOnNetwork(addr, chainConfig.NetworkID) {
+				errs = append(errs, fmt.Sprintf("%s %s does not belong to network %d", key, addr, chainConfig.NetworkID))
+			}
+		}
+
+		if chainConfig.IsEnabled && chainConfig.BridgingAddresses.FallbackAddress == "" {
+			errs = append(errs, "fallbackAddress not specified for enabled chain")
+		}
+
+		if len(errs) > 0 {
+			results = append(results, failed(name, "%s", strings.Join(errs, "; ")))
+		} else {
+			results = append(results, passed(name, "addresses match network %d", chainConfig.NetworkID))
+		}
+	}
+
+	return results
+}
+
+func checkProviders(config *core.AppConfig) (results []checkResult) {
+	for _, chainID := range sortedKeys(config.CardanoChains) {
+		chainConfig := config.CardanoChains[chainID]
+		if chainConfig == nil || chainConfig.ChainSpecific == nil {
+			continue
+		}
+
+		name := fmt.Sprintf("provider: %s", chainID)
+		specific := chainConfig.ChainSpecific
+
+		var providers []string
+
+		if specific.OgmiosURL != "" {
+			providers = append(providers, "ogmiosUrl")
+		}
+
+		if specific.BlockfrostURL != "" {
+			providers = append(providers, "blockfrostUrl")
+		}
+
+		if specific.SocketPath != "" {
+			providers = append(providers, "socketPath")
+		}
+
+		switch {
+		case len(providers) == 0 && chainConfig.IsEnabled:
+			results = append(results, failed(name, "enabled chain has no ogmiosUrl, blockfrostUrl or socketPath"))
+		case len(providers) == 0:
+			results = append(results, skipped(name, "chain is disabled and has no provider"))
+		case len(providers) > 1:
+			results = append(results, failed(name, "providers are mutually exclusive: %s",
+				strings.Join(providers, ", ")))
+		case specific.OgmiosURL != "" && !isWellFormedHTTPURL(specific.OgmiosURL):
+			results = append(results, failed(name, "invalid ogmiosUrl: %s", specific.OgmiosURL))
+		case specific.BlockfrostURL != "" && !isWellFormedHTTPURL(specific.BlockfrostURL):
+			results = append(results, failed(name, "invalid blockfrostUrl: %s", specific.BlockfrostURL))
+		case specific.BlockfrostURL != "" && specific.BlockfrostAPIKey == "":
+			results = append(results, failed(name, "blockfrostApiKey not specified"))
+		default:
+			results = append(results, passed(name, "%s", providers[0]))
+		}
+	}
+
+	return results
+}
+
+func checkAPIKeys(config *core.AppConfig) []checkResult {
+	var errs []string
+
+	if len(config.APIConfig.APIKeys) == 0 && !config.APIConfig.TLS.RequireClientCert {
+		errs = append(errs, "no api keys specified")
+	}
+
+	errs = append(errs, checkKeyList("apiKeys", config.APIConfig.APIKeys)...)
+	errs = append(errs, checkKeyList("utxoCacheKeys", config.APIConfig.UTXOCacheKeys)...)
+	errs = append(errs, checkKeyList("adminApiKeys", config.APIConfig.AdminAPIKeys)...)
+
+	for i, key := range config.APIConfig.AdminAPIKeys {
+		if slices.Contains(config.APIConfig.APIKeys, key) {
+			errs = append(errs, fmt.Sprintf("adminApiKeys[%d] is also regular api key", i))
+		}
+	}
+
+	if config.OracleAPI.APIKey == "" {
+		errs = append(errs, "oracle api key not specified")
+	}
+
+	if len(errs) > 0 {
+		return []checkResult{failed("api keys", "%s", strings.Join(errs, "; "))}
+	}
+
+	return []checkResult{passed("api keys", "%d api key(s), %d utxo cache key(s), %d admin api key(s)",
+		len(config.APIConfig.APIKeys), len(config.APIConfig.UTXOCacheKeys), len(config.APIConfig.AdminAPIKeys))}
+}
+
+func checkTLS(config *core.AppConfig) []checkResult {
+	const name = "tls"
+
+	tlsConfig := config.APIConfig.TLS
+	if !tlsConfig.IsEnabled() {
+		if tlsConfig.ClientCAFile != "" || tlsConfig.RequireClientCert {
+			return []checkResult{failed(name, "client certificate settings specified but tls is not enabled")}
+		}
+
+		return []checkResult{passed(name, "disabled")}
+	}
+
+	var errs []string
+
+	if _, err := tlsConfig.GetMinVersion(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	cert, err := tls.LoadX509KeyPair(tlsConfig.CertFile, tlsConfig.KeyFile)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("failed to load certificate: %v", err))
+	} else if cert.Leaf != nil && time.Now().After(cert.Leaf.NotAfter) {
+		errs = append(errs, fmt.Sprintf("certificate expired at %s", cert.Leaf.NotAfter.UTC()))
+	}
+
+	if tlsConfig.ClientCAFile != "" {
+		caBundle, err := os.ReadFile(tlsConfig.ClientCAFile)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to read client CA file: %v", err))
+		} else if !x509.NewCertPool().AppendCertsFromPEM(caBundle) {
+			errs = append(errs, "no certificates found in client CA file")
+		}
+	} else if tlsConfig.RequireClientCert {
+		errs = append(errs, "client certificate required but client CA file not specified")
+	}
+
+	if len(errs) > 0 {
+		return []checkResult{failed(name, "%s", strings.Join(errs, "; "))}
+	}
+
+	if tlsConfig.ClientCAFile != "" {
+		return []checkResult{passed(name, "enabled with client certificate verification")}
+	}
+
+	return []checkResult{passed(name, "enabled")}
+}
+
+func checkKeyList(name string, keys []string) (errs []string) {
+	seen := make(map[string]int, len(keys))
+
+	for i, key := range keys {
+		if prev, exists := seen[key]; exists {
+			errs = append(errs, fmt.Sprintf("%s[%d] duplicates %s[%d]", name, i, name, prev))
+
+			continue
+		}
+
+		seen[key] = i
+
+		if len(strings.TrimSpace(key)) < minAPIKeyLength {
+			errs = append(errs, fmt.Sprintf("%s[%d] is weak: shorter than %d characters", name, i, minAPIKeyLength))
+		}
+	}
+
+	return errs
+}
+
+func probeOracle(ctx context.Context, config *core.AppConfig, timeout time.Duration) checkResult {
+	const name = "online: oracle"
+
+	ctx, cancelFn := context.WithTimeout(ctx, timeout)
+	defer cancelFn()
+
+	// probe only once, without retries
+	oracleConfig := config.OracleAPI
+	oracleConfig.Timeout = timeout
+	oracleConfig.MaxAttempts = 1
+
+	settings, err := oracle.Get[*core.SettingsResponse](ctx, oracle.NewClient(oracleConfig), "/api/Settings/Get")
+	if err != nil {
+		return failed(name, "%v", err)
+	}
+
+	return passed(name, "reachable, allowed directions: %d", len(settings.AllowedDirections))
+}
+
+func probeChains(ctx context.Context, config *core.AppConfig, timeout time.Duration) (results []checkResult) {
+	for _, chainID := range sortedKeys(config.CardanoChains) {
+		chainConfig := config.CardanoChains[chainID]
+		if chainConfig == nil || chainConfig.ChainSpecific == nil || !chainConfig.IsEnabled {
+			continue
+		}
+
+		// CreateTxProvider relies on these being set which normally happens in AppConfig.FillOut.
+		// They are set on a copy, so validation does not change the loaded config
+		specific := *chainConfig.ChainSpecific
+		specific.NetworkID = chainConfig.NetworkID
+		specific.NetworkMagic = chainConfig.NetworkMagic
+
+		txProvider, err := specific.CreateTxProvider()
+		if err != nil {
+			results = append(results, failed(fmt.Sprintf("online: tip %s", chainID), "%v", err))
+
+			continue
+		}
+
+		results = append(results,
+			probeTip(ctx, chainID, txProvider, timeout),
+			probeNetworkMagic(ctx, chainID, chainConfig, timeout),
+			probeFallbackAddress(ctx, chainID, chainConfig, txProvider, timeout))
+
+		txProvider.Dispose()
+	}
+
+	return results
+}
+
+func probeTip(
+	ctx context.Context, chainID string, txProvider wallet.ITxProvider, timeout time.Duration,
+) checkResult {
+	name := fmt.Sprintf("online: tip %s", chainID)
+
+	ctx, cancelFn := context.WithTimeout(ctx, timeout)
+	defer cancelFn()
+
+	tip, err := txProvider.GetTip(ctx)
+	if err != nil {
+		return failed(name, "%v", err)
+	}
+
+	return passed(name, "block %d, slot %d", tip.Block, tip.Slot)
+}
+
+func probeNetworkMagic(
+	ctx context.Context, chainID string, chainConfig *core.CardanoChainConfig, timeout time.Duration,
+) checkResult {
+	name := fmt.Sprintf("online: network magic %s", chainID)
+
+	if chainConfig.NetworkMagic == 0 {
+		return skipped(name, "network magic not configured")
+	}
+
+	ctx, cancelFn := context.WithTimeout(ctx, timeout)
+	defer cancelFn()
+
+	var (
+		networkMagic uint32
+		err          error
+	)
+
+	specific := chainConfig.ChainSpecific
+
+	switch {
+	case specific.OgmiosURL != "":
+		networkMagic, err = fetchOgmiosNetworkMagic(ctx, specific.OgmiosURL)
+	case specific.BlockfrostURL != "":
+		networkMagic, err = fetchBlockfrostNetworkMagic(
+			ctx, specific.BlockfrostURL, specific.GetBlockfrostAuthHeaderKey(), specific.BlockfrostAPIKey)
+	default:
+		return skipped(name, "socket provider verifies network magic during node handshake")
+	}
+
+	if err != nil {
+		return failed(name, "%v", err)
+	}
+
+	if networkMagic != chainConfig.NetworkMagic {
+		return failed(name, "provider reports %d, config has %d", networkMagic, chainConfig.NetworkMagic)
+	}
+
+	return passed(name, "%d", networkMagic)
+}
+
+func probeFallbackAddress(
+	ctx context.Context, chainID string, chainConfig *core.CardanoChainConfig,
+	txProvider wallet.ITxProvider, timeout time.Duration,
+) checkResult {
+	name := fmt.Sprintf("online: fallback address %s", chainID)
+	addr := chainConfig.BridgingAddresses.FallbackAddress
+
+	if addr == "" {
+		return skipped(name, "fallback address not configured")
+	}
+
+	ctx, cancelFn := context.WithTimeout(ctx, timeout)
+	defer cancelFn()
+
+	utxos, err := txProvider.GetUtxos(ctx, addr)
+	if err != nil {
+		return failed(name, "%v", err)
+	}
+
+	if len(utxos) == 0 {
+		return failed(name, "no utxos found on %s", addr)
+	}
+
+	return passed(name, "%d utxo(s) found on %s", len(utxos), addr)
+}
+
+func fetchOgmiosNetworkMagic(ctx context.Context, ogmiosURL string) (uint32, error) {
+	type genesisRequest struct {
+		Jsonrpc string            `json:"jsonrpc"`
+		Method  string            `json:"method"`
+		Params  map[string]string `json:"params"`
+	}
+
+	type genesisResponse struct {
+		Result struct {
+			NetworkMagic uint32 `json:"networkMagic"`
+		} `json:"result"`
+	}
+
+	response, err := common.HTTPPost[genesisRequest, genesisResponse](ctx, ogmiosURL, genesisRequest{
+		Jsonrpc: "2.0",
+		Method:  "queryNetwork/genesisConfiguration",
+		Params:  map[string]string{"era": "shelley"},
+	}, "")
+	if err != nil {
+		return 0, err
+	}
+
+	return response.Result.NetworkMagic, nil
+}
+
+func fetchBlockfrostNetworkMagic(
+	ctx context.Context, blockfrostURL, authHeaderKey, apiKey string,
+) (uint32, error) {
+	type genesisResponse struct {
+		NetworkMagic uint32 `json:"network_magic"`
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, blockfrostURL+"/genesis", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set(authHeaderKey, apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("http status for %s code is %d", req.URL.String(), resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var response genesisResponse
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return 0, err
+	}
+
+	if response.NetworkMagic == 0 {
+		return 0, errors.New("network magic not found in genesis response")
+	}
+
+	return response.NetworkMagic, nil
+}
+
+// isWellFormedHTTPURL is like common.IsValidHTTPURL but does not resolve the host,
+// so it can be used for offline checks
+func isWellFormedHTTPURL(input string) bool {
+	u, err := url.Parse(input)
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isAddressOnNetwork(addr string, networkID wallet.CardanoNetworkType) bool {
+	cardanoAddr, err := wallet.NewCardanoAddressFromString(addr)
+
+	return err == nil && cardanoAddr.String() == addr && cardanoAddr.GetInfo().Network == networkID
+}
+
+func containsUint32(values []uint32, value uint32) bool {
+	for _, x := range values {
+		if x == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
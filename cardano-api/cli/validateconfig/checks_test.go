@@ -0,0 +1,460 @@
+package clivalidateconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cardanotx "github.com/Ethernal-Tech/cardano-api/cardano"
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testnetAddr = "addr_test1vqfuetznnmngqzquslwcu0ygn2hq29vjlpytlpwss762vcgun5vvw"
+	mainnetAddr = "addr1w8nv7cp7revdt70yuc96z4ke9pasa70grc5clhyf7q70f4spev3dn"
+	testAPIKey  = "0123456789abcdef0123"
+)
+
+func newValidConfig() *core.AppConfig {
+	return &core.AppConfig{
+		CardanoChains: map[string]*core.CardanoChainConfig{
+			common.ChainIDStrPrime: {
+				NetworkID:    wallet.TestNetNetwork,
+				NetworkMagic: uint32(wallet.PrimeTestNetProtocolMagic),
+				IsEnabled:    true,
+				BridgingAddresses: core.BridgingAddresses{
+					BridgingAddress: testnetAddr,
+					FeeAddress:      testnetAddr,
+					FallbackAddress: testnetAddr,
+				},
+				ChainSpecific: &cardanotx.CardanoChainConfig{
+					OgmiosURL:        "http://localhost:1337",
+					TTLSlotNumberInc: 1800,
+				},
+			},
+		},
+		OracleAPI: core.OracleAPISettings{URL: "http://localhost:40000", APIKey: "oracle-key"},
+		APIConfig: core.APIConfig{
+			Port:         40000,
+			APIKeyHeader: "x-api-key",
+			APIKeys:      []string{testAPIKey},
+		},
+		UtxoCacheTimeout: time.Minute,
+	}
+}
+
+func requireCheck(t *testing.T, results []checkResult, status checkStatus, message string) {
+	t.Helper()
+
+	require.Len(t, results, 1)
+	require.Equal(t, status, results[0].Status, results[0].Message)
+	require.Contains(t, results[0].Message, message)
+}
+
+func TestCheckUnknownFields(t *testing.T) {
+	dir := t.TempDir()
+
+	validPath := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(validPath, []byte(`{"utxoCacheTimeout": 1000}`), 0600))
+
+	typoPath := filepath.Join(dir, "typo.json")
+	require.NoError(t, os.WriteFile(typoPath, []byte(`{"utxoCacheTimout": 1000}`), 0600))
+
+	require.Equal(t, checkStatusPass, checkUnknownFields(validPath).Status)
+
+	result := checkUnknownFields(typoPath)
+	require.Equal(t, checkStatusFail, result.Status)
+	require.Contains(t, result.Message, "utxoCacheTimout")
+
+	require.Equal(t, checkStatusFail, checkUnknownFields(filepath.Join(dir, "missing.json")).Status)
+}
+
+func TestCheckSchema(t *testing.T) {
+	testCases := []struct {
+		name    string
+		modify  func(config *core.AppConfig)
+		status  checkStatus
+		message string
+	}{
+		{"valid", func(*core.AppConfig) {}, checkStatusPass, "required fields are present"},
+		{
+			"no chains",
+			func(config *core.AppConfig) { config.CardanoChains = nil },
+			checkStatusFail, "at least one cardano chain must be configured",
+		},
+		{
+			"unknown chain",
+			func(config *core.AppConfig) {
+				config.CardanoChains["unknown"] = config.CardanoChains[common.ChainIDStrPrime]
+			},
+			checkStatusFail, "unknown cardano chain id: unknown",
+		},
+		{
+			"missing chain specific",
+			func(config *core.AppConfig) { config.CardanoChains[common.ChainIDStrPrime].ChainSpecific = nil },
+			checkStatusFail, "chainSpecific not specified for chain: prime",
+		},
+		{
+			"missing ttl",
+			func(config *core.AppConfig) {
+				config.CardanoChains[common.ChainIDStrPrime].ChainSpecific.TTLSlotNumberInc = 0
+			},
+			checkStatusFail, "ttlSlotNumberIncrement not specified for chain: prime",
+		},
+		{
+			"invalid oracle url",
+			func(config *core.AppConfig) { config.OracleAPI.URL = "localhost:40000" },
+			checkStatusFail, "invalid oracle API url",
+		},
+		{
+			"invalid oracle backoff",
+			func(config *core.AppConfig) {
+				config.OracleAPI.InitialBackoff = time.Second
+				config.OracleAPI.MaxBackoff = time.Millisecond
+			},
+			checkStatusFail, "maxBackoff should not be less than initialBackoff",
+		},
+		{
+			"invalid port",
+			func(config *core.AppConfig) { config.APIConfig.Port = 70000 },
+			checkStatusFail, "invalid api port: 70000",
+		},
+		{
+			"fee quote timeout",
+			func(config *core.AppConfig) { config.FeeQuoteTimeout = 2 * time.Minute },
+			checkStatusFail, "greater than utxoCacheTimeout",
+		},
+		{
+			"reservation store",
+			func(config *core.AppConfig) { config.UtxoReservationStore.Type = "mongo" },
+			checkStatusFail, "invalid utxo reservation store type: mongo",
+		},
+		{
+			"webhooks store directory",
+			func(config *core.AppConfig) { config.Webhooks.StorePath = "/not/existing/dir/webhooks.json" },
+			checkStatusFail, "directory of webhooks store does not exist",
+		},
+		{
+			"all errors are reported",
+			func(config *core.AppConfig) {
+				config.APIConfig.APIKeyHeader = ""
+				config.Telemetry.SampleRatio = 2
+			},
+			checkStatusFail, "api key header not specified; telemetry sampleRatio should be between 0 and 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := newValidConfig()
+			tc.modify(config)
+
+			requireCheck(t, checkSchema(config), tc.status, tc.message)
+		})
+	}
+}
+
+func TestCheckAddresses(t *testing.T) {
+	testCases := []struct {
+		name    string
+		modify  func(chainConfig *core.CardanoChainConfig)
+		status  checkStatus
+		message string
+	}{
+		{"valid", func(*core.CardanoChainConfig) {}, checkStatusPass, "addresses match network"},
+		{
+			"mainnet magic on testnet",
+			func(chainConfig *core.CardanoChainConfig) {
+				chainConfig.NetworkMagic = uint32(wallet.MainNetProtocolMagic)
+			},
+			checkStatusFail, "mainnet network magic 764824073 used with testnet network id",
+		},
+		{
+			"testnet magic on mainnet",
+			func(chainConfig *core.CardanoChainConfig) {
+				chainConfig.NetworkID = wallet.MainNetNetwork
+				chainConfig.BridgingAddresses = core.BridgingAddresses{FallbackAddress: mainnetAddr}
+			},
+			checkStatusFail, "testnet network magic 3311 used with mainnet network id",
+		},
+		{
+			"address of other network",
+			func(chainConfig *core.CardanoChainConfig) {
+				chainConfig.BridgingAddresses.FeeAddress = mainnetAddr
+			},
+			checkStatusFail, "feeAddress " + mainnetAddr + " does not belong to network",
+		},
+		{
+			"invalid address",
+			func(chainConfig *core.CardanoChainConfig) {
+				chainConfig.BridgingAddresses.BridgingAddress = "addr_test1invalid"
+			},
+			checkStatusFail, "address addr_test1invalid does not belong to network",
+		},
+		{
+			"missing fallback address",
+			func(chainConfig *core.CardanoChainConfig) {
+				chainConfig.BridgingAddresses.FallbackAddress = ""
+			},
+			checkStatusFail, "fallbackAddress not specified for enabled chain",
+		},
+		{
+			"missing fallback address of disabled chain",
+			func(chainConfig *core.CardanoChainConfig) {
+				chainConfig.IsEnabled = false
+				chainConfig.BridgingAddresses.FallbackAddress = ""
+			},
+			checkStatusPass, "addresses match network",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := newValidConfig()
+			tc.modify(config.CardanoChains[common.ChainIDStrPrime])
+
+			requireCheck(t, checkAddresses(config), tc.status, tc.message)
+		})
+	}
+}
+
+func TestCheckProviders(t *testing.T) {
+	testCases := []struct {
+		name     string
+		specific cardanotx.CardanoChainConfig
+		disabled bool
+		status   checkStatus
+		message  string
+	}{
+		{"ogmios", cardanotx.CardanoChainConfig{OgmiosURL: "http://localhost:1337"}, false, checkStatusPass, "ogmiosUrl"},
+		{
+			"blockfrost",
+			cardanotx.CardanoChainConfig{BlockfrostURL: "https://blockfrost.io/api/v0", BlockfrostAPIKey: "key"},
+			false, checkStatusPass, "blockfrostUrl",
+		},
+		{"socket", cardanotx.CardanoChainConfig{SocketPath: "/tmp/node.socket"}, false, checkStatusPass, "socketPath"},
+		{
+			"no provider", cardanotx.CardanoChainConfig{}, false,
+			checkStatusFail, "enabled chain has no ogmiosUrl, blockfrostUrl or socketPath",
+		},
+		{"no provider on disabled chain", cardanotx.CardanoChainConfig{}, true, checkStatusSkip, "chain is disabled"},
+		{
+			"multiple providers",
+			cardanotx.CardanoChainConfig{OgmiosURL: "http://localhost:1337", SocketPath: "/tmp/node.socket"},
+			false, checkStatusFail, "providers are mutually exclusive: ogmiosUrl, socketPath",
+		},
+		{
+			"invalid ogmios url", cardanotx.CardanoChainConfig{OgmiosURL: "localhost:1337"}, false,
+			checkStatusFail, "invalid ogmiosUrl",
+		},
+		{
+			"missing blockfrost api key", cardanotx.CardanoChainConfig{BlockfrostURL: "https://blockfrost.io/api/v0"},
+			false, checkStatusFail, "blockfrostApiKey not specified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := newValidConfig()
+			config.CardanoChains[common.ChainIDStrPrime].ChainSpecific = &tc.specific
+			config.CardanoChains[common.ChainIDStrPrime].IsEnabled = !tc.disabled
+
+			requireCheck(t, checkProviders(config), tc.status, tc.message)
+		})
+	}
+}
+
+func TestCheckAPIKeys(t *testing.T) {
+	testCases := []struct {
+		name    string
+		modify  func(config *core.AppConfig)
+		status  checkStatus
+		message string
+	}{
+		{"valid", func(*core.AppConfig) {}, checkStatusPass, "1 api key(s), 0 utxo cache key(s), 0 admin api key(s)"},
+		{
+			"no api keys",
+			func(config *core.AppConfig) { config.APIConfig.APIKeys = nil },
+			checkStatusFail, "no api keys specified",
+		},
+		{
+			"no api keys with client certificates",
+			func(config *core.AppConfig) {
+				config.APIConfig.APIKeys = nil
+				config.APIConfig.TLS.RequireClientCert = true
+			},
+			checkStatusPass, "0 api key(s)",
+		},
+		{
+			"weak key",
+			func(config *core.AppConfig) { config.APIConfig.UTXOCacheKeys = []string{"short"} },
+			checkStatusFail, "utxoCacheKeys[0] is weak: shorter than 16 characters",
+		},
+		{
+			"duplicated key",
+			func(config *core.AppConfig) { config.APIConfig.APIKeys = []string{testAPIKey, testAPIKey} },
+			checkStatusFail, "apiKeys[1] duplicates apiKeys[0]",
+		},
+		{
+			"admin key is regular key",
+			func(config *core.AppConfig) { config.APIConfig.AdminAPIKeys = []string{testAPIKey} },
+			checkStatusFail, "adminApiKeys[0] is also regular api key",
+		},
+		{
+			"missing oracle api key",
+			func(config *core.AppConfig) { config.OracleAPI.APIKey = "" },
+			checkStatusFail, "oracle api key not specified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := newValidConfig()
+			tc.modify(config)
+
+			requireCheck(t, checkAPIKeys(config), tc.status, tc.message)
+		})
+	}
+}
+
+func writeTestCert(t *testing.T, dir string, notAfter time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    notAfter.Add(-2 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	return certFile, keyFile
+}
+
+func TestCheckTLS(t *testing.T) {
+	validDir, expiredDir := t.TempDir(), t.TempDir()
+	certFile, keyFile := writeTestCert(t, validDir, time.Now().Add(time.Hour))
+	expiredCertFile, expiredKeyFile := writeTestCert(t, expiredDir, time.Now().Add(-time.Hour))
+
+	// certificate is a valid CA bundle as well
+	caFile := certFile
+	invalidCAFile := keyFile
+
+	testCases := []struct {
+		name    string
+		tls     core.TLSConfig
+		status  checkStatus
+		message string
+	}{
+		{"disabled", core.TLSConfig{}, checkStatusPass, "disabled"},
+		{
+			"client certificate without tls", core.TLSConfig{RequireClientCert: true},
+			checkStatusFail, "client certificate settings specified but tls is not enabled",
+		},
+		{"enabled", core.TLSConfig{CertFile: certFile, KeyFile: keyFile}, checkStatusPass, "enabled"},
+		{
+			"client certificate verification",
+			core.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, RequireClientCert: true},
+			checkStatusPass, "enabled with client certificate verification",
+		},
+		{
+			"unsupported version", core.TLSConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.1"},
+			checkStatusFail, "unsupported tls version: 1.1",
+		},
+		{
+			"missing key", core.TLSConfig{CertFile: certFile},
+			checkStatusFail, "failed to load certificate",
+		},
+		{
+			"expired certificate", core.TLSConfig{CertFile: expiredCertFile, KeyFile: expiredKeyFile},
+			checkStatusFail, "certificate expired at",
+		},
+		{
+			"invalid client CA file",
+			core.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: invalidCAFile},
+			checkStatusFail, "no certificates found in client CA file",
+		},
+		{
+			"client certificate without CA file",
+			core.TLSConfig{CertFile: certFile, KeyFile: keyFile, RequireClientCert: true},
+			checkStatusFail, "client certificate required but client CA file not specified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := newValidConfig()
+			config.APIConfig.TLS = tc.tls
+
+			requireCheck(t, checkTLS(config), tc.status, tc.message)
+		})
+	}
+}
+
+func TestProbes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/Settings/Get":
+			_, _ = w.Write([]byte(`{"allowedDirections": {"prime": ["vector"]}}`))
+		case "/genesis":
+			_, _ = w.Write([]byte(`{"network_magic": 3311}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	config := newValidConfig()
+	config.OracleAPI.URL = server.URL
+	chainConfig := config.CardanoChains[common.ChainIDStrPrime]
+	chainConfig.ChainSpecific = &cardanotx.CardanoChainConfig{BlockfrostURL: server.URL, BlockfrostAPIKey: "key"}
+
+	result := probeOracle(ctx, config, time.Second)
+	require.Equal(t, checkStatusPass, result.Status, result.Message)
+	require.Contains(t, result.Message, "allowed directions: 1")
+
+	result = probeNetworkMagic(ctx, common.ChainIDStrPrime, chainConfig, time.Second)
+	require.Equal(t, checkStatusPass, result.Status, result.Message)
+
+	chainConfig.NetworkMagic = uint32(wallet.VectorTestNetProtocolMagic)
+
+	result = probeNetworkMagic(ctx, common.ChainIDStrPrime, chainConfig, time.Second)
+	require.Equal(t, checkStatusFail, result.Status)
+	require.Contains(t, result.Message, "provider reports 3311, config has 1127")
+
+	t.Run("probe chains does not change config", func(t *testing.T) {
+		results := probeChains(ctx, config, time.Second)
+		require.Len(t, results, 3)
+
+		require.Equal(t, &cardanotx.CardanoChainConfig{BlockfrostURL: server.URL, BlockfrostAPIKey: "key"},
+			chainConfig.ChainSpecific)
+	})
+}
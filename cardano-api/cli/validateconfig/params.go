@@ -0,0 +1,92 @@
+package clivalidateconfig
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	configFlag       = "config"
+	onlineFlag       = "online"
+	probeTimeoutFlag = "probe-timeout"
+
+	configFlagDesc       = "path to config json file"
+	onlineFlagDesc       = "also run online probes against the oracle and the chain providers"
+	probeTimeoutFlagDesc = "timeout for each online probe"
+
+	defaultProbeTimeout = 30 * time.Second
+)
+
+type validateConfigParams struct {
+	config       string
+	online       bool
+	probeTimeout time.Duration
+}
+
+func (p *validateConfigParams) validateFlags() error {
+	if p.config == "" {
+		return fmt.Errorf("specify: %s", configFlag)
+	}
+
+	if p.probeTimeout <= 0 {
+		return fmt.Errorf("invalid %s: %s", probeTimeoutFlag, p.probeTimeout)
+	}
+
+	return nil
+}
+
+func (p *validateConfigParams) setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&p.config,
+		configFlag,
+		"",
+		configFlagDesc,
+	)
+	cmd.Flags().BoolVar(
+		&p.online,
+		onlineFlag,
+		false,
+		onlineFlagDesc,
+	)
+	cmd.Flags().DurationVar(
+		&p.probeTimeout,
+		probeTimeoutFlag,
+		defaultProbeTimeout,
+		probeTimeoutFlagDesc,
+	)
+}
+
+func (p *validateConfigParams) Execute(ctx context.Context) (*validationReport, error) {
+	config, err := common.LoadConfig[core.AppConfig](p.config, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	report := &validationReport{
+		ConfigPath: p.config,
+		Online:     p.online,
+	}
+
+	report.add(checkUnknownFields(p.config))
+	report.add(checkSchema(config)...)
+	report.add(checkAddresses(config)...)
+	report.add(checkProviders(config)...)
+	report.add(checkAPIKeys(config)...)
+	report.add(checkTLS(config)...)
+
+	if p.online {
+		report.add(probeOracle(ctx, config, p.probeTimeout))
+		report.add(probeChains(ctx, config, p.probeTimeout)...)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,85 @@
+package clivalidateconfig
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+)
+
+type checkStatus string
+
+const (
+	checkStatusPass checkStatus = "PASS"
+	checkStatusFail checkStatus = "FAIL"
+	checkStatusSkip checkStatus = "SKIP"
+)
+
+type checkResult struct {
+	Name    string      `json:"name"`
+	Status  checkStatus `json:"status"`
+	Message string      `json:"message"`
+}
+
+func passed(name string, format string, args ...any) checkResult {
+	return checkResult{Name: name, Status: checkStatusPass, Message: fmt.Sprintf(format, args...)}
+}
+
+func failed(name string, format string, args ...any) checkResult {
+	return checkResult{Name: name, Status: checkStatusFail, Message: fmt.Sprintf(format, args...)}
+}
+
+func skipped(name string, format string, args ...any) checkResult {
+	return checkResult{Name: name, Status: checkStatusSkip, Message: fmt.Sprintf(format, args...)}
+}
+
+type validationReport struct {
+	ConfigPath string        `json:"configPath"`
+	Online     bool          `json:"online"`
+	Checks     []checkResult `json:"checks"`
+}
+
+func (r *validationReport) add(checks ...checkResult) {
+	r.Checks = append(r.Checks, checks...)
+}
+
+func (r validationReport) countByStatus(status checkStatus) (cnt int) {
+	for _, x := range r.Checks {
+		if x.Status == status {
+			cnt++
+		}
+	}
+
+	return cnt
+}
+
+func (r validationReport) failedCount() int {
+	return r.countByStatus(checkStatusFail)
+}
+
+func (r validationReport) GetOutput() string {
+	var buffer bytes.Buffer
+
+	result := checkStatusPass
+	if r.failedCount() > 0 {
+		result = checkStatusFail
+	}
+
+	buffer.WriteString("[CONFIG VALIDATION]\n")
+	buffer.WriteString(common.FormatKV([]string{
+		fmt.Sprintf("Config|%s", r.ConfigPath),
+		fmt.Sprintf("Online probes|%t", r.Online),
+		fmt.Sprintf("Result|%s (passed: %d, failed: %d, skipped: %d)", result,
+			r.countByStatus(checkStatusPass), r.failedCount(), r.countByStatus(checkStatusSkip)),
+	}))
+	buffer.WriteString("\n\n[CHECKS]\n")
+
+	checks := make([]string, len(r.Checks))
+	for i, x := range r.Checks {
+		checks[i] = fmt.Sprintf("%s|%s %s", x.Name, x.Status, x.Message)
+	}
+
+	buffer.WriteString(common.FormatKV(checks))
+
+	return buffer.String()
+}
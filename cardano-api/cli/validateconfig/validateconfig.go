@@ -0,0 +1,51 @@
+package clivalidateconfig
+
+import (
+	"fmt"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/spf13/cobra"
+)
+
+var paramsData = &validateConfigParams{}
+
+func GetValidateConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "validate-config",
+		Short:   "validates config json file",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	paramsData.setFlags(cmd)
+
+	return cmd
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return paramsData.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := common.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	defer func() {
+		if r := recover(); r != nil {
+			outputter.SetError(fmt.Errorf("%v", r))
+		}
+	}()
+
+	report, err := paramsData.Execute(cmd.Context())
+	if err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(report)
+
+	if failed := report.failedCount(); failed > 0 {
+		outputter.SetError(fmt.Errorf("config validation failed: %d check(s) failed", failed))
+	}
+}
@@ -26,6 +26,11 @@ var (
 		ChainIDIntVector: ChainIDStrVector,
 		ChainIDIntNexus:  ChainIDStrNexus,
 	}
+	chainTypes = map[string]int{
+		ChainIDStrPrime:  ChainTypeCardano,
+		ChainIDStrVector: ChainTypeCardano,
+		ChainIDStrNexus:  ChainTypeEVM,
+	}
 )
 
 func ToNumChainID(chainIDStr string) chainIDNum {
@@ -35,3 +40,8 @@ func ToNumChainID(chainIDStr string) chainIDNum {
 func ToStrChainID(chainIDNum chainIDNum) string {
 	return intToStr[chainIDNum]
 }
+
+// GetChainType returns ChainTypeCardano or ChainTypeEVM. Unknown chains are considered cardano chains
+func GetChainType(chainID string) int {
+	return chainTypes[chainID]
+}
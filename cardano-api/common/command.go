@@ -1,7 +1,9 @@
 package common
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -9,6 +11,23 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	OutputFlag     = "output"
+	OutputFlagDesc = "output format: text or json"
+
+	OutputFormatText = "text"
+	OutputFormatJSON = "json"
+)
+
+// stdout, stderr and osExit are replaced in tests
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+	osExit           = os.Exit
+)
+
+// ICommandResult is the result of a command execution. GetOutput returns the human readable form,
+// while the structured form (used with --output json) is the JSON encoding of the result itself
 type ICommandResult interface {
 	GetOutput() string
 }
@@ -64,7 +83,39 @@ func (c *commonOutputFormatter) SetCommandResult(result ICommandResult) {
 	c.commandOutput = result
 }
 
+// RegisterOutputFlag registers the global output format flag
+func RegisterOutputFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(OutputFlag, OutputFormatText, OutputFlagDesc)
+}
+
+// ValidateOutputFlag returns an error if the output format is not supported
+func ValidateOutputFlag(cmd *cobra.Command) error {
+	if format := GetOutputFormat(cmd); format != OutputFormatText && format != OutputFormatJSON {
+		return fmt.Errorf("invalid %s: %s", OutputFlag, format)
+	}
+
+	return nil
+}
+
+// GetOutputFormat returns the output format requested for the command
+func GetOutputFormat(cmd *cobra.Command) string {
+	if cmd == nil {
+		return OutputFormatText
+	}
+
+	format, err := cmd.Flags().GetString(OutputFlag)
+	if err != nil || format == "" {
+		return OutputFormatText
+	}
+
+	return strings.ToLower(format)
+}
+
 func InitializeOutputter(cmd *cobra.Command) OutputFormatter {
+	if GetOutputFormat(cmd) == OutputFormatJSON {
+		return &jsonOutput{}
+	}
+
 	return &cliOutput{}
 }
 
@@ -75,23 +126,30 @@ type cliOutput struct {
 // WriteOutput implements OutputFormatter interface
 func (cli *cliOutput) WriteOutput() {
 	if cli.errorOutput != nil {
-		_, _ = fmt.Fprintln(os.Stderr, cli.getErrorOutput())
+		// partial result (if any) is still useful to the user
+		if cli.commandOutput != nil {
+			_, _ = fmt.Fprintln(stdout, cli.getCommandOutput())
+		}
+
+		_, _ = fmt.Fprintln(stderr, cli.getErrorOutput())
 
 		// return proper error exit code for cli error output
-		os.Exit(1)
+		osExit(1)
+
+		return
 	}
 
-	_, _ = fmt.Fprintln(os.Stdout, cli.getCommandOutput())
+	_, _ = fmt.Fprintln(stdout, cli.getCommandOutput())
 }
 
 // WriteCommandResult implements OutputFormatter interface
 func (cli *cliOutput) WriteCommandResult(result ICommandResult) {
-	_, _ = fmt.Fprintln(os.Stdout, result.GetOutput())
+	_, _ = fmt.Fprintln(stdout, result.GetOutput())
 }
 
 // WriteOutput implements OutputFormatter plus io.Writer interfaces
 func (cli *cliOutput) Write(p []byte) (n int, err error) {
-	return os.Stdout.Write(p)
+	return stdout.Write(p)
 }
 
 func (cli *cliOutput) getErrorOutput() string {
@@ -109,3 +167,59 @@ func (cli *cliOutput) getCommandOutput() string {
 
 	return cli.commandOutput.GetOutput()
 }
+
+type jsonErrorOutput struct {
+	Err    string         `json:"error"`
+	Result ICommandResult `json:"result,omitempty"`
+}
+
+type jsonOutput struct {
+	commonOutputFormatter
+}
+
+// WriteOutput implements OutputFormatter interface
+func (jo *jsonOutput) WriteOutput() {
+	if jo.errorOutput != nil {
+		_, _ = fmt.Fprintln(stdout, marshalJSONToString(jsonErrorOutput{
+			Err:    jo.errorOutput.Error(),
+			Result: jo.commandOutput,
+		}))
+
+		// return proper error exit code for json error output
+		osExit(1)
+
+		return
+	}
+
+	jo.WriteCommandResult(jo.commandOutput)
+}
+
+// WriteCommandResult implements OutputFormatter interface. Empty object is written if there is no result
+func (jo *jsonOutput) WriteCommandResult(result ICommandResult) {
+	if result == nil {
+		_, _ = fmt.Fprintln(stdout, "{}")
+
+		return
+	}
+
+	_, _ = fmt.Fprintln(stdout, marshalJSONToString(result))
+}
+
+// Write implements OutputFormatter plus io.Writer interfaces
+func (jo *jsonOutput) Write(p []byte) (n int, err error) {
+	return stdout.Write(p)
+}
+
+// WriteJSONError writes the error as json object. Used for errors which happen before the command runs
+func WriteJSONError(err error) {
+	_, _ = fmt.Fprintln(stdout, marshalJSONToString(jsonErrorOutput{Err: err.Error()}))
+}
+
+func marshalJSONToString(input any) string {
+	bytes, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Sprintf("{\"error\":%q}", err.Error())
+	}
+
+	return string(bytes)
+}
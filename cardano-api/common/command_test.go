@@ -0,0 +1,140 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+type testCommandResult struct {
+	TxHash string `json:"txHash"`
+}
+
+func (r testCommandResult) GetOutput() string {
+	return FormatKV([]string{"Tx Hash|" + r.TxHash})
+}
+
+// captureOutput replaces stdout, stderr and exit of the formatters and returns what has been written
+func captureOutput(t *testing.T, write func()) (string, string, int) {
+	t.Helper()
+
+	var outBuf, errBuf bytes.Buffer
+
+	exitCode := 0
+	oldStdout, oldStderr, oldExit := stdout, stderr, osExit
+	stdout, stderr, osExit = &outBuf, &errBuf, func(code int) { exitCode = code }
+
+	defer func() { stdout, stderr, osExit = oldStdout, oldStderr, oldExit }()
+
+	write()
+
+	return outBuf.String(), errBuf.String(), exitCode
+}
+
+func newTestOutputter(t *testing.T, format string) OutputFormatter {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+	RegisterOutputFlag(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--" + OutputFlag, format}))
+	require.NoError(t, ValidateOutputFlag(cmd))
+
+	return InitializeOutputter(cmd)
+}
+
+func TestJSONOutput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		result   ICommandResult
+		err      error
+		stdout   string
+		exitCode int
+	}{
+		{
+			name:   "result",
+			result: testCommandResult{TxHash: "abc"},
+			stdout: `{"txHash":"abc"}` + "\n",
+		},
+		{
+			name:   "no result",
+			stdout: "{}\n",
+		},
+		{
+			name:     "error",
+			err:      errors.New("failed"),
+			stdout:   `{"error":"failed"}` + "\n",
+			exitCode: 1,
+		},
+		{
+			name:     "error with partial result",
+			result:   testCommandResult{TxHash: "abc"},
+			err:      errors.New("failed"),
+			stdout:   `{"error":"failed","result":{"txHash":"abc"}}` + "\n",
+			exitCode: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputter := newTestOutputter(t, OutputFormatJSON)
+
+			if tc.result != nil {
+				outputter.SetCommandResult(tc.result)
+			}
+
+			if tc.err != nil {
+				outputter.SetError(tc.err)
+			}
+
+			out, errOut, exitCode := captureOutput(t, outputter.WriteOutput)
+
+			require.Equal(t, tc.stdout, out)
+			require.Empty(t, errOut)
+			require.Equal(t, tc.exitCode, exitCode)
+		})
+	}
+
+	t.Run("error before command", func(t *testing.T) {
+		out, _, _ := captureOutput(t, func() { WriteJSONError(errors.New("unknown flag")) })
+
+		require.Equal(t, `{"error":"unknown flag"}`+"\n", out)
+	})
+}
+
+func TestTextOutput(t *testing.T) {
+	outputter := newTestOutputter(t, OutputFormatText)
+
+	outputter.SetCommandResult(testCommandResult{TxHash: "abc"})
+
+	out, errOut, exitCode := captureOutput(t, outputter.WriteOutput)
+
+	require.Equal(t, "Tx Hash = abc\n", out)
+	require.Empty(t, errOut)
+	require.Equal(t, 0, exitCode)
+
+	outputter.SetError(errors.New("failed"))
+
+	out, errOut, exitCode = captureOutput(t, outputter.WriteOutput)
+
+	require.Equal(t, "Tx Hash = abc\n", out)
+	require.Equal(t, "failed\n", errOut)
+	require.Equal(t, 1, exitCode)
+}
+
+func TestValidateOutputFlag(t *testing.T) {
+	cmd := &cobra.Command{}
+	RegisterOutputFlag(cmd)
+
+	require.NoError(t, cmd.ParseFlags(nil))
+	require.NoError(t, ValidateOutputFlag(cmd))
+	require.Equal(t, OutputFormatText, GetOutputFormat(cmd))
+
+	require.NoError(t, cmd.ParseFlags([]string{"--" + OutputFlag, "JSON"}))
+	require.Equal(t, OutputFormatJSON, GetOutputFormat(cmd))
+
+	require.NoError(t, cmd.ParseFlags([]string{"--" + OutputFlag, "yaml"}))
+	require.ErrorContains(t, ValidateOutputFlag(cmd), "invalid output: yaml")
+}
@@ -9,3 +9,14 @@ type ValidatorChangeTracker interface {
 	SetValidatorChangeStatus(inProgress bool)
 	IsValidatorChangeInProgress() bool
 }
+
+// ValidatorChangeNotifier is notified when validator change starts or completes
+type ValidatorChangeNotifier interface {
+	NotifyValidatorChange(inProgress bool)
+}
+
+// ConfigChangeNotifier is notified when the settings or multisig addresses are fetched from the oracle
+type ConfigChangeNotifier interface {
+	NotifySettingsChanged()
+	NotifyMultisigAddressesChanged()
+}
@@ -2,7 +2,9 @@ package common
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/fxamacker/cbor/v2"
 )
@@ -10,6 +12,9 @@ import (
 type BridgingTxType string
 type MetadataEncodingType string
 
+// MetadataVersion is the version of the bridging metadata format. Metadata without the version key is version 1
+type MetadataVersion uint8
+
 const (
 	BridgingTxTypeBridgingRequest BridgingTxType = "bridge"
 	BridgingTxTypeBatchExecution  BridgingTxType = "batch"
@@ -19,10 +24,36 @@ const (
 	MetadataEncodingTypeCbor MetadataEncodingType = "cbor"
 
 	MetadataMapKey = 1
+	// MetadataStringMaxLength is the maximal length of the metadata string in bytes,
+	// longer strings (addresses) are split into chunks
+	MetadataStringMaxLength = 64
+
+	// MetadataVersion1 is the original format without the version key
+	MetadataVersion1 MetadataVersion = 1
+	// MetadataVersion2 contains the version key and the optional refund address of the bridging request
+	MetadataVersion2 MetadataVersion = 2
+	// LatestMetadataVersion is the newest version which can be encoded and decoded
+	LatestMetadataVersion = MetadataVersion2
+)
+
+var (
+	ErrTxWithoutMetadata          = errors.New("transaction does not contain metadata")
+	ErrUnsupportedMetadataVersion = errors.New("unsupported metadata version")
+	ErrRefundAddrNotSupported     = errors.New("refund address requires metadata version 2")
 )
 
 type BaseMetadata struct {
-	BridgingTxType BridgingTxType `cbor:"t" json:"t"`
+	BridgingTxType BridgingTxType  `cbor:"t" json:"t"`
+	Version        MetadataVersion `cbor:"v,omitempty" json:"v,omitempty"`
+}
+
+// GetVersion returns version 1 for the metadata without the version key
+func (m BaseMetadata) GetVersion() MetadataVersion {
+	if m.Version == 0 {
+		return MetadataVersion1
+	}
+
+	return m.Version
 }
 
 type BridgingRequestMetadataTransaction struct {
@@ -36,6 +67,10 @@ type BridgingRequestMetadata struct {
 	SenderAddr         []string                             `cbor:"s" json:"s"`
 	Transactions       []BridgingRequestMetadataTransaction `cbor:"tx" json:"tx"`
 	FeeAmount          uint64                               `cbor:"fa" json:"fa"`
+	Version            MetadataVersion                      `cbor:"v,omitempty" json:"v,omitempty"`
+	// RefundAddr is the address on the source chain which receives the funds if the request is refunded,
+	// instead of the sender (since version 2)
+	RefundAddr []string `cbor:"ra,omitempty" json:"ra,omitempty"`
 }
 
 type BatchExecutedMetadata struct {
@@ -93,6 +128,27 @@ func MarshalMetadata[
 	return result, nil
 }
 
+// MarshalBridgingRequestMetadata encodes the bridging request metadata in the given version.
+// Version key is not written for version 1, so the metadata stays readable by the oracles which predate versioning
+func MarshalBridgingRequestMetadata(
+	encodingType MetadataEncodingType, version MetadataVersion, metadata BridgingRequestMetadata,
+) ([]byte, error) {
+	switch version {
+	case MetadataVersion1:
+		if len(metadata.RefundAddr) > 0 {
+			return nil, ErrRefundAddrNotSupported
+		}
+
+		metadata.Version = 0
+	case MetadataVersion2:
+		metadata.Version = version
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedMetadataVersion, version)
+	}
+
+	return MarshalMetadata(encodingType, metadata)
+}
+
 func UnmarshalMetadata[
 	T BaseMetadata | BridgingRequestMetadata | BatchExecutedMetadata | RefundExecutedMetadata,
 ](
@@ -127,3 +183,123 @@ func UnmarshalMetadata[
 
 	return nil, nil
 }
+
+// JoinAddrChunks joins address which has been split into chunks because of the metadata string length limit.
+// Evm addresses are stored without 0x prefix so it is added back for the evm chains
+func JoinAddrChunks(chunks []string, chainType int) string {
+	addr := strings.Join(chunks, "")
+
+	if chainType == ChainTypeEVM && addr != "" && !strings.HasPrefix(addr, "0x") {
+		return "0x" + addr
+	}
+
+	return addr
+}
+
+// DecodedMetadata holds bridging metadata of a transaction. Only the field matching BridgingTxType is set
+type DecodedMetadata struct {
+	BridgingTxType  BridgingTxType
+	Version         MetadataVersion
+	BridgingRequest *BridgingRequestMetadata
+	BatchExecuted   *BatchExecutedMetadata
+	RefundExecuted  *RefundExecutedMetadata
+}
+
+type metadataDecoder = func(metadataRaw []byte, result *DecodedMetadata) (err error)
+
+// metadataDecoders contains the decoders of every supported version by the metadata type
+var metadataDecoders = map[MetadataVersion]map[BridgingTxType]metadataDecoder{
+	MetadataVersion1: {
+		BridgingTxTypeBridgingRequest: decodeBridgingRequestV1,
+		BridgingTxTypeBatchExecution:  decodeBatchExecuted,
+		BridgingTxTypeRefundExecution: decodeRefundExecuted,
+	},
+	// version 2 changes only the bridging request
+	MetadataVersion2: {
+		BridgingTxTypeBridgingRequest: decodeBridgingRequestV2,
+		BridgingTxTypeBatchExecution:  decodeBatchExecuted,
+		BridgingTxTypeRefundExecution: decodeRefundExecuted,
+	},
+}
+
+// decodeBridgingRequestV1 decodes the bridging request without the version key, which can not have the refund address
+func decodeBridgingRequestV1(metadataRaw []byte, result *DecodedMetadata) error {
+	metadata, err := UnmarshalMetadata[BridgingRequestMetadata](MetadataEncodingTypeCbor, metadataRaw)
+	if err != nil {
+		return err
+	}
+
+	if len(metadata.RefundAddr) > 0 {
+		return ErrRefundAddrNotSupported
+	}
+
+	result.BridgingRequest = metadata
+
+	return nil
+}
+
+// decodeBridgingRequestV2 decodes the bridging request with the version key and the optional refund address
+func decodeBridgingRequestV2(metadataRaw []byte, result *DecodedMetadata) error {
+	metadata, err := UnmarshalMetadata[BridgingRequestMetadata](MetadataEncodingTypeCbor, metadataRaw)
+	if err != nil {
+		return err
+	}
+
+	if metadata.Version != MetadataVersion2 {
+		return fmt.Errorf("invalid version %d of the bridging request metadata", metadata.Version)
+	}
+
+	for i, chunk := range metadata.RefundAddr {
+		if chunk == "" || len(chunk) > MetadataStringMaxLength {
+			return fmt.Errorf("invalid refund address chunk %d of length %d", i, len(chunk))
+		}
+	}
+
+	result.BridgingRequest = metadata
+
+	return nil
+}
+
+func decodeBatchExecuted(metadataRaw []byte, result *DecodedMetadata) (err error) {
+	result.BatchExecuted, err = UnmarshalMetadata[BatchExecutedMetadata](MetadataEncodingTypeCbor, metadataRaw)
+
+	return err
+}
+
+func decodeRefundExecuted(metadataRaw []byte, result *DecodedMetadata) (err error) {
+	result.RefundExecuted, err = UnmarshalMetadata[RefundExecutedMetadata](MetadataEncodingTypeCbor, metadataRaw)
+
+	return err
+}
+
+// DecodeMetadata decodes cbor metadata of the transaction according to its version and type.
+// metadataRaw is in the same shape UnmarshalMetadata expects
+func DecodeMetadata(metadataRaw []byte) (*DecodedMetadata, error) {
+	baseMetadata, err := UnmarshalMetadata[BaseMetadata](MetadataEncodingTypeCbor, metadataRaw)
+	if err != nil {
+		return nil, err
+	} else if baseMetadata == nil {
+		return nil, ErrTxWithoutMetadata
+	}
+
+	result := &DecodedMetadata{
+		BridgingTxType: baseMetadata.BridgingTxType,
+		Version:        baseMetadata.GetVersion(),
+	}
+
+	decoders, exists := metadataDecoders[result.Version]
+	if !exists {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedMetadataVersion, result.Version)
+	}
+
+	decoder, exists := decoders[result.BridgingTxType]
+	if !exists {
+		return nil, fmt.Errorf("unknown metadata type: %s", result.BridgingTxType)
+	}
+
+	if err := decoder(metadataRaw, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,524 @@
+package common
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCardanoAddr = "addr_test1qz2fxv2umyhttkxyxp8x0dlpdt3k6cwng5pxj3jhsydzer3jcu5d8ps7zex2k2xt3uqxgjqnnj83ws8lhrn648jjxtwq2ytjqp"
+	testEvmAddr     = "4bc7dA11fE9a4F9AEc2b5bE3Ddfb9d0C1Cf8C0A1"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "overwrite golden metadata fixtures")
+
+type metadataTypes interface {
+	BaseMetadata | BridgingRequestMetadata | BatchExecutedMetadata | RefundExecutedMetadata
+}
+
+var metadataEncodings = []MetadataEncodingType{MetadataEncodingTypeJSON, MetadataEncodingTypeCbor}
+
+// wrapTxMetadata puts the marshaled metadata under the label in the same shape
+// the metadata of the transaction is passed to UnmarshalMetadata (see cardanotx.GetTxMetadata)
+func wrapTxMetadata(t *testing.T, encodingType MetadataEncodingType, data []byte) []byte {
+	t.Helper()
+
+	var (
+		wrapped []byte
+		err     error
+	)
+
+	if encodingType == MetadataEncodingTypeJSON {
+		wrapped, err = json.Marshal(map[int]json.RawMessage{0: data})
+	} else {
+		wrapped, err = cbor.Marshal(map[int]cbor.RawMessage{0: data})
+	}
+
+	require.NoError(t, err)
+
+	return wrapped
+}
+
+func requireMetadataRoundTrip[T metadataTypes](t *testing.T, metadata T) {
+	t.Helper()
+
+	for _, encodingType := range metadataEncodings {
+		data, err := MarshalMetadata(encodingType, metadata)
+		require.NoError(t, err)
+
+		decoded, err := UnmarshalMetadata[T](encodingType, wrapTxMetadata(t, encodingType, data))
+		require.NoError(t, err)
+		require.NotNil(t, decoded)
+		require.Equal(t, metadata, *decoded, encodingType)
+	}
+}
+
+// requireMetadataStringsLength checks every text string of the cbor encoded metadata
+func requireMetadataStringsLength(t *testing.T, data []byte) {
+	t.Helper()
+
+	var (
+		decoded any
+		walk    func(v any)
+	)
+
+	require.NoError(t, cbor.Unmarshal(data, &decoded))
+
+	walk = func(v any) {
+		switch x := v.(type) {
+		case string:
+			require.LessOrEqual(t, len(x), MetadataStringMaxLength, x)
+		case []any:
+			for _, item := range x {
+				walk(item)
+			}
+		case map[any]any:
+			for key, value := range x {
+				walk(key)
+				walk(value)
+			}
+		}
+	}
+
+	walk(decoded)
+}
+
+func newTestBridgingRequestMetadata(
+	destinationChainID string, senderAddr string, receiverAddr string, amount uint64, feeAmount uint64,
+) BridgingRequestMetadata {
+	return BridgingRequestMetadata{
+		BridgingTxType:     BridgingTxTypeBridgingRequest,
+		DestinationChainID: destinationChainID,
+		SenderAddr:         SplitString(senderAddr, MetadataStringMaxLength),
+		Transactions: []BridgingRequestMetadataTransaction{
+			{Address: SplitString(receiverAddr, MetadataStringMaxLength), Amount: amount},
+		},
+		FeeAmount: feeAmount,
+	}
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	requireMetadataRoundTrip(t, BaseMetadata{BridgingTxType: BridgingTxTypeBatchExecution})
+	requireMetadataRoundTrip(t, newTestBridgingRequestMetadata(
+		ChainIDStrNexus, testCardanoAddr, testEvmAddr, 1_000_000, 1_100_000))
+	requireMetadataRoundTrip(t, BridgingRequestMetadata{BridgingTxType: BridgingTxTypeBridgingRequest})
+	requireMetadataRoundTrip(t, BatchExecutedMetadata{BridgingTxType: BridgingTxTypeBatchExecution, BatchNonceID: 7})
+	requireMetadataRoundTrip(t, RefundExecutedMetadata{BridgingTxType: BridgingTxTypeRefundExecution})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		_, err := MarshalMetadata(MetadataEncodingType("xml"), BaseMetadata{})
+		require.Error(t, err)
+
+		_, err = UnmarshalMetadata[BaseMetadata](MetadataEncodingType("xml"), []byte{})
+		require.Error(t, err)
+	})
+
+	t.Run("missing metadata key", func(t *testing.T) {
+		data, err := cbor.Marshal(map[int]map[int]BaseMetadata{0: {2: {BridgingTxType: "bridge"}}})
+		require.NoError(t, err)
+
+		metadata, err := UnmarshalMetadata[BaseMetadata](MetadataEncodingTypeCbor, data)
+		require.NoError(t, err)
+		require.Nil(t, metadata)
+	})
+}
+
+func TestSplitStringProperties(t *testing.T) {
+	property := func(s string, maxLength uint8) bool {
+		mxlen := int(maxLength)%MetadataStringMaxLength + 1
+		chunks := SplitString(s, mxlen)
+
+		for _, chunk := range chunks {
+			if len(chunk) > mxlen || len(chunk) == 0 {
+				return false
+			}
+
+			// characters are not split if they fit into the chunk
+			if mxlen >= utf8.UTFMax && !utf8.ValidString(chunk) {
+				return false
+			}
+		}
+
+		return strings.Join(chunks, "") == s
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 1000}))
+}
+
+func TestSplitString(t *testing.T) {
+	require.Nil(t, SplitString("", 4))
+	require.Equal(t, []string{"abcd", "efgh", "i"}, SplitString("abcdefghi", 4))
+	require.Equal(t, []string{"abč", "ćd"}, SplitString("abčćd", 4))
+	require.Equal(t, []string{"ab", "č", "ćd"}, SplitString("abčćd", 3))
+	require.Equal(t, []string{"\xe2\x82", "\xac"}, SplitString("€", 2))
+}
+
+func TestJoinAddrChunks(t *testing.T) {
+	chunks := SplitString(testEvmAddr, 16)
+
+	require.Equal(t, "0x"+testEvmAddr, JoinAddrChunks(chunks, ChainTypeEVM))
+	require.Equal(t, "0x"+testEvmAddr, JoinAddrChunks([]string{"0x" + testEvmAddr}, ChainTypeEVM))
+	// hex string of the evm address length is not an evm address on the cardano chain
+	require.Equal(t, testEvmAddr, JoinAddrChunks(chunks, ChainTypeCardano))
+	require.Equal(t, "", JoinAddrChunks(nil, ChainTypeEVM))
+	require.Equal(t, ChainTypeEVM, GetChainType(ChainIDStrNexus))
+	require.Equal(t, ChainTypeCardano, GetChainType(ChainIDStrVector))
+}
+
+func TestBridgingRequestMetadataStringsProperties(t *testing.T) {
+	property := func(senderAddr string, receiverAddr string, amount uint64, feeAmount uint64) bool {
+		metadata := newTestBridgingRequestMetadata(ChainIDStrVector, senderAddr, receiverAddr, amount, feeAmount)
+
+		data, err := MarshalMetadata(MetadataEncodingTypeCbor, metadata)
+		if err != nil {
+			return false
+		}
+
+		requireMetadataStringsLength(t, data)
+
+		return strings.Join(metadata.SenderAddr, "") == senderAddr &&
+			strings.Join(metadata.Transactions[0].Address, "") == receiverAddr
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 500}))
+}
+
+func TestMetadataGolden(t *testing.T) {
+	testCases := []struct {
+		name    string
+		marshal func() ([]byte, error)
+	}{
+		{"bridging_request", func() ([]byte, error) {
+			return MarshalMetadata(MetadataEncodingTypeCbor, newTestBridgingRequestMetadata(
+				ChainIDStrVector, testCardanoAddr, testCardanoAddr, 1_000_000, 1_100_000))
+		}},
+		{"bridging_request_evm", func() ([]byte, error) {
+			return MarshalMetadata(MetadataEncodingTypeCbor, newTestBridgingRequestMetadata(
+				ChainIDStrNexus, testCardanoAddr, testEvmAddr, 2_500_000, 1_000_000))
+		}},
+		{"bridging_request_v2", func() ([]byte, error) {
+			return MarshalBridgingRequestMetadata(MetadataEncodingTypeCbor, MetadataVersion2,
+				newTestBridgingRequestMetadata(ChainIDStrVector, testCardanoAddr, testCardanoAddr, 1_000_000, 1_100_000))
+		}},
+		{"batch_executed", func() ([]byte, error) {
+			return MarshalMetadata(MetadataEncodingTypeCbor, BatchExecutedMetadata{
+				BridgingTxType: BridgingTxTypeBatchExecution, BatchNonceID: 42,
+			})
+		}},
+		{"refund_executed", func() ([]byte, error) {
+			return MarshalMetadata(MetadataEncodingTypeCbor, RefundExecutedMetadata{
+				BridgingTxType: BridgingTxTypeRefundExecution,
+			})
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tc.marshal()
+			require.NoError(t, err)
+
+			requireMetadataStringsLength(t, data)
+
+			path := filepath.Join("testdata", "metadata", tc.name+".cbor.hex")
+
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(path, []byte(hex.EncodeToString(data)+"\n"), 0600))
+			}
+
+			expected, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, strings.TrimSpace(string(expected)), hex.EncodeToString(data))
+		})
+	}
+
+	t.Run("evm address chunks are joined with 0x prefix", func(t *testing.T) {
+		expected, err := os.ReadFile(filepath.Join("testdata", "metadata", "bridging_request_evm.cbor.hex"))
+		require.NoError(t, err)
+
+		data, err := hex.DecodeString(strings.TrimSpace(string(expected)))
+		require.NoError(t, err)
+
+		metadata, err := UnmarshalMetadata[BridgingRequestMetadata](
+			MetadataEncodingTypeCbor, wrapTxMetadata(t, MetadataEncodingTypeCbor, data))
+		require.NoError(t, err)
+		require.Equal(t, ChainIDStrNexus, metadata.DestinationChainID)
+		require.Equal(t, testCardanoAddr, JoinAddrChunks(metadata.SenderAddr, ChainTypeCardano))
+		require.Equal(t, "0x"+testEvmAddr, JoinAddrChunks(metadata.Transactions[0].Address, ChainTypeEVM))
+		require.Equal(t, uint64(1_000_000), metadata.FeeAmount)
+	})
+}
+
+// legacyBridgingRequestMetadata is the bridging request metadata as decoded by the oracles which predate versioning
+type legacyBridgingRequestMetadata struct {
+	BridgingTxType     BridgingTxType                       `cbor:"t" json:"t"`
+	DestinationChainID string                               `cbor:"d" json:"d"`
+	SenderAddr         []string                             `cbor:"s" json:"s"`
+	Transactions       []BridgingRequestMetadataTransaction `cbor:"tx" json:"tx"`
+	FeeAmount          uint64                               `cbor:"fa" json:"fa"`
+}
+
+func TestMetadataVersions(t *testing.T) {
+	metadata := newTestBridgingRequestMetadata(ChainIDStrVector, testCardanoAddr, testCardanoAddr, 1_000_000, 100)
+
+	for _, encodingType := range metadataEncodings {
+		t.Run(string(encodingType), func(t *testing.T) {
+			v1, err := MarshalBridgingRequestMetadata(encodingType, MetadataVersion1, metadata)
+			require.NoError(t, err)
+
+			v2, err := MarshalBridgingRequestMetadata(encodingType, MetadataVersion2, metadata)
+			require.NoError(t, err)
+
+			// version 1 is the format created before versioning
+			unversioned, err := MarshalMetadata(encodingType, metadata)
+			require.NoError(t, err)
+			require.Equal(t, unversioned, v1)
+
+			for _, tc := range []struct {
+				data    []byte
+				version MetadataVersion
+			}{
+				{v1, MetadataVersion1},
+				{v2, MetadataVersion2},
+			} {
+				wrapped := wrapTxMetadata(t, encodingType, tc.data)
+
+				base, err := UnmarshalMetadata[BaseMetadata](encodingType, wrapped)
+				require.NoError(t, err)
+				require.Equal(t, tc.version, base.GetVersion())
+
+				decoded, err := UnmarshalMetadata[BridgingRequestMetadata](encodingType, wrapped)
+				require.NoError(t, err)
+				require.Equal(t, metadata.Transactions, decoded.Transactions)
+				require.Equal(t, metadata.SenderAddr, decoded.SenderAddr)
+
+				// oracles which do not know about versions ignore the version key
+				var legacy map[int]map[int]legacyBridgingRequestMetadata
+
+				unmarshal, err := getUnmarshalFunc(encodingType)
+				require.NoError(t, err)
+				require.NoError(t, unmarshal(wrapped, &legacy))
+				require.Equal(t, legacyBridgingRequestMetadata{
+					BridgingTxType:     metadata.BridgingTxType,
+					DestinationChainID: metadata.DestinationChainID,
+					SenderAddr:         metadata.SenderAddr,
+					Transactions:       metadata.Transactions,
+					FeeAmount:          metadata.FeeAmount,
+				}, legacy[0][MetadataMapKey])
+			}
+
+			_, err = MarshalBridgingRequestMetadata(encodingType, MetadataVersion(3), metadata)
+			require.ErrorIs(t, err, ErrUnsupportedMetadataVersion)
+		})
+	}
+
+	t.Run("decode metadata", func(t *testing.T) {
+		for _, version := range []MetadataVersion{MetadataVersion1, MetadataVersion2} {
+			metadataRaw, err := MarshalBridgingRequestMetadata(MetadataEncodingTypeCbor, version, metadata)
+			require.NoError(t, err)
+
+			decoded, err := DecodeMetadata(wrapTxMetadata(t, MetadataEncodingTypeCbor, metadataRaw))
+			require.NoError(t, err)
+			require.Equal(t, version, decoded.Version)
+			require.Equal(t, BridgingTxTypeBridgingRequest, decoded.BridgingTxType)
+			require.Equal(t, metadata.FeeAmount, decoded.BridgingRequest.FeeAmount)
+		}
+
+		metadataRaw, err := MarshalMetadata(MetadataEncodingTypeCbor, BatchExecutedMetadata{
+			BridgingTxType: BridgingTxTypeBatchExecution, BatchNonceID: 3,
+		})
+		require.NoError(t, err)
+
+		decoded, err := DecodeMetadata(wrapTxMetadata(t, MetadataEncodingTypeCbor, metadataRaw))
+		require.NoError(t, err)
+		require.Equal(t, MetadataVersion1, decoded.Version)
+		require.Equal(t, uint64(3), decoded.BatchExecuted.BatchNonceID)
+
+		metadataRaw, err = MarshalMetadata(MetadataEncodingTypeCbor, BaseMetadata{
+			BridgingTxType: BridgingTxTypeBridgingRequest, Version: 3,
+		})
+		require.NoError(t, err)
+
+		_, err = DecodeMetadata(wrapTxMetadata(t, MetadataEncodingTypeCbor, metadataRaw))
+		require.ErrorIs(t, err, ErrUnsupportedMetadataVersion)
+
+		metadataRaw, err = MarshalMetadata(MetadataEncodingTypeCbor, BaseMetadata{BridgingTxType: "unknown"})
+		require.NoError(t, err)
+
+		_, err = DecodeMetadata(wrapTxMetadata(t, MetadataEncodingTypeCbor, metadataRaw))
+		require.ErrorContains(t, err, "unknown metadata type")
+	})
+
+	t.Run("refund address", func(t *testing.T) {
+		withRefund := metadata
+		withRefund.RefundAddr = SplitString(testCardanoAddr, MetadataStringMaxLength)
+
+		for _, encodingType := range metadataEncodings {
+			_, err := MarshalBridgingRequestMetadata(encodingType, MetadataVersion1, withRefund)
+			require.ErrorIs(t, err, ErrRefundAddrNotSupported)
+
+			v2, err := MarshalBridgingRequestMetadata(encodingType, MetadataVersion2, withRefund)
+			require.NoError(t, err)
+
+			decoded, err := UnmarshalMetadata[BridgingRequestMetadata](encodingType, wrapTxMetadata(t, encodingType, v2))
+			require.NoError(t, err)
+			require.Equal(t, testCardanoAddr, JoinAddrChunks(decoded.RefundAddr, ChainTypeCardano))
+		}
+
+		metadataRaw, err := MarshalBridgingRequestMetadata(MetadataEncodingTypeCbor, MetadataVersion2, withRefund)
+		require.NoError(t, err)
+
+		decoded, err := DecodeMetadata(wrapTxMetadata(t, MetadataEncodingTypeCbor, metadataRaw))
+		require.NoError(t, err)
+		require.Equal(t, withRefund.RefundAddr, decoded.BridgingRequest.RefundAddr)
+	})
+
+	t.Run("version decoders", func(t *testing.T) {
+		withRefund := metadata
+		withRefund.RefundAddr = SplitString(testCardanoAddr, MetadataStringMaxLength)
+
+		decode := func(t *testing.T, version MetadataVersion, metadata BridgingRequestMetadata) *DecodedMetadata {
+			t.Helper()
+
+			metadataRaw, err := MarshalBridgingRequestMetadata(MetadataEncodingTypeCbor, version, metadata)
+			require.NoError(t, err)
+
+			decoded, err := DecodeMetadata(wrapTxMetadata(t, MetadataEncodingTypeCbor, metadataRaw))
+			require.NoError(t, err)
+
+			return decoded
+		}
+
+		// version 1 is still decoded by its own decoder
+		decoded := decode(t, MetadataVersion1, metadata)
+		require.Equal(t, MetadataVersion1, decoded.Version)
+		require.Equal(t, &metadata, decoded.BridgingRequest)
+
+		// version 2 is decoded together with its version key and the refund address
+		decoded = decode(t, MetadataVersion2, withRefund)
+		expected := withRefund
+		expected.Version = MetadataVersion2
+
+		require.Equal(t, MetadataVersion2, decoded.Version)
+		require.Equal(t, &expected, decoded.BridgingRequest)
+
+		decoded = decode(t, MetadataVersion2, metadata)
+		require.Nil(t, decoded.BridgingRequest.RefundAddr)
+
+		v1Raw, err := MarshalBridgingRequestMetadata(MetadataEncodingTypeCbor, MetadataVersion1, metadata)
+		require.NoError(t, err)
+		require.ErrorContains(t,
+			decodeBridgingRequestV2(wrapTxMetadata(t, MetadataEncodingTypeCbor, v1Raw), &DecodedMetadata{}),
+			"invalid version 0")
+
+		for _, tc := range []struct {
+			name     string
+			metadata BridgingRequestMetadata
+			err      string
+		}{
+			{
+				name:     "refund address without version",
+				metadata: withRefund,
+				err:      ErrRefundAddrNotSupported.Error(),
+			},
+			{
+				name: "too long refund address chunk",
+				metadata: BridgingRequestMetadata{
+					BridgingTxType: BridgingTxTypeBridgingRequest,
+					Version:        MetadataVersion2,
+					RefundAddr:     []string{strings.Repeat("a", MetadataStringMaxLength+1)},
+				},
+				err: "invalid refund address chunk 0",
+			},
+			{
+				name: "empty refund address chunk",
+				metadata: BridgingRequestMetadata{
+					BridgingTxType: BridgingTxTypeBridgingRequest,
+					Version:        MetadataVersion2,
+					RefundAddr:     []string{"addr", ""},
+				},
+				err: "invalid refund address chunk 1",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				metadataRaw, err := MarshalMetadata(MetadataEncodingTypeCbor, tc.metadata)
+				require.NoError(t, err)
+
+				_, err = DecodeMetadata(wrapTxMetadata(t, MetadataEncodingTypeCbor, metadataRaw))
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+}
+
+func FuzzBridgingRequestMetadataRoundTrip(f *testing.F) {
+	f.Add(ChainIDStrVector, testCardanoAddr, testCardanoAddr, uint64(1_000_000), uint64(1_100_000))
+	f.Add(ChainIDStrNexus, testCardanoAddr, testEvmAddr, uint64(0), uint64(^uint64(0)))
+	f.Add("", "", "", uint64(1), uint64(0))
+
+	f.Fuzz(func(t *testing.T, destinationChainID, senderAddr, receiverAddr string, amount, feeAmount uint64) {
+		// metadata strings are utf-8 and json encoding replaces invalid characters
+		if !utf8.ValidString(destinationChainID) || !utf8.ValidString(senderAddr) || !utf8.ValidString(receiverAddr) {
+			t.Skip()
+		}
+
+		metadata := newTestBridgingRequestMetadata(destinationChainID, senderAddr, receiverAddr, amount, feeAmount)
+
+		requireMetadataRoundTrip(t, metadata)
+
+		for _, chunk := range append(metadata.SenderAddr, metadata.Transactions[0].Address...) {
+			require.LessOrEqual(t, len(chunk), MetadataStringMaxLength)
+		}
+	})
+}
+
+func FuzzBatchAndRefundMetadataRoundTrip(f *testing.F) {
+	f.Add(string(BridgingTxTypeBatchExecution), uint64(1))
+
+	f.Fuzz(func(t *testing.T, txType string, batchNonceID uint64) {
+		if !utf8.ValidString(txType) {
+			t.Skip()
+		}
+
+		requireMetadataRoundTrip(t, BaseMetadata{BridgingTxType: BridgingTxType(txType)})
+		requireMetadataRoundTrip(t, RefundExecutedMetadata{BridgingTxType: BridgingTxType(txType)})
+		requireMetadataRoundTrip(t, BatchExecutedMetadata{
+			BridgingTxType: BridgingTxType(txType), BatchNonceID: batchNonceID,
+		})
+	})
+}
+
+// FuzzUnmarshalMetadata checks that arbitrary (possibly malicious) metadata does not panic the decoding
+func FuzzUnmarshalMetadata(f *testing.F) {
+	for _, name := range []string{"bridging_request", "batch_executed"} {
+		expected, err := os.ReadFile(filepath.Join("testdata", "metadata", name+".cbor.hex"))
+		require.NoError(f, err)
+
+		data, err := hex.DecodeString(strings.TrimSpace(string(expected)))
+		require.NoError(f, err)
+
+		f.Add(data)
+	}
+
+	f.Add([]byte(`{"0":{"1":{"t":"bridge","d":"vector","s":["addr"],"tx":[{"a":["addr"],"m":1}],"fa":2}}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, encodingType := range metadataEncodings {
+			_, _ = UnmarshalMetadata[BaseMetadata](encodingType, data)
+			_, _ = UnmarshalMetadata[BridgingRequestMetadata](encodingType, data)
+			_, _ = UnmarshalMetadata[BatchExecutedMetadata](encodingType, data)
+			_, _ = UnmarshalMetadata[RefundExecutedMetadata](encodingType, data)
+		}
+
+		_, _ = DecodeMetadata(data)
+	})
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+type requestContextKey string
+
+const (
+	apiKeyIDContextKey    requestContextKey = "apiKeyID"
+	requestIDContextKey   requestContextKey = "requestID"
+	requestInfoContextKey requestContextKey = "requestInfo"
+
+	apiKeyIDLength = 8
+)
+
+// RequestInfo is filled out while the request is handled and logged in the access log line when it is finished
+type RequestInfo struct {
+	APIKeyID           string
+	SourceChainID      string
+	DestinationChainID string
+}
+
+// GetAPIKeyID returns non secret identifier of the api key which can be logged or shown to admins
+func GetAPIKeyID(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+
+	return hex.EncodeToString(hash[:apiKeyIDLength])
+}
+
+// NewRequestID generates random request id
+func NewRequestID() string {
+	bytes := make([]byte, 16)
+	_, _ = rand.Read(bytes)
+
+	return hex.EncodeToString(bytes)
+}
+
+func ContextWithAPIKeyID(ctx context.Context, apiKeyID string) context.Context {
+	if info := RequestInfoFromContext(ctx); info != nil {
+		info.APIKeyID = apiKeyID
+	}
+
+	return context.WithValue(ctx, apiKeyIDContextKey, apiKeyID)
+}
+
+func APIKeyIDFromContext(ctx context.Context) string {
+	value, _ := ctx.Value(apiKeyIDContextKey).(string)
+
+	return value
+}
+
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	value, _ := ctx.Value(requestIDContextKey).(string)
+
+	return value
+}
+
+func ContextWithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoContextKey, info)
+}
+
+// RequestInfoFromContext returns nil if the request info is not set (request is not handled by the api)
+func RequestInfoFromContext(ctx context.Context) *RequestInfo {
+	value, _ := ctx.Value(requestInfoContextKey).(*RequestInfo)
+
+	return value
+}
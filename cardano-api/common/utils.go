@@ -15,10 +15,13 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/Ethernal-Tech/cardano-api/telemetry"
 	"github.com/Ethernal-Tech/cardano-infrastructure/indexer"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/sethvargo/go-retry"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -113,15 +116,22 @@ func IsContextDoneErr(err error) bool {
 	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
 }
 
-// SplitString splits large string into slice of substrings
+// SplitString splits large string into slice of substrings of at most mxlen bytes.
+// Multi-byte characters are not split, unless mxlen is shorter than the character
 func SplitString(s string, mxlen int) (res []string) {
-	for i := 0; i < len(s); i += mxlen {
-		end := i + mxlen
-		if end > len(s) {
-			end = len(s)
+	for i := 0; i < len(s); {
+		end := min(i+mxlen, len(s))
+
+		for boundary := end; boundary > i && boundary < len(s); boundary-- {
+			if utf8.RuneStart(s[boundary]) {
+				end = boundary
+
+				break
+			}
 		}
 
 		res = append(res, s[i:end])
+		i = end
 	}
 
 	return res
@@ -195,13 +205,25 @@ func WeiToDfm(wei *big.Int) *big.Int {
 }
 
 func executeHTTPCall[TResponse any](req *http.Request, apiKey string) (t TResponse, err error) {
+	req, span := telemetry.StartHTTPClientSpan(req)
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-KEY", apiKey)
+
+	if apiKey != "" {
+		req.Header.Set("X-API-KEY", apiKey)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return t, err
-	} else if resp.StatusCode != http.StatusOK {
+	}
+
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
 		return t, fmt.Errorf("http status for %s code is %d", req.URL.String(), resp.StatusCode)
 	}
 
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/oracle"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFeeQuoteTimeout(t *testing.T) {
+	config := &AppConfig{UtxoCacheTimeout: time.Minute}
+
+	require.NoError(t, config.ValidateFeeQuoteTimeout())
+
+	config.FeeQuoteTimeout = time.Minute
+	require.NoError(t, config.ValidateFeeQuoteTimeout())
+
+	config.FeeQuoteTimeout = time.Minute + time.Second
+	require.ErrorContains(t, config.ValidateFeeQuoteTimeout(), "greater than utxoCacheTimeout")
+
+	config.FeeQuoteTimeout = -time.Second
+	require.Error(t, config.ValidateFeeQuoteTimeout())
+}
+
+func TestFillOutMetadataVersion(t *testing.T) {
+	fillOut := func(t *testing.T, settings string) *AppConfig {
+		t.Helper()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/Settings/Get", r.URL.Path)
+
+			_, _ = w.Write([]byte(settings))
+		}))
+		defer server.Close()
+
+		config := &AppConfig{
+			OracleAPI: oracle.Config{URL: server.URL, Timeout: time.Second, MaxAttempts: 1},
+		}
+
+		require.NoError(t, config.FillOut(context.Background(), hclog.NewNullLogger()))
+
+		return config
+	}
+
+	hasVersionKey := func(t *testing.T, config *AppConfig) bool {
+		t.Helper()
+
+		metadataRaw, err := common.MarshalBridgingRequestMetadata(
+			common.MetadataEncodingTypeCbor, config.BridgingSettings.GetMetadataVersion(),
+			common.BridgingRequestMetadata{
+				BridgingTxType:     common.BridgingTxTypeBridgingRequest,
+				DestinationChainID: common.ChainIDStrVector,
+			})
+		require.NoError(t, err)
+
+		var metadata map[int]map[string]any
+
+		require.NoError(t, cbor.Unmarshal(metadataRaw, &metadata))
+
+		_, exists := metadata[common.MetadataMapKey]["v"]
+
+		return exists
+	}
+
+	t.Run("version 2", func(t *testing.T) {
+		config := fillOut(t, `{"minValueToBridge":1000000,"maxAmountAllowedToBridge":"0","metadataVersion":2}`)
+
+		require.Equal(t, common.MetadataVersion2, config.BridgingSettings.MetadataVersion)
+		require.Equal(t, common.MetadataVersion2, config.BridgingSettings.GetMetadataVersion())
+		require.True(t, hasVersionKey(t, config))
+	})
+
+	t.Run("version not advertised", func(t *testing.T) {
+		config := fillOut(t, `{"minValueToBridge":1000000,"maxAmountAllowedToBridge":"0"}`)
+
+		require.Equal(t, common.MetadataVersion1, config.BridgingSettings.GetMetadataVersion())
+		require.False(t, hasVersionKey(t, config))
+	})
+}
+
+type testChangeNotifier struct {
+	settingsChanged atomic.Int32
+}
+
+func (n *testChangeNotifier) NotifySettingsChanged() {
+	n.settingsChanged.Add(1)
+}
+
+func (n *testChangeNotifier) NotifyMultisigAddressesChanged() {}
+
+func TestFetchAndUpdateSettings(t *testing.T) {
+	var (
+		settingsLock sync.Mutex
+		settings     = `{"minValueToBridge":1000000,"maxAmountAllowedToBridge":"0"}`
+	)
+
+	setSettings := func(value string) {
+		settingsLock.Lock()
+		defer settingsLock.Unlock()
+
+		settings = value
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/Settings/Get", r.URL.Path)
+
+		settingsLock.Lock()
+		defer settingsLock.Unlock()
+
+		_, _ = w.Write([]byte(settings))
+	}))
+	defer server.Close()
+
+	notifier := &testChangeNotifier{}
+	config := &AppConfig{
+		OracleAPI: oracle.Config{URL: server.URL, Timeout: time.Second, MaxAttempts: 1},
+	}
+
+	config.SetChangeNotifier(notifier)
+
+	require.NoError(t, config.FillOut(context.Background(), hclog.NewNullLogger()))
+	require.Equal(t, int32(1), notifier.settingsChanged.Load())
+	require.Equal(t, uint64(1_000_000), config.GetBridgingSettings().MinValueToBridge)
+
+	t.Run("not changed", func(t *testing.T) {
+		require.NoError(t, config.FetchAndUpdateSettings(context.Background(), hclog.NewNullLogger()))
+		require.Equal(t, int32(1), notifier.settingsChanged.Load())
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		setSettings(`{"minValueToBridge":2000000,"maxAmountAllowedToBridge":"0"}`)
+
+		require.NoError(t, config.FetchAndUpdateSettings(context.Background(), hclog.NewNullLogger()))
+		require.Equal(t, int32(2), notifier.settingsChanged.Load())
+		require.Equal(t, uint64(2_000_000), config.GetBridgingSettings().MinValueToBridge)
+	})
+
+	t.Run("periodic refresh", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go config.StartSettingsRefresh(ctx, hclog.NewNullLogger(), 10*time.Millisecond)
+
+		setSettings(`{"minValueToBridge":3000000,"maxAmountAllowedToBridge":"0","metadataVersion":2}`)
+
+		require.Eventually(t, func() bool {
+			return config.GetBridgingSettings().MinValueToBridge == 3_000_000
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// settings are fetched many times, but the notifier is called only once for the change
+		time.Sleep(100 * time.Millisecond)
+
+		require.Equal(t, int32(3), notifier.settingsChanged.Load())
+		require.Equal(t, common.MetadataVersion2, config.GetBridgingSettings().GetMetadataVersion())
+	})
+}
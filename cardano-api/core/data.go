@@ -2,6 +2,8 @@ package core
 
 import (
 	"net/http"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
 )
 
 type APIEndpointHandler = func(w http.ResponseWriter, r *http.Request)
@@ -11,6 +13,8 @@ type APIEndpoint struct {
 	Method       string
 	Handler      APIEndpointHandler
 	NoAPIKeyAuth bool
+	// AdminOnly endpoints are accessible only with one of the admin api keys
+	AdminOnly bool
 }
 
 type SettingsResponse struct {
@@ -20,6 +24,8 @@ type SettingsResponse struct {
 	MaxAmountAllowedToBridge       string              `json:"maxAmountAllowedToBridge"`
 	MaxReceiversPerBridgingRequest int                 `json:"maxReceiversPerBridgingRequest"`
 	AllowedDirections              map[string][]string `json:"allowedDirections"`
+	// MetadataVersion is the newest bridging metadata version supported by the oracle, missing for version 1
+	MetadataVersion common.MetadataVersion `json:"metadataVersion"`
 }
 
 type MultiSigAddressesResponse struct {
@@ -0,0 +1,88 @@
+package oracle
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("oracle api circuit breaker is open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker stops calling the oracle after failureThreshold consecutive failures. After openTimeout
+// one trial call is allowed (half-open state) which either closes the circuit or opens it again
+type circuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	now              func() time.Time
+
+	lock     sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+func newCircuitBreaker(failureThreshold int, openTimeout time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		now:              time.Now,
+	}
+}
+
+// allow returns ErrCircuitOpen if the call should not be executed
+func (cb *circuitBreaker) allow() error {
+	if cb.failureThreshold <= 0 {
+		return nil
+	}
+
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.openTimeout {
+			return ErrCircuitOpen
+		}
+
+		cb.state = circuitHalfOpen
+
+		return nil
+	case circuitHalfOpen:
+		// only one trial call at a time
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+func (cb *circuitBreaker) onSuccess() {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	cb.state = circuitClosed
+	cb.failures = 0
+}
+
+func (cb *circuitBreaker) onFailure() {
+	if cb.failureThreshold <= 0 {
+		return
+	}
+
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	cb.failures++
+
+	if cb.state == circuitHalfOpen || cb.failures >= cb.failureThreshold {
+		cb.state = circuitOpen
+		cb.openedAt = cb.now()
+	}
+}
@@ -0,0 +1,247 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultTimeout          = 10 * time.Second
+	defaultMaxAttempts      = 5
+	defaultInitialBackoff   = 500 * time.Millisecond
+	defaultMaxBackoff       = 10 * time.Second
+	defaultBreakerThreshold = 10
+	defaultBreakerTimeout   = 30 * time.Second
+
+	apiKeyHeader        = "X-API-KEY"
+	maxErrorBodyLength  = 512
+	maxResponseBodySize = 10 * 1024 * 1024
+)
+
+type Config struct {
+	URL    string `json:"url"`
+	APIKey string `json:"apiKey"`
+	// Timeout of a single http call
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// MaxAttempts is the number of http calls before the request fails
+	MaxAttempts    int           `json:"maxAttempts,omitempty"`
+	InitialBackoff time.Duration `json:"initialBackoff,omitempty"`
+	MaxBackoff     time.Duration `json:"maxBackoff,omitempty"`
+	// BreakerThreshold is the number of consecutive failed calls which opens the circuit breaker.
+	// Negative value disables the circuit breaker
+	BreakerThreshold int `json:"breakerThreshold,omitempty"`
+	// BreakerTimeout is how long the circuit breaker stays open
+	BreakerTimeout time.Duration `json:"breakerTimeout,omitempty"`
+}
+
+// HTTPError is returned when the oracle responds with status other than 200
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("oracle api %s responded with status %d", e.URL, e.StatusCode)
+	}
+
+	return fmt.Sprintf("oracle api %s responded with status %d: %s", e.URL, e.StatusCode, e.Message)
+}
+
+// isRetryable returns true for server errors and rate limiting
+func (e *HTTPError) isRetryable() bool {
+	return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests
+}
+
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	breaker    *circuitBreaker
+}
+
+func NewClient(config Config) *Client {
+	config = fillOutDefaults(config)
+
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		breaker:    newCircuitBreaker(config.BreakerThreshold, config.BreakerTimeout),
+	}
+}
+
+// Get calls the oracle api endpoint and decodes the json response into the result.
+// Failed calls are retried with exponential backoff until MaxAttempts is reached
+func Get[T any](ctx context.Context, client *Client, path string) (result T, err error) {
+	err = client.do(ctx, http.MethodGet, path, &result)
+
+	return result, err
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, result any) error {
+	requestURL := strings.TrimRight(c.config.URL, "/") + path
+	backoff := c.config.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := c.breaker.allow()
+		if err == nil {
+			err = c.call(ctx, method, requestURL, result)
+			if err == nil {
+				c.breaker.onSuccess()
+
+				return nil
+			}
+
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) && !httpErr.isRetryable() {
+				// oracle is up, the request is invalid
+				c.breaker.onSuccess()
+			} else {
+				c.breaker.onFailure()
+			}
+		}
+
+		if !isRetryable(ctx, err) || attempt >= c.config.MaxAttempts {
+			return fmt.Errorf("oracle api call failed after %d attempt(s): %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("oracle api call failed after %d attempt(s): %w", attempt, ctx.Err())
+		case <-time.After(withJitter(backoff)):
+		}
+
+		backoff = min(backoff*2, c.config.MaxBackoff)
+	}
+}
+
+func (c *Client) call(ctx context.Context, method string, requestURL string, result any) (err error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	req, span := telemetry.StartHTTPClientSpan(req)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	req.Header.Set("Accept", "application/json")
+
+	if c.config.APIKey != "" {
+		req.Header.Set(apiKeyHeader, c.config.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &HTTPError{
+			URL:        requestURL,
+			StatusCode: resp.StatusCode,
+			Message:    decodeErrorMessage(body),
+		}
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to decode oracle api response: %w", err)
+	}
+
+	return nil
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.isRetryable()
+	}
+
+	// network errors, timeouts and open circuit breaker (it can be closed until the next attempt)
+	return true
+}
+
+// decodeErrorMessage extracts the error message from the oracle error response (or returns the beginning of the body)
+func decodeErrorMessage(body []byte) string {
+	var errResponse struct {
+		Err     string `json:"err"`
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(body, &errResponse); err == nil {
+		for _, msg := range []string{errResponse.Err, errResponse.Error, errResponse.Message} {
+			if msg != "" {
+				return msg
+			}
+		}
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if len(msg) > maxErrorBodyLength {
+		msg = msg[:maxErrorBodyLength] + "..."
+	}
+
+	return msg
+}
+
+// withJitter returns random duration between half and the whole backoff
+func withJitter(backoff time.Duration) time.Duration {
+	if backoff <= 1 {
+		return backoff
+	}
+
+	half := backoff / 2
+
+	return half + rand.N(backoff-half) //nolint:gosec
+}
+
+func fillOutDefaults(config Config) Config {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaultInitialBackoff
+	}
+
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+
+	if config.BreakerThreshold == 0 {
+		config.BreakerThreshold = defaultBreakerThreshold
+	}
+
+	if config.BreakerTimeout <= 0 {
+		config.BreakerTimeout = defaultBreakerTimeout
+	}
+
+	return config
+}
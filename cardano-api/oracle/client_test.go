@@ -0,0 +1,199 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type settingsResponse struct {
+	MinValueToBridge uint64 `json:"minValueToBridge"`
+}
+
+func newTestClient(url string, apiKey string) *Client {
+	return NewClient(Config{
+		URL:              url,
+		APIKey:           apiKey,
+		Timeout:          time.Second,
+		MaxAttempts:      3,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		BreakerThreshold: 5,
+		BreakerTimeout:   time.Hour,
+	})
+}
+
+func TestClient(t *testing.T) {
+	t.Run("success with api key", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/Settings/Get", r.URL.Path)
+			require.Equal(t, "key", r.Header.Get(apiKeyHeader))
+
+			_, _ = w.Write([]byte(`{"minValueToBridge": 1000000}`))
+		}))
+		defer server.Close()
+
+		res, err := Get[settingsResponse](context.Background(), newTestClient(server.URL+"/", "key"), "/api/Settings/Get")
+		require.NoError(t, err)
+		require.Equal(t, uint64(1_000_000), res.MinValueToBridge)
+	})
+
+	t.Run("api key header is not sent if empty", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, exists := r.Header[http.CanonicalHeaderKey(apiKeyHeader)]
+			require.False(t, exists)
+
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		_, err := Get[settingsResponse](context.Background(), newTestClient(server.URL, ""), "/")
+		require.NoError(t, err)
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		var calls atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"minValueToBridge": 5}`))
+		}))
+		defer server.Close()
+
+		res, err := Get[settingsResponse](context.Background(), newTestClient(server.URL, ""), "/")
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), res.MinValueToBridge)
+		require.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("client errors are not retried and body is decoded", func(t *testing.T) {
+		var calls atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"err": "invalid chain"}`))
+		}))
+		defer server.Close()
+
+		_, err := Get[settingsResponse](context.Background(), newTestClient(server.URL, ""), "/")
+		require.ErrorContains(t, err, "responded with status 400: invalid chain")
+		require.Equal(t, int32(1), calls.Load())
+
+		var httpErr *HTTPError
+
+		require.True(t, errors.As(err, &httpErr))
+		require.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var calls atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("database is down"))
+		}))
+		defer server.Close()
+
+		_, err := Get[settingsResponse](context.Background(), newTestClient(server.URL, ""), "/")
+		require.ErrorContains(t, err, "failed after 3 attempt(s)")
+		require.ErrorContains(t, err, "database is down")
+		require.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		defer server.Close()
+
+		client := newTestClient(server.URL, "")
+		client.httpClient.Timeout = 10 * time.Millisecond
+
+		_, err := Get[settingsResponse](context.Background(), client, "/")
+		require.ErrorContains(t, err, "Client.Timeout exceeded")
+	})
+
+	t.Run("canceled context stops retries", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		ctx, cancelFn := context.WithCancel(context.Background())
+		cancelFn()
+
+		_, err := Get[settingsResponse](ctx, newTestClient(server.URL, ""), "/")
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("circuit breaker", func(t *testing.T) {
+		var calls atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		client := newTestClient(server.URL, "")
+
+		_, err := Get[settingsResponse](context.Background(), client, "/")
+		require.Error(t, err)
+
+		_, err = Get[settingsResponse](context.Background(), client, "/")
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		require.Equal(t, int32(5), calls.Load())
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	cb := newCircuitBreaker(2, time.Minute)
+	cb.now = func() time.Time { return now }
+
+	cb.onFailure()
+	require.NoError(t, cb.allow())
+
+	cb.onFailure()
+	require.ErrorIs(t, cb.allow(), ErrCircuitOpen)
+
+	now = now.Add(time.Minute)
+
+	// half-open allows only one trial call
+	require.NoError(t, cb.allow())
+	require.ErrorIs(t, cb.allow(), ErrCircuitOpen)
+
+	// failed trial call opens the circuit again
+	cb.onFailure()
+	require.ErrorIs(t, cb.allow(), ErrCircuitOpen)
+
+	now = now.Add(time.Minute)
+
+	require.NoError(t, cb.allow())
+	cb.onSuccess()
+	require.NoError(t, cb.allow())
+	require.NoError(t, cb.allow())
+}
+
+func TestDecodeErrorMessage(t *testing.T) {
+	require.Equal(t, "a", decodeErrorMessage([]byte(`{"error": "a"}`)))
+	require.Equal(t, "b", decodeErrorMessage([]byte(`{"message": "b"}`)))
+	require.Equal(t, "plain text", decodeErrorMessage([]byte(" plain text\n")))
+	require.Len(t, decodeErrorMessage(make([]byte, 2*maxErrorBodyLength)), maxErrorBodyLength+3)
+}
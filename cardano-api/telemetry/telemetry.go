@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName         = "github.com/Ethernal-Tech/cardano-api"
+	defaultServiceName = "cardano-api"
+)
+
+type Config struct {
+	// Endpoint of the OTLP/HTTP collector, for example http://localhost:4318. Tracing is disabled if it is empty
+	Endpoint string `json:"endpoint"`
+	// Headers are sent with every export request (for example collector authorization)
+	Headers     map[string]string `json:"headers,omitempty"`
+	ServiceName string            `json:"serviceName"`
+	// SampleRatio is the ratio of traces which are sampled (0 means all of them)
+	SampleRatio float64 `json:"sampleRatio"`
+}
+
+// Init sets up the global tracer provider which exports spans to the OTLP collector.
+// The returned function flushes and stops the exporter
+func Init(ctx context.Context, config Config) (func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithHeaders(config.Headers)}
+
+	if strings.Contains(config.Endpoint, "://") {
+		options = append(options, otlptracehttp.WithEndpointURL(config.Endpoint))
+	} else {
+		options = append(options, otlptracehttp.WithEndpoint(config.Endpoint))
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+	}
+
+	tracerProvider := NewTracerProvider(config, exporter)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tracerProvider.Shutdown, nil
+}
+
+// NewTracerProvider creates tracer provider for the exporter. Tests can use it with an in-memory exporter
+func NewTracerProvider(
+	config Config, exporter sdktrace.SpanExporter, options ...sdktrace.TracerProviderOption,
+) *sdktrace.TracerProvider {
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if config.SampleRatio > 0 && config.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(config.SampleRatio)
+	}
+
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	}, options...)...)
+}
+
+// StartSpan starts span with the global tracer provider (noop if tracing is not initialized)
+func StartSpan(
+	ctx context.Context, name string, attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records the error (if any) and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,123 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type txProviderMock struct {
+	wallet.ITxProvider
+	err error
+}
+
+func (m txProviderMock) GetUtxos(context.Context, string) ([]wallet.Utxo, error) {
+	return []wallet.Utxo{{Hash: "a"}, {Hash: "b"}}, m.err
+}
+
+// setupInMemoryTracing sets global tracer provider with in-memory exporter and returns function for getting spans
+func setupInMemoryTracing(t *testing.T) func() tracetest.SpanStubs {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := NewTracerProvider(Config{ServiceName: "test"}, exporter)
+	prevTracerProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		_ = tracerProvider.Shutdown(context.Background())
+
+		otel.SetTracerProvider(prevTracerProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	return func() tracetest.SpanStubs {
+		require.NoError(t, tracerProvider.ForceFlush(context.Background()))
+
+		return exporter.GetSpans()
+	}
+}
+
+func TestInitWithoutEndpoint(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
+
+func TestSpans(t *testing.T) {
+	getSpans := setupInMemoryTracing(t)
+
+	t.Run("tx provider", func(t *testing.T) {
+		provider := NewTxProviderWithTracing(txProviderMock{}, "ogmios")
+
+		utxos, err := provider.GetUtxos(context.Background(), "addr1")
+		require.NoError(t, err)
+		require.Len(t, utxos, 2)
+
+		provider = NewTxProviderWithTracing(txProviderMock{err: errors.New("provider down")}, "blockfrost")
+
+		_, err = provider.GetUtxos(context.Background(), "addr2")
+		require.Error(t, err)
+
+		spans := getSpans()
+		require.Len(t, spans, 2)
+		require.Equal(t, "txProvider.GetUtxos", spans[0].Name)
+		require.Equal(t, codes.Unset, spans[0].Status.Code)
+		require.Contains(t, spans[0].Attributes, attribute.String("provider", "ogmios"))
+		require.Contains(t, spans[0].Attributes, attribute.Int("utxos", 2))
+		require.Equal(t, codes.Error, spans[1].Status.Code)
+		require.Equal(t, "provider down", spans[1].Status.Description)
+	})
+
+	t.Run("http client and server spans share the trace", func(t *testing.T) {
+		var serverSpanCtx trace.SpanContext
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, span := StartHTTPServerSpan(r, "Test")
+			serverSpanCtx = span.SpanContext()
+
+			EndHTTPServerSpan(span, http.StatusInternalServerError)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		ctx, parentSpan := StartSpan(context.Background(), "parent")
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/path", nil)
+		require.NoError(t, err)
+
+		req, clientSpan := StartHTTPClientSpan(req)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		EndSpan(clientSpan, nil)
+		EndSpan(parentSpan, nil)
+
+		require.Equal(t, parentSpan.SpanContext().TraceID(), serverSpanCtx.TraceID())
+
+		spans := getSpans()[2:]
+		require.Len(t, spans, 3)
+		require.Equal(t, "HTTP GET Test", spans[0].Name)
+		require.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+		require.Equal(t, clientSpan.SpanContext().SpanID(), spans[0].Parent.SpanID())
+		require.Equal(t, codes.Error, spans[0].Status.Code)
+		require.Equal(t, "HTTP GET", spans[1].Name)
+		require.Equal(t, trace.SpanKindClient, spans[1].SpanKind)
+		require.Equal(t, "parent", spans[2].Name)
+	})
+}
@@ -0,0 +1,63 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/Ethernal-Tech/cardano-infrastructure/wallet"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TxProviderWithTracing creates span for every call of the wrapped provider
+type TxProviderWithTracing struct {
+	provider     wallet.ITxProvider
+	providerType string
+}
+
+var _ wallet.ITxProvider = (*TxProviderWithTracing)(nil)
+
+func NewTxProviderWithTracing(provider wallet.ITxProvider, providerType string) *TxProviderWithTracing {
+	return &TxProviderWithTracing{
+		provider:     provider,
+		providerType: providerType,
+	}
+}
+
+func (p *TxProviderWithTracing) SubmitTx(ctx context.Context, txSigned []byte) (err error) {
+	ctx, span := StartSpan(ctx, "txProvider.SubmitTx", p.attrs()...)
+	defer func() { EndSpan(span, err) }()
+
+	return p.provider.SubmitTx(ctx, txSigned)
+}
+
+func (p *TxProviderWithTracing) GetTip(ctx context.Context) (_ wallet.QueryTipData, err error) {
+	ctx, span := StartSpan(ctx, "txProvider.GetTip", p.attrs()...)
+	defer func() { EndSpan(span, err) }()
+
+	return p.provider.GetTip(ctx)
+}
+
+func (p *TxProviderWithTracing) GetProtocolParameters(ctx context.Context) (_ []byte, err error) {
+	ctx, span := StartSpan(ctx, "txProvider.GetProtocolParameters", p.attrs()...)
+	defer func() { EndSpan(span, err) }()
+
+	return p.provider.GetProtocolParameters(ctx)
+}
+
+func (p *TxProviderWithTracing) GetUtxos(ctx context.Context, addr string) (_ []wallet.Utxo, err error) {
+	ctx, span := StartSpan(ctx, "txProvider.GetUtxos", append(p.attrs(), attribute.String("addr", addr))...)
+	defer func() { EndSpan(span, err) }()
+
+	utxos, err := p.provider.GetUtxos(ctx, addr)
+
+	span.SetAttributes(attribute.Int("utxos", len(utxos)))
+
+	return utxos, err
+}
+
+func (p *TxProviderWithTracing) Dispose() {
+	p.provider.Dispose()
+}
+
+func (p *TxProviderWithTracing) attrs() []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("provider", p.providerType)}
+}
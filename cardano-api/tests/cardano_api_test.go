@@ -14,6 +14,7 @@ import (
 	"github.com/Ethernal-Tech/cardano-api/api/controllers"
 	"github.com/Ethernal-Tech/cardano-api/api/model/request"
 	"github.com/Ethernal-Tech/cardano-api/api/model/response"
+	utxotransformer "github.com/Ethernal-Tech/cardano-api/api/utxo_transformer"
 	"github.com/Ethernal-Tech/cardano-api/common"
 	"github.com/Ethernal-Tech/cardano-api/core"
 	infracommon "github.com/Ethernal-Tech/cardano-infrastructure/common"
@@ -63,7 +64,8 @@ func TestCardanoAPI(t *testing.T) {
 
 	apiControllers := []core.APIController{
 		controllers.NewCardanoTxController(
-			config, logger.Named("cardano_tx_controller"), validatorChangeTracker),
+			config, utxotransformer.NewUsedUtxoCacher(config.UtxoCacheTimeout), nil, nil,
+			logger.Named("cardano_tx_controller"), validatorChangeTracker, nil, nil),
 	}
 
 	apiObj, err := api.NewAPI(config.APIConfig, apiControllers, logger.Named("api"))
@@ -2,12 +2,12 @@ package validatorchange
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/Ethernal-Tech/cardano-api/api/model/response"
 	"github.com/Ethernal-Tech/cardano-api/common"
 	"github.com/Ethernal-Tech/cardano-api/core"
+	"github.com/Ethernal-Tech/cardano-api/oracle"
 	"github.com/hashicorp/go-hclog"
 )
 
@@ -17,17 +17,24 @@ type validatorChange struct {
 	logger                 hclog.Logger
 	appConfig              *core.AppConfig
 	validatorChangeTracker common.ValidatorChangeTracker
+	notifiers              []common.ValidatorChangeNotifier
+
+	// lastInProgress is the last status fetched from the oracle, nil until the first successful fetch
+	lastInProgress *bool
 }
 
 func NewValidatorChange(
 	ctx context.Context,
 	logger hclog.Logger,
 	appConfig *core.AppConfig,
-	tracker common.ValidatorChangeTracker) *validatorChange {
+	tracker common.ValidatorChangeTracker,
+	notifiers ...common.ValidatorChangeNotifier,
+) *validatorChange {
 	return &validatorChange{
 		logger:                 logger,
 		appConfig:              appConfig,
 		validatorChangeTracker: tracker,
+		notifiers:              notifiers,
 	}
 }
 
@@ -50,11 +57,15 @@ func (v *validatorChange) Start(ctx context.Context) {
 	}
 }
 
-func (v *validatorChange) setValidatorChangeStatus(ctx context.Context) error {
-	validatorChangeStatusRequestURL := fmt.Sprintf("%s/api/Settings/GetValidatorChangeStatus", v.appConfig.OracleAPI.URL)
+// SyncStatus fetches the validator change status (and multisig addresses if needed) once,
+// without starting the polling loop
+func (v *validatorChange) SyncStatus(ctx context.Context) error {
+	return v.setValidatorChangeStatus(ctx)
+}
 
-	validatorChangeStatusReponse, err := common.HTTPGet[*response.ValidatorChangeStatusReponse](
-		ctx, validatorChangeStatusRequestURL, v.appConfig.OracleAPI.APIKey)
+func (v *validatorChange) setValidatorChangeStatus(ctx context.Context) error {
+	validatorChangeStatusReponse, err := oracle.Get[*response.ValidatorChangeStatusReponse](
+		ctx, v.appConfig.GetOracleClient(), "/api/Settings/GetValidatorChangeStatus")
 	if err != nil {
 		return err
 	}
@@ -68,5 +79,14 @@ func (v *validatorChange) setValidatorChangeStatus(ctx context.Context) error {
 
 	v.validatorChangeTracker.SetValidatorChangeStatus(validatorChangeStatusReponse.InProgress)
 
+	inProgress := validatorChangeStatusReponse.InProgress
+	if v.lastInProgress != nil && *v.lastInProgress != inProgress {
+		for _, notifier := range v.notifiers {
+			notifier.NotifyValidatorChange(inProgress)
+		}
+	}
+
+	v.lastInProgress = &inProgress
+
 	return nil
 }
@@ -0,0 +1,70 @@
+package webhook
+
+import "time"
+
+const (
+	defaultTimeout             = 10 * time.Second
+	defaultMaxAttempts         = 10
+	defaultInitialBackoff      = 5 * time.Second
+	defaultMaxBackoff          = 10 * time.Minute
+	defaultTxPollInterval      = 30 * time.Second
+	defaultTxExpiryGracePeriod = 10 * time.Minute
+	defaultTxTrackTimeout      = 24 * time.Hour
+)
+
+type Config struct {
+	// StorePath is the file in which subscriptions, outbox, dead letters and tracked txs are persisted.
+	// Webhooks are disabled if empty
+	StorePath string `json:"storePath"`
+	// Timeout of a single delivery
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// MaxAttempts is the number of deliveries before the event is moved to the dead letters
+	MaxAttempts    int           `json:"maxAttempts,omitempty"`
+	InitialBackoff time.Duration `json:"initialBackoff,omitempty"`
+	MaxBackoff     time.Duration `json:"maxBackoff,omitempty"`
+	// TxPollInterval is how often the oracle is asked for the state of the tracked txs
+	TxPollInterval time.Duration `json:"txPollInterval,omitempty"`
+	// TxExpiryGracePeriod is added to the tx ttl before the tx not seen by the oracle is reported as expired
+	TxExpiryGracePeriod time.Duration `json:"txExpiryGracePeriod,omitempty"`
+	// TxTrackTimeout is how long the tx is tracked if it does not reach the final state
+	TxTrackTimeout time.Duration `json:"txTrackTimeout,omitempty"`
+	// AllowPrivateHosts allows http webhooks and webhooks on loopback, private and link-local addresses.
+	// It should be used only for local development
+	AllowPrivateHosts bool `json:"allowPrivateHosts,omitempty"`
+}
+
+func (config Config) IsEnabled() bool {
+	return config.StorePath != ""
+}
+
+func fillOutDefaults(config Config) Config {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaultInitialBackoff
+	}
+
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+
+	if config.TxPollInterval <= 0 {
+		config.TxPollInterval = defaultTxPollInterval
+	}
+
+	if config.TxExpiryGracePeriod <= 0 {
+		config.TxExpiryGracePeriod = defaultTxExpiryGracePeriod
+	}
+
+	if config.TxTrackTimeout <= 0 {
+		config.TxTrackTimeout = defaultTxTrackTimeout
+	}
+
+	return config
+}
@@ -0,0 +1,627 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/common"
+	"github.com/Ethernal-Tech/cardano-api/oracle"
+	"github.com/Ethernal-Tech/cardano-api/telemetry"
+	"github.com/hashicorp/go-hclog"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	EventIDHeader        = "X-Webhook-ID"
+	EventTypeHeader      = "X-Webhook-Event"
+	TimestampHeader      = "X-Webhook-Timestamp"
+	SignatureHeader      = "X-Webhook-Signature"
+	signaturePrefix      = "sha256="
+	secretLength         = 32
+	deliveryInterval     = time.Second
+	saveDelay            = time.Second
+	maxDeadLetters       = 1000
+	maxResponseBodyBytes = 4096
+
+	txStatusExecutedOnDestination = "ExecutedOnDestination"
+	txStatusInvalidRequest        = "InvalidRequest"
+)
+
+var ErrNotRegistered = errors.New("webhook is not registered for the api key")
+
+type bridgingRequestStateResponse struct {
+	Status   string `json:"status"`
+	IsRefund bool   `json:"isRefund"`
+}
+
+// Service delivers signed events to the webhooks registered per api key. Events are kept in the outbox
+// persisted in the store file until they are delivered or moved to the dead letters after MaxAttempts
+type Service struct {
+	config       Config
+	oracleClient *oracle.Client
+	httpClient   *http.Client
+	logger       hclog.Logger
+	now          func() time.Time
+	deliverCh    chan struct{}
+	saveCh       chan struct{}
+
+	lock  sync.Mutex
+	state *state
+	dirty bool
+
+	// saveLock orders the writes of the store, so the older state can not replace the newer one
+	saveLock sync.Mutex
+}
+
+func NewService(config Config, oracleClient *oracle.Client, logger hclog.Logger) (*Service, error) {
+	config = fillOutDefaults(config)
+
+	st, err := loadState(config.StorePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		config:       config,
+		oracleClient: oracleClient,
+		httpClient:   newHTTPClient(config),
+		logger:       logger,
+		now:          func() time.Time { return time.Now().UTC() },
+		deliverCh:    make(chan struct{}, 1),
+		saveCh:       make(chan struct{}, 1),
+		state:        st,
+	}, nil
+}
+
+// Start delivers the events, polls the state of the tracked txs and saves the changes of the store
+// until the context is done. Flush should be called afterwards to save the last changes
+func (s *Service) Start(ctx context.Context) {
+	go s.pollTrackedTxsLoop(ctx)
+	go s.saveLoop(ctx)
+
+	ticker := time.NewTicker(deliveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.deliverCh:
+		}
+
+		s.deliverDue(ctx)
+	}
+}
+
+// Register registers the webhook url for the api key and returns the subscription with newly generated secret.
+// Registering again changes the url and the secret
+func (s *Service) Register(apiKeyID string, webhookURL string) (Subscription, error) {
+	secret := make([]byte, secretLength)
+	_, _ = rand.Read(secret)
+
+	subscription := Subscription{
+		APIKeyID:  apiKeyID,
+		URL:       webhookURL,
+		Secret:    hex.EncodeToString(secret),
+		CreatedAt: s.now(),
+	}
+
+	s.lock.Lock()
+	s.state.Subscriptions[apiKeyID] = &subscription
+	s.markDirty()
+	s.lock.Unlock()
+
+	return subscription, s.Flush()
+}
+
+// Unregister removes the webhook of the api key together with its pending events and tracked txs
+func (s *Service) Unregister(apiKeyID string) error {
+	s.lock.Lock()
+
+	if _, exists := s.state.Subscriptions[apiKeyID]; !exists {
+		s.lock.Unlock()
+
+		return ErrNotRegistered
+	}
+
+	delete(s.state.Subscriptions, apiKeyID)
+
+	isOfAPIKey := func(d *Delivery) bool { return d.APIKeyID == apiKeyID }
+	s.state.Outbox = slices.DeleteFunc(s.state.Outbox, isOfAPIKey)
+	s.state.DeadLetters = slices.DeleteFunc(s.state.DeadLetters, isOfAPIKey)
+	s.state.TrackedTxs = slices.DeleteFunc(s.state.TrackedTxs, func(tx *TrackedTx) bool {
+		return tx.APIKeyID == apiKeyID
+	})
+
+	s.markDirty()
+	s.lock.Unlock()
+
+	return s.Flush()
+}
+
+// GetSubscription returns the subscription of the api key and the number of pending events, dead letters
+// and tracked txs
+func (s *Service) GetSubscription(apiKeyID string) (
+	subscription Subscription, pending int, deadLetters int, trackedTxs int, err error,
+) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	existing, exists := s.state.Subscriptions[apiKeyID]
+	if !exists {
+		return subscription, 0, 0, 0, ErrNotRegistered
+	}
+
+	for _, d := range s.state.Outbox {
+		if d.APIKeyID == apiKeyID {
+			pending++
+		}
+	}
+
+	for _, d := range s.state.DeadLetters {
+		if d.APIKeyID == apiKeyID {
+			deadLetters++
+		}
+	}
+
+	for _, tx := range s.state.TrackedTxs {
+		if tx.APIKeyID == apiKeyID {
+			trackedTxs++
+		}
+	}
+
+	return *existing, pending, deadLetters, trackedTxs, nil
+}
+
+func (s *Service) GetDeadLetters(apiKeyID string) []Delivery {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	result := []Delivery{}
+
+	for _, d := range s.state.DeadLetters {
+		if d.APIKeyID == apiKeyID {
+			result = append(result, *d)
+		}
+	}
+
+	return result
+}
+
+// RedeliverDeadLetters moves the dead letters of the api key back to the outbox
+func (s *Service) RedeliverDeadLetters(apiKeyID string) (int, error) {
+	s.lock.Lock()
+
+	if _, exists := s.state.Subscriptions[apiKeyID]; !exists {
+		s.lock.Unlock()
+
+		return 0, ErrNotRegistered
+	}
+
+	count := 0
+	now := s.now()
+
+	s.state.DeadLetters = slices.DeleteFunc(s.state.DeadLetters, func(d *Delivery) bool {
+		if d.APIKeyID != apiKeyID {
+			return false
+		}
+
+		d.Attempts = 0
+		d.NextAttemptAt = now
+		s.state.Outbox = append(s.state.Outbox, d)
+		count++
+
+		return true
+	})
+
+	if count > 0 {
+		s.wakeUpDelivery()
+		s.markDirty()
+	}
+
+	s.lock.Unlock()
+
+	return count, s.Flush()
+}
+
+// TrackTx starts tracking the state of the bridging tx if the api key has registered webhook.
+// ttl is the time after which the tx can not be included in a block anymore. Service can be nil
+func (s *Service) TrackTx(apiKeyID string, chainID string, txHash string, ttl time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, exists := s.state.Subscriptions[apiKeyID]; !exists {
+		return
+	}
+
+	now := s.now()
+
+	s.state.TrackedTxs = append(s.state.TrackedTxs, &TrackedTx{
+		APIKeyID:   apiKeyID,
+		ChainID:    chainID,
+		TxHash:     txHash,
+		ExpiresAt:  now.Add(ttl + s.config.TxExpiryGracePeriod),
+		TrackUntil: now.Add(max(s.config.TxTrackTimeout, ttl+s.config.TxExpiryGracePeriod)),
+	})
+
+	s.markDirty()
+}
+
+// NotifyValidatorChange sends validator change event to all the webhooks. Service can be nil
+func (s *Service) NotifyValidatorChange(inProgress bool) {
+	if s == nil {
+		return
+	}
+
+	eventType := EventValidatorChangeCompleted
+	if inProgress {
+		eventType = EventValidatorChangeStarted
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for apiKeyID := range s.state.Subscriptions {
+		s.enqueue(apiKeyID, Event{
+			Type:            eventType,
+			ValidatorChange: &ValidatorChangeEventData{InProgress: inProgress},
+		})
+	}
+
+	s.markDirty()
+}
+
+func (s *Service) pollTrackedTxsLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.config.TxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.pollTrackedTxs(ctx)
+		}
+	}
+}
+
+func (s *Service) pollTrackedTxs(ctx context.Context) {
+	s.lock.Lock()
+	trackedTxs := make([]TrackedTx, len(s.state.TrackedTxs))
+
+	for i, tx := range s.state.TrackedTxs {
+		trackedTxs[i] = *tx
+	}
+	s.lock.Unlock()
+
+	for _, tx := range trackedTxs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		stateResponse, err := oracle.Get[*bridgingRequestStateResponse](ctx, s.oracleClient, fmt.Sprintf(
+			"/api/BridgingRequestState/Get?chainId=%s&txHash=%s", url.QueryEscape(tx.ChainID), url.QueryEscape(tx.TxHash)))
+		if err != nil {
+			// oracle responds with client error if it has not seen the tx yet
+			var httpErr *oracle.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode >= http.StatusInternalServerError {
+				s.logger.Error("failed to fetch bridging request state", "chainID", tx.ChainID, "txHash", tx.TxHash, "err", err)
+
+				continue
+			}
+
+			stateResponse = nil
+		}
+
+		s.updateTrackedTx(tx, stateResponse)
+	}
+}
+
+// updateTrackedTx creates the events for the changed state of the tx and stops tracking the tx if it is
+// in the final state, expired or tracked for too long. stateResponse is nil if the tx is not known to the oracle
+func (s *Service) updateTrackedTx(tx TrackedTx, stateResponse *bridgingRequestStateResponse) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	idx := slices.IndexFunc(s.state.TrackedTxs, func(x *TrackedTx) bool {
+		return x.APIKeyID == tx.APIKeyID && x.ChainID == tx.ChainID && x.TxHash == tx.TxHash
+	})
+	if idx == -1 {
+		return
+	}
+
+	trackedTx := s.state.TrackedTxs[idx]
+	now := s.now()
+	stopTracking := now.After(trackedTx.TrackUntil)
+
+	switch {
+	case stateResponse == nil:
+		if trackedTx.Status == "" && now.After(trackedTx.ExpiresAt) {
+			s.enqueueTxEvent(trackedTx, EventTxExpired)
+
+			stopTracking = true
+		}
+	case stateResponse.Status != trackedTx.Status || stateResponse.IsRefund != trackedTx.IsRefund:
+		wasConfirmed, wasRefund := trackedTx.Status != "", trackedTx.IsRefund
+		statusChanged := stateResponse.Status != trackedTx.Status
+
+		trackedTx.Status = stateResponse.Status
+		trackedTx.IsRefund = stateResponse.IsRefund
+
+		if !wasConfirmed {
+			s.enqueueTxEvent(trackedTx, EventTxConfirmed)
+		} else if statusChanged {
+			s.enqueueTxEvent(trackedTx, EventTxStatusChanged)
+		}
+
+		if trackedTx.IsRefund && !wasRefund {
+			s.enqueueTxEvent(trackedTx, EventTxRefunded)
+		}
+
+		stopTracking = stopTracking || trackedTx.Status == txStatusExecutedOnDestination ||
+			trackedTx.Status == txStatusInvalidRequest
+	}
+
+	if stopTracking {
+		s.state.TrackedTxs = slices.Delete(s.state.TrackedTxs, idx, idx+1)
+	}
+
+	s.markDirty()
+}
+
+func (s *Service) enqueueTxEvent(tx *TrackedTx, eventType string) {
+	s.enqueue(tx.APIKeyID, Event{
+		Type: eventType,
+		Tx: &TxEventData{
+			ChainID:  tx.ChainID,
+			TxHash:   tx.TxHash,
+			Status:   tx.Status,
+			IsRefund: tx.IsRefund,
+		},
+	})
+}
+
+// enqueue adds the event to the outbox if the api key has registered webhook. Lock must be held
+func (s *Service) enqueue(apiKeyID string, event Event) {
+	if _, exists := s.state.Subscriptions[apiKeyID]; !exists {
+		return
+	}
+
+	now := s.now()
+	event.ID = common.NewRequestID()
+	event.CreatedAt = now
+
+	s.state.Outbox = append(s.state.Outbox, &Delivery{
+		Event:         event,
+		APIKeyID:      apiKeyID,
+		NextAttemptAt: now,
+	})
+
+	s.wakeUpDelivery()
+}
+
+func (s *Service) wakeUpDelivery() {
+	select {
+	case s.deliverCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) deliverDue(ctx context.Context) {
+	type dueDelivery struct {
+		delivery     Delivery
+		subscription Subscription
+	}
+
+	var due []dueDelivery
+
+	s.lock.Lock()
+
+	now := s.now()
+
+	for _, d := range s.state.Outbox {
+		if subscription, exists := s.state.Subscriptions[d.APIKeyID]; exists && !d.NextAttemptAt.After(now) {
+			due = append(due, dueDelivery{delivery: *d, subscription: *subscription})
+		}
+	}
+	s.lock.Unlock()
+
+	for _, x := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := s.deliver(ctx, x.subscription, x.delivery.Event)
+		if err != nil {
+			s.logger.Debug("failed to deliver webhook event",
+				"apiKeyID", x.delivery.APIKeyID, "eventID", x.delivery.Event.ID, "err", err)
+		}
+
+		s.completeDelivery(x.delivery.Event.ID, err)
+	}
+}
+
+// completeDelivery removes delivered event from the outbox or schedules the next attempt.
+// Event is moved to the dead letters after MaxAttempts
+func (s *Service) completeDelivery(eventID string, deliveryErr error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	idx := slices.IndexFunc(s.state.Outbox, func(d *Delivery) bool { return d.Event.ID == eventID })
+	if idx == -1 {
+		return // unregistered in the meantime
+	}
+
+	delivery := s.state.Outbox[idx]
+
+	if deliveryErr != nil {
+		delivery.Attempts++
+		delivery.LastError = deliveryErr.Error()
+
+		if delivery.Attempts < s.config.MaxAttempts {
+			delivery.NextAttemptAt = s.now().Add(s.getBackoff(delivery.Attempts))
+
+			s.markDirty()
+
+			return
+		}
+
+		s.logger.Warn("webhook event moved to dead letters",
+			"apiKeyID", delivery.APIKeyID, "eventID", eventID, "err", deliveryErr)
+
+		s.state.DeadLetters = append(s.state.DeadLetters, delivery)
+		if len(s.state.DeadLetters) > maxDeadLetters {
+			s.state.DeadLetters = s.state.DeadLetters[len(s.state.DeadLetters)-maxDeadLetters:]
+		}
+	}
+
+	s.state.Outbox = slices.Delete(s.state.Outbox, idx, idx+1)
+
+	s.markDirty()
+}
+
+func (s *Service) deliver(ctx context.Context, subscription Subscription, event Event) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "webhook.Deliver",
+		attribute.String("apiKeyID", subscription.APIKeyID), attribute.String("eventType", event.Type))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, event.ID)
+	req.Header.Set(EventTypeHeader, event.Type)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, signaturePrefix+Sign(subscription.Secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBodyBytes))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// getBackoff returns the delay before the next attempt which doubles with each failed attempt
+func (s *Service) getBackoff(attempts int) time.Duration {
+	backoff := s.config.InitialBackoff
+
+	for i := 1; i < attempts && backoff < s.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, s.config.MaxBackoff)
+}
+
+// markDirty marks the state as changed and wakes up the save loop, so many changes are written to the store
+// at once instead of on every change. Lock must be held
+func (s *Service) markDirty() {
+	s.dirty = true
+
+	select {
+	case s.saveCh <- struct{}{}:
+	default:
+	}
+}
+
+// saveLoop saves the changed state at most once per saveDelay until the context is done
+func (s *Service) saveLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.saveCh:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(saveDelay):
+		}
+
+		if err := s.Flush(); err != nil {
+			s.logger.Error("failed to save webhook store", "err", err)
+		}
+	}
+}
+
+// Flush writes the state to the store if it has been changed since the last write.
+// The state is encoded under the lock, but written to the disk without holding it
+func (s *Service) Flush() error {
+	s.saveLock.Lock()
+	defer s.saveLock.Unlock()
+
+	s.lock.Lock()
+
+	if !s.dirty {
+		s.lock.Unlock()
+
+		return nil
+	}
+
+	stateBytes, err := json.Marshal(s.state)
+	s.dirty = err != nil
+
+	s.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if err := saveState(s.config.StorePath, stateBytes); err != nil {
+		s.lock.Lock()
+		s.dirty = true
+		s.lock.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// Sign returns hex encoded HMAC-SHA256 of the timestamp and the body joined with a dot.
+// Receivers should compare it with the X-Webhook-Signature header (without sha256= prefix)
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,363 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/cardano-api/oracle"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedEvent struct {
+	event     Event
+	signature string
+	timestamp string
+	body      []byte
+}
+
+type webhookReceiver struct {
+	*httptest.Server
+
+	lock   sync.Mutex
+	status int
+	events []receivedEvent
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	t.Helper()
+
+	receiver := &webhookReceiver{status: http.StatusOK}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var event Event
+
+		require.NoError(t, json.Unmarshal(body, &event))
+		require.Equal(t, event.ID, r.Header.Get(EventIDHeader))
+		require.Equal(t, event.Type, r.Header.Get(EventTypeHeader))
+
+		receiver.lock.Lock()
+		defer receiver.lock.Unlock()
+
+		receiver.events = append(receiver.events, receivedEvent{
+			event:     event,
+			signature: r.Header.Get(SignatureHeader),
+			timestamp: r.Header.Get(TimestampHeader),
+			body:      body,
+		})
+
+		w.WriteHeader(receiver.status)
+	}))
+
+	t.Cleanup(receiver.Close)
+
+	return receiver
+}
+
+func (r *webhookReceiver) setStatus(status int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.status = status
+}
+
+func (r *webhookReceiver) getEvents() []receivedEvent {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]receivedEvent(nil), r.events...)
+}
+
+type oracleMock struct {
+	*httptest.Server
+
+	lock   sync.Mutex
+	states map[string]bridgingRequestStateResponse
+}
+
+func newOracleMock(t *testing.T) *oracleMock {
+	t.Helper()
+
+	mock := &oracleMock{states: map[string]bridgingRequestStateResponse{}}
+	mock.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/BridgingRequestState/Get", r.URL.Path)
+
+		mock.lock.Lock()
+		defer mock.lock.Unlock()
+
+		state, exists := mock.states[r.URL.Query().Get("chainId")+"/"+r.URL.Query().Get("txHash")]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(state)
+	}))
+
+	t.Cleanup(mock.Close)
+
+	return mock
+}
+
+func (m *oracleMock) setState(chainID, txHash, status string, isRefund bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.states[chainID+"/"+txHash] = bridgingRequestStateResponse{Status: status, IsRefund: isRefund}
+}
+
+func newTestService(t *testing.T, storePath string, oracleURL string) *Service {
+	t.Helper()
+
+	service, err := NewService(Config{
+		StorePath:      storePath,
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Hour,
+		TxTrackTimeout: 2 * time.Hour,
+		// test receivers listen on the loopback address
+		AllowPrivateHosts: true,
+	}, oracle.NewClient(oracle.Config{URL: oracleURL, MaxAttempts: 1}), hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	return service
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("tx lifecycle events are signed and delivered", func(t *testing.T) {
+		receiver, oracleMock := newWebhookReceiver(t), newOracleMock(t)
+		service := newTestService(t, filepath.Join(t.TempDir(), "webhooks.json"), oracleMock.URL)
+
+		subscription, err := service.Register("key1", receiver.URL)
+		require.NoError(t, err)
+		require.Len(t, subscription.Secret, 2*secretLength)
+
+		service.TrackTx("key1", "prime", "tx1", time.Hour)
+		service.TrackTx("not registered", "prime", "tx2", time.Hour)
+
+		service.pollTrackedTxs(ctx)
+		service.deliverDue(ctx)
+		require.Empty(t, receiver.getEvents())
+
+		oracleMock.setState("prime", "tx1", "SubmittedToBridge", false)
+		service.pollTrackedTxs(ctx)
+
+		oracleMock.setState("prime", "tx1", "FailedToExecuteOnDestination", true)
+		service.pollTrackedTxs(ctx)
+
+		oracleMock.setState("prime", "tx1", txStatusExecutedOnDestination, true)
+		service.pollTrackedTxs(ctx)
+		service.deliverDue(ctx)
+
+		events := receiver.getEvents()
+		require.Len(t, events, 4)
+
+		for i, expected := range []struct {
+			eventType string
+			status    string
+		}{
+			{EventTxConfirmed, "SubmittedToBridge"},
+			{EventTxStatusChanged, "FailedToExecuteOnDestination"},
+			{EventTxRefunded, "FailedToExecuteOnDestination"},
+			{EventTxStatusChanged, txStatusExecutedOnDestination},
+		} {
+			require.Equal(t, expected.eventType, events[i].event.Type)
+			require.Equal(t, expected.status, events[i].event.Tx.Status)
+			require.Equal(t, "tx1", events[i].event.Tx.TxHash)
+			require.Equal(t, signaturePrefix+Sign(subscription.Secret, events[i].timestamp, events[i].body),
+				events[i].signature)
+		}
+
+		_, pending, deadLetters, trackedTxs, err := service.GetSubscription("key1")
+		require.NoError(t, err)
+		require.Zero(t, pending)
+		require.Zero(t, deadLetters)
+		require.Zero(t, trackedTxs)
+	})
+
+	t.Run("expired tx", func(t *testing.T) {
+		receiver, oracleMock := newWebhookReceiver(t), newOracleMock(t)
+		service := newTestService(t, filepath.Join(t.TempDir(), "webhooks.json"), oracleMock.URL)
+
+		_, err := service.Register("key1", receiver.URL)
+		require.NoError(t, err)
+
+		service.TrackTx("key1", "vector", "tx1", time.Minute)
+
+		now := time.Now().UTC().Add(time.Minute + defaultTxExpiryGracePeriod + time.Second)
+		service.now = func() time.Time { return now }
+
+		service.pollTrackedTxs(ctx)
+		service.deliverDue(ctx)
+
+		events := receiver.getEvents()
+		require.Len(t, events, 1)
+		require.Equal(t, EventTxExpired, events[0].event.Type)
+		require.Equal(t, "vector", events[0].event.Tx.ChainID)
+	})
+
+	t.Run("retries, dead letters and persistence", func(t *testing.T) {
+		receiver := newWebhookReceiver(t)
+		receiver.setStatus(http.StatusServiceUnavailable)
+
+		storePath := filepath.Join(t.TempDir(), "webhooks.json")
+		service := newTestService(t, storePath, "http://localhost")
+		now := time.Now().UTC()
+		service.now = func() time.Time { return now }
+
+		_, err := service.Register("key1", receiver.URL)
+		require.NoError(t, err)
+
+		service.NotifyValidatorChange(true)
+		service.deliverDue(ctx)
+		require.Len(t, receiver.getEvents(), 1)
+
+		// next attempt is not due yet
+		service.deliverDue(ctx)
+		require.Len(t, receiver.getEvents(), 1)
+
+		// outbox survives restart
+		require.NoError(t, service.Flush())
+
+		service = newTestService(t, storePath, "http://localhost")
+		service.now = func() time.Time { return now }
+
+		for i := 0; i < 2; i++ {
+			now = now.Add(time.Hour)
+			service.deliverDue(ctx)
+		}
+
+		require.Len(t, receiver.getEvents(), 3)
+
+		deadLetters := service.GetDeadLetters("key1")
+		require.Len(t, deadLetters, 1)
+		require.Equal(t, EventValidatorChangeStarted, deadLetters[0].Event.Type)
+		require.Equal(t, 3, deadLetters[0].Attempts)
+		require.Contains(t, deadLetters[0].LastError, "503")
+		require.Empty(t, service.GetDeadLetters("key2"))
+
+		receiver.setStatus(http.StatusNoContent)
+
+		redelivered, err := service.RedeliverDeadLetters("key1")
+		require.NoError(t, err)
+		require.Equal(t, 1, redelivered)
+
+		service.deliverDue(ctx)
+
+		events := receiver.getEvents()
+		require.Len(t, events, 4)
+		require.Equal(t, events[0].event.ID, events[3].event.ID)
+		require.Empty(t, service.GetDeadLetters("key1"))
+	})
+
+	t.Run("unregister", func(t *testing.T) {
+		storePath := filepath.Join(t.TempDir(), "webhooks.json")
+		service := newTestService(t, storePath, "http://localhost")
+
+		require.ErrorIs(t, service.Unregister("key1"), ErrNotRegistered)
+
+		_, err := service.Register("key1", "http://localhost:1234")
+		require.NoError(t, err)
+
+		service.TrackTx("key1", "prime", "tx1", time.Hour)
+		service.NotifyValidatorChange(false)
+
+		require.NoError(t, service.Unregister("key1"))
+
+		_, _, _, _, err = service.GetSubscription("key1")
+		require.ErrorIs(t, err, ErrNotRegistered)
+
+		st, err := loadState(storePath)
+		require.NoError(t, err)
+		require.Empty(t, st.Subscriptions)
+		require.Empty(t, st.Outbox)
+		require.Empty(t, st.TrackedTxs)
+	})
+
+	t.Run("batched saves", func(t *testing.T) {
+		storePath := filepath.Join(t.TempDir(), "webhooks.json")
+		service := newTestService(t, storePath, "http://localhost")
+
+		_, err := service.Register("key1", "http://localhost:1234")
+		require.NoError(t, err)
+
+		// registration is written immediately
+		st, err := loadState(storePath)
+		require.NoError(t, err)
+		require.Len(t, st.Subscriptions, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go service.saveLoop(ctx)
+
+		for i := 0; i < 100; i++ {
+			service.TrackTx("key1", "prime", fmt.Sprintf("tx%d", i), time.Hour)
+		}
+
+		// tracked txs are written together by the save loop
+		st, err = loadState(storePath)
+		require.NoError(t, err)
+		require.Empty(t, st.TrackedTxs)
+
+		require.Eventually(t, func() bool {
+			st, err := loadState(storePath)
+
+			return err == nil && len(st.TrackedTxs) == 100
+		}, 5*saveDelay, saveDelay/10)
+
+		cancel()
+
+		service.NotifyValidatorChange(true)
+		require.NoError(t, service.Flush())
+
+		st, err = loadState(storePath)
+		require.NoError(t, err)
+		require.Len(t, st.Outbox, 1)
+
+		files, err := os.ReadDir(filepath.Dir(storePath))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+	})
+
+	t.Run("nil service", func(t *testing.T) {
+		var service *Service
+
+		service.TrackTx("key1", "prime", "tx1", time.Hour)
+		service.NotifyValidatorChange(true)
+	})
+}
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", "1700000000", []byte(`{"id":"1"}`))
+
+	require.Len(t, signature, 64)
+	require.Equal(t, strings.ToLower(signature), signature)
+	require.NotEqual(t, signature, Sign("secret", "1700000001", []byte(`{"id":"1"}`)))
+	require.NotEqual(t, signature, Sign("other", "1700000000", []byte(`{"id":"1"}`)))
+}
+
+func TestGetBackoff(t *testing.T) {
+	service := &Service{config: Config{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+
+	require.Equal(t, time.Second, service.getBackoff(1))
+	require.Equal(t, 2*time.Second, service.getBackoff(2))
+	require.Equal(t, 4*time.Second, service.getBackoff(3))
+	require.Equal(t, 5*time.Second, service.getBackoff(4))
+	require.Equal(t, 5*time.Second, service.getBackoff(100))
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	EventTxConfirmed              = "bridgingTx.confirmed"
+	EventTxStatusChanged          = "bridgingTx.statusChanged"
+	EventTxRefunded               = "bridgingTx.refunded"
+	EventTxExpired                = "bridgingTx.expired"
+	EventValidatorChangeStarted   = "validatorChange.started"
+	EventValidatorChangeCompleted = "validatorChange.completed"
+)
+
+type Subscription struct {
+	APIKeyID  string    `json:"apiKeyId"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type TxEventData struct {
+	ChainID  string `json:"chainId"`
+	TxHash   string `json:"txHash"`
+	Status   string `json:"status,omitempty"`
+	IsRefund bool   `json:"isRefund"`
+}
+
+type ValidatorChangeEventData struct {
+	InProgress bool `json:"inProgress"`
+}
+
+// Event is the json body of the webhook request
+type Event struct {
+	ID              string                    `json:"id"`
+	Type            string                    `json:"type"`
+	CreatedAt       time.Time                 `json:"createdAt"`
+	Tx              *TxEventData              `json:"tx,omitempty"`
+	ValidatorChange *ValidatorChangeEventData `json:"validatorChange,omitempty"`
+}
+
+// Delivery is the event waiting to be delivered to the subscription of the api key (or the dead letter)
+type Delivery struct {
+	Event         Event     `json:"event"`
+	APIKeyID      string    `json:"apiKeyId"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+	LastError     string    `json:"lastError,omitempty"`
+}
+
+type TrackedTx struct {
+	APIKeyID   string    `json:"apiKeyId"`
+	ChainID    string    `json:"chainId"`
+	TxHash     string    `json:"txHash"`
+	Status     string    `json:"status,omitempty"`
+	IsRefund   bool      `json:"isRefund"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	TrackUntil time.Time `json:"trackUntil"`
+}
+
+type state struct {
+	Subscriptions map[string]*Subscription `json:"subscriptions"`
+	Outbox        []*Delivery              `json:"outbox"`
+	DeadLetters   []*Delivery              `json:"deadLetters"`
+	TrackedTxs    []*TrackedTx             `json:"trackedTxs"`
+}
+
+// loadState reads the state from the file. Empty state is returned if the file does not exist
+func loadState(path string) (*state, error) {
+	st := &state{}
+
+	bytes, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read webhook store: %w", err)
+	} else if err == nil {
+		if err := json.Unmarshal(bytes, st); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook store: %w", err)
+		}
+	}
+
+	if st.Subscriptions == nil {
+		st.Subscriptions = map[string]*Subscription{}
+	}
+
+	return st, nil
+}
+
+// saveState writes the state into the temporary file which is synced to the disk and then replaces the old one,
+// so the store is never left half written
+func saveState(path string, bytes []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create webhook store: %w", err)
+	}
+
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(bytes); err != nil {
+		tmpFile.Close()
+
+		return fmt.Errorf("failed to write webhook store: %w", err)
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+
+		return fmt.Errorf("failed to sync webhook store: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write webhook store: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace webhook store: %w", err)
+	}
+
+	// sync the directory as well, otherwise the rename itself could be lost on crash
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("failed to sync webhook store directory: %w", err)
+	}
+
+	defer dir.Close()
+
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("failed to sync webhook store directory: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const dialTimeout = 10 * time.Second
+
+var ErrInvalidURL = errors.New("invalid webhook url")
+
+// notAllowedPrefixes are ranges which are not covered by netip.Addr methods but must not be reachable either
+var notAllowedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// ValidateURL checks that the webhook url is https and that its host does not resolve to loopback, private,
+// link-local or other internal address, so api keys can not use webhooks to reach the internal network.
+// Both checks are skipped if AllowPrivateHosts is set
+func (s *Service) ValidateURL(ctx context.Context, webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Host == "" || u.User != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidURL, webhookURL)
+	}
+
+	if s.config.AllowPrivateHosts {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("%w: %s", ErrInvalidURL, webhookURL)
+		}
+
+		return nil
+	}
+
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: https is required: %s", ErrInvalidURL, webhookURL)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: failed to resolve %s: %w", ErrInvalidURL, u.Hostname(), err)
+	}
+
+	for _, addr := range addrs {
+		if !isAllowedAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to not allowed address %s", ErrInvalidURL, u.Hostname(), addr)
+		}
+	}
+
+	return nil
+}
+
+func isAllowedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range notAllowedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newHTTPClient creates the delivery client. Unless private hosts are allowed, connections to not allowed
+// addresses are refused when dialing, so the host can not be changed to the internal address after it is registered
+func newHTTPClient(config Config) *http.Client {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+
+	if !config.AllowPrivateHosts {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+
+			if !isAllowedAddr(addrPort.Addr()) {
+				return fmt.Errorf("connection to not allowed address %s", addrPort.Addr())
+			}
+
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &http.Client{Timeout: config.Timeout, Transport: transport}
+}